@@ -6,19 +6,32 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"drakor-backend/internal/actor"
+	"drakor-backend/internal/advisory"
 	"drakor-backend/internal/analytics"
 	"drakor-backend/internal/auth"
+	"drakor-backend/internal/collection"
 	"drakor-backend/internal/comment"
+	"drakor-backend/internal/company"
 	"drakor-backend/internal/drama"
 	"drakor-backend/internal/episode"
+	"drakor-backend/internal/export"
 	"drakor-backend/internal/genre"
 	"drakor-backend/internal/history"
+	"drakor-backend/internal/home"
+	"drakor-backend/internal/importer"
+	"drakor-backend/internal/media"
+	"drakor-backend/internal/metadata"
 	"drakor-backend/internal/review"
+	"drakor-backend/internal/schedule"
 	"drakor-backend/internal/season"
+	"drakor-backend/internal/tag"
+	"drakor-backend/internal/trash"
 	"drakor-backend/internal/watchlist"
 	"drakor-backend/pkg/database"
 
@@ -58,7 +71,7 @@ func main() {
 	// CORS configuration
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000", "http://localhost:5173", "*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
@@ -92,16 +105,42 @@ func main() {
 	genreService := genre.NewService(genreRepo)
 	genreHandler := genre.NewHandler(genreService)
 
+	// Initialize Tag dependencies
+	tagRepo := tag.NewRepository()
+	tagService := tag.NewService(tagRepo)
+	tagHandler := tag.NewHandler(tagService)
+
+	// Initialize Advisory dependencies
+	advisoryRepo := advisory.NewRepository()
+	advisoryService := advisory.NewService(advisoryRepo)
+	advisoryHandler := advisory.NewHandler(advisoryService)
+
 	// Initialize Actor dependencies
 	actorRepo := actor.NewRepository()
 	actorService := actor.NewService(actorRepo)
 	actorHandler := actor.NewHandler(actorService)
 
+	// Initialize Company dependencies (networks and production companies)
+	companyRepo := company.NewRepository()
+	companyService := company.NewService(companyRepo)
+	networkHandler := company.NewHandler(companyService, "network", "Network")
+	productionHandler := company.NewHandler(companyService, "production", "Production company")
+
 	// Initialize Drama dependencies
 	dramaRepo := drama.NewRepository()
 	dramaService := drama.NewService(dramaRepo)
 	dramaHandler := drama.NewHandler(dramaService)
 
+	// Initialize Media dependencies
+	mediaRepo := media.NewRepository()
+	mediaService := media.NewService(mediaRepo)
+	mediaHandler := media.NewHandler(mediaService)
+
+	// Initialize Schedule dependencies
+	scheduleRepo := schedule.NewRepository(drama.ChangeStatus)
+	scheduleService := schedule.NewService(scheduleRepo)
+	scheduleHandler := schedule.NewHandler(scheduleService)
+
 	// Initialize Season dependencies
 	seasonRepo := season.NewRepository()
 	seasonService := season.NewService(seasonRepo)
@@ -137,6 +176,65 @@ func main() {
 	analyticsService := analytics.NewService(analyticsRepo)
 	analyticsHandler := analytics.NewHandler(analyticsService)
 
+	// Initialize Trash dependencies
+	retentionDays, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil || retentionDays < 1 {
+		retentionDays = 30
+	}
+	trashRepo := trash.NewRepository()
+	trashService := trash.NewService(trashRepo, time.Duration(retentionDays)*24*time.Hour)
+	trashHandler := trash.NewHandler(trashService)
+
+	// Initialize Import dependencies
+	importRepo := importer.NewRepository()
+	importService := importer.NewService(importRepo)
+	importHandler := importer.NewHandler(importService)
+
+	// Initialize Export dependencies
+	exportRepo := export.NewRepository()
+	exportService := export.NewService(exportRepo)
+	exportHandler := export.NewHandler(exportService)
+
+	// Initialize Collection dependencies
+	collectionRepo := collection.NewRepository()
+	collectionService := collection.NewService(collectionRepo)
+	collectionHandler := collection.NewHandler(collectionService)
+
+	// Initialize Home dependencies
+	homeConfig := home.Config{}
+	if rows := os.Getenv("HOME_ROWS"); rows != "" {
+		homeConfig.Rows = strings.Split(rows, ",")
+	}
+	if ms, err := strconv.Atoi(os.Getenv("HOME_ROW_TIMEOUT_MS")); err == nil && ms > 0 {
+		homeConfig.RowTimeout = time.Duration(ms) * time.Millisecond
+	}
+	homeRepo := home.NewRepository()
+	homeService := home.NewService(homeRepo, dramaService, watchlistService, collectionService, homeConfig)
+	homeHandler := home.NewHandler(homeService)
+
+	// Initialize Metadata dependencies
+	tmdbBaseURL := os.Getenv("TMDB_BASE_URL")
+	if tmdbBaseURL == "" {
+		tmdbBaseURL = "https://api.themoviedb.org/3"
+	}
+	tmdbImageBaseURL := os.Getenv("TMDB_IMAGE_BASE_URL")
+	if tmdbImageBaseURL == "" {
+		tmdbImageBaseURL = "https://image.tmdb.org/t/p/original"
+	}
+	metadataRepo := metadata.NewRepository()
+	metadataService := metadata.NewService(metadataRepo, dramaRepo, map[string]metadata.Provider{
+		"tmdb": metadata.NewTMDB(tmdbBaseURL, tmdbImageBaseURL, os.Getenv("TMDB_API_KEY")),
+	})
+	metadataHandler := metadata.NewHandler(metadataService)
+
+	// Background jobs stop when the server shuts down
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	trashService.StartPurgeJob(jobsCtx, time.Hour)
+	dramaService.StartScheduler(jobsCtx, time.Minute)
+	episodeService.StartScheduler(jobsCtx, time.Minute)
+	scheduleService.StartCompletionJob(jobsCtx, 10*time.Minute)
+
 	// API routes group
 	api := r.Group("/api")
 	{
@@ -147,23 +245,78 @@ func main() {
 			})
 		})
 
+		// --- HOME Routes ---
+		// Public (personalized when a token is sent)
+		api.GET("/home", auth.OptionalMiddleware(), homeHandler.Get)
+
 		// --- GENRE Routes ---
 		// Public
 		api.GET("/genres", genreHandler.GetAll)
+		api.GET("/genres/:id", genreHandler.GetByID)
 
 		// Admin
 		genreGroup := api.Group("/genres")
 		genreGroup.Use(auth.Middleware(), auth.AdminMiddleware())
 		{
 			genreGroup.POST("", genreHandler.Create)
+			genreGroup.PUT("/order", genreHandler.Reorder)
 			genreGroup.PUT("/:id", genreHandler.Update)
+			genreGroup.PATCH("/:id", genreHandler.Patch)
 			genreGroup.DELETE("/:id", genreHandler.Delete)
 		}
 
+		// --- TAG Routes ---
+		// Public
+		api.GET("/tags", tagHandler.GetAll)
+		api.GET("/tags/categories", tagHandler.GetCategories)
+		api.GET("/tags/autocomplete", tagHandler.Autocomplete)
+		api.GET("/tags/:id", tagHandler.GetByID)
+
+		// Admin
+		tagGroup := api.Group("/tags")
+		tagGroup.Use(auth.Middleware(), auth.AdminMiddleware())
+		{
+			tagGroup.POST("", tagHandler.Create)
+			tagGroup.PUT("/:id", tagHandler.Update)
+			tagGroup.PATCH("/:id", tagHandler.Patch)
+			tagGroup.DELETE("/:id", tagHandler.Delete)
+		}
+
+		// --- ADVISORY Routes ---
+		// Public
+		api.GET("/advisories", advisoryHandler.GetAll)
+		api.GET("/advisories/:id", advisoryHandler.GetByID)
+
+		// Admin
+		advisoryGroup := api.Group("/advisories")
+		advisoryGroup.Use(auth.Middleware(), auth.AdminMiddleware())
+		{
+			advisoryGroup.POST("", advisoryHandler.Create)
+			advisoryGroup.PUT("/:id", advisoryHandler.Update)
+			advisoryGroup.DELETE("/:id", advisoryHandler.Delete)
+		}
+
+		// --- COLLECTION Routes ---
+		// Public (admins may add ?preview=true to see collections outside their window)
+		api.GET("/collections", auth.OptionalMiddleware(), collectionHandler.GetAll)
+		api.GET("/collections/:id", auth.OptionalMiddleware(), collectionHandler.GetByID)
+
+		// Admin
+		collectionGroup := api.Group("/collections")
+		collectionGroup.Use(auth.Middleware(), auth.AdminMiddleware())
+		{
+			collectionGroup.POST("", collectionHandler.Create)
+			collectionGroup.PUT("/:id", collectionHandler.Update)
+			collectionGroup.PUT("/:id/dramas", collectionHandler.SetDramas)
+			collectionGroup.DELETE("/:id", collectionHandler.Delete)
+		}
+
 		// --- ACTOR Routes ---
 		// Public
 		api.GET("/actors", actorHandler.GetAll)
 		api.GET("/actors/:id", actorHandler.GetByID)
+		api.GET("/actors/:id/dramas", actorHandler.GetFilmography)
+		api.GET("/actors/:id/co-stars", actorHandler.GetCoStars)
 
 		// Admin
 		actorGroup := api.Group("/actors")
@@ -171,27 +324,105 @@ func main() {
 		{
 			actorGroup.POST("", actorHandler.Create)
 			actorGroup.PUT("/:id", actorHandler.Update)
+			actorGroup.PATCH("/:id", actorHandler.Patch)
 			actorGroup.DELETE("/:id", actorHandler.Delete)
+			actorGroup.POST("/:id/photos", actorHandler.AddPhoto)
+			actorGroup.PUT("/:id/photos/:photoId", actorHandler.UpdatePhoto)
+			actorGroup.DELETE("/:id/photos/:photoId", actorHandler.DeletePhoto)
 		}
 
-		// --- DRAMA Routes ---
+		// --- CREW Routes ---
+		// Public (crew are people from the actors table; manage them through /actors)
+		api.GET("/crew", actorHandler.GetCrew)
+		api.GET("/crew/:id", actorHandler.GetCrewProfile)
+
+		// --- NETWORK Routes ---
 		// Public
-		api.GET("/dramas", dramaHandler.GetAll)
-		api.GET("/dramas/:id", dramaHandler.GetByID)
+		api.GET("/networks", networkHandler.GetAll)
+		api.GET("/networks/:id", networkHandler.GetByID)
+
+		// Admin
+		networkGroup := api.Group("/networks")
+		networkGroup.Use(auth.Middleware(), auth.AdminMiddleware())
+		{
+			networkGroup.POST("", networkHandler.Create)
+			networkGroup.PUT("/:id", networkHandler.Update)
+			networkGroup.PATCH("/:id", networkHandler.Patch)
+			networkGroup.DELETE("/:id", networkHandler.Delete)
+		}
+
+		// --- PRODUCTION COMPANY Routes ---
+		// Public
+		api.GET("/production-companies", productionHandler.GetAll)
+		api.GET("/production-companies/:id", productionHandler.GetByID)
+
+		// Admin
+		productionGroup := api.Group("/production-companies")
+		productionGroup.Use(auth.Middleware(), auth.AdminMiddleware())
+		{
+			productionGroup.POST("", productionHandler.Create)
+			productionGroup.PUT("/:id", productionHandler.Update)
+			productionGroup.PATCH("/:id", productionHandler.Patch)
+			productionGroup.DELETE("/:id", productionHandler.Delete)
+		}
+
+		// --- DRAMA Routes ---
+		// Public (admins may add ?preview=true to see unpublished dramas)
+		api.GET("/dramas", auth.OptionalMiddleware(), dramaHandler.GetAll)
+		api.GET("/dramas/coming-soon", dramaHandler.ComingSoon)
+		api.GET("/dramas/facets", auth.OptionalMiddleware(), dramaHandler.GetFacets)
+		api.GET("/countries", dramaHandler.GetCountries)
+		api.GET("/countries/:code/dramas", auth.OptionalMiddleware(), dramaHandler.GetByCountry)
+		api.GET("/dramas/:id", auth.OptionalMiddleware(), dramaHandler.GetByID)
+		api.GET("/dramas/:id/similar", auth.OptionalMiddleware(), dramaHandler.GetSimilar)
+		api.GET("/dramas/:id/media", auth.OptionalMiddleware(), mediaHandler.GetByDrama)
 
 		// Admin
 		dramaGroup := api.Group("/dramas")
-		dramaGroup.Use(auth.Middleware(), auth.AdminMiddleware())
+		dramaGroup.Use(auth.Middleware(), auth.AdminMiddleware(), drama.IDMiddleware())
 		{
 			dramaGroup.POST("", dramaHandler.Create)
 			dramaGroup.PUT("/:id", dramaHandler.Update)
+			dramaGroup.PATCH("/:id", dramaHandler.Patch)
 			dramaGroup.DELETE("/:id", dramaHandler.Delete)
+			dramaGroup.POST("/:id/publish", dramaHandler.Publish)
+			dramaGroup.POST("/:id/unpublish", dramaHandler.Unpublish)
+			dramaGroup.POST("/:id/schedule", dramaHandler.Schedule)
+			dramaGroup.GET("/:id/revisions", dramaHandler.GetRevisions)
+			dramaGroup.GET("/:id/revisions/:revision", dramaHandler.GetRevision)
+			dramaGroup.POST("/:id/revisions/:revision/rollback", dramaHandler.Rollback)
+			dramaGroup.GET("/:id/status-history", dramaHandler.GetStatusHistory)
+			dramaGroup.GET("/:id/metadata", metadataHandler.GetLink)
+			dramaGroup.POST("/:id/metadata", metadataHandler.Link)
+			dramaGroup.POST("/:id/metadata/refresh", metadataHandler.Refresh)
+			dramaGroup.PUT("/:id/metadata/locks", metadataHandler.SetLocks)
+			dramaGroup.PUT("/:id/tags", tagHandler.SetDramaTags)
+			dramaGroup.PUT("/:id/advisories", advisoryHandler.SetDramaAdvisories)
+			dramaGroup.PUT("/:id/crew", dramaHandler.SetCrew)
+			dramaGroup.PUT("/:id/companies", dramaHandler.SetCompanies)
+			dramaGroup.PUT("/:id/broadcast", scheduleHandler.SetSlots)
+			dramaGroup.PUT("/:id/seasons/order", seasonHandler.Reorder)
+			dramaGroup.POST("/:id/media", mediaHandler.Create)
+		}
+
+		// --- MEDIA Routes ---
+		// Admin (items are added through /dramas/:id/media)
+		mediaGroup := api.Group("/media")
+		mediaGroup.Use(auth.Middleware(), auth.AdminMiddleware())
+		{
+			mediaGroup.PUT("/:id", mediaHandler.Update)
+			mediaGroup.DELETE("/:id", mediaHandler.Delete)
 		}
 
+		// --- SCHEDULE Routes ---
+		// Public
+		api.GET("/schedule", scheduleHandler.GetSchedule)
+		api.GET("/schedule/today", scheduleHandler.GetToday)
+
 		// --- SEASON Routes ---
 		// Public
-		api.GET("/dramas/:id/seasons", seasonHandler.GetByDramaID)
-		api.GET("/seasons/:id", seasonHandler.GetByID)
+		api.GET("/dramas/:id/seasons", auth.OptionalMiddleware(), seasonHandler.GetByDramaID)
+		api.GET("/seasons/:id", auth.OptionalMiddleware(), seasonHandler.GetByID)
 
 		// Admin
 		seasonGroup := api.Group("/seasons")
@@ -199,13 +430,16 @@ func main() {
 		{
 			seasonGroup.POST("", seasonHandler.Create)
 			seasonGroup.PUT("/:id", seasonHandler.Update)
+			seasonGroup.PATCH("/:id", seasonHandler.Patch)
 			seasonGroup.DELETE("/:id", seasonHandler.Delete)
+			seasonGroup.POST("/:id/episodes/bulk", episodeHandler.BulkCreate)
+			seasonGroup.PUT("/:id/episodes/order", episodeHandler.Reorder)
 		}
 
 		// --- EPISODE Routes ---
 		// Public
-		api.GET("/seasons/:id/episodes", episodeHandler.GetBySeasonID)
-		api.GET("/episodes/:id", episodeHandler.GetByID)
+		api.GET("/seasons/:id/episodes", auth.OptionalMiddleware(), episodeHandler.GetBySeasonID)
+		api.GET("/episodes/:id", auth.OptionalMiddleware(), episodeHandler.GetByID)
 
 		// Admin
 		episodeGroup := api.Group("/episodes")
@@ -213,7 +447,13 @@ func main() {
 		{
 			episodeGroup.POST("", episodeHandler.Create)
 			episodeGroup.PUT("/:id", episodeHandler.Update)
+			episodeGroup.PATCH("/:id", episodeHandler.Patch)
 			episodeGroup.DELETE("/:id", episodeHandler.Delete)
+			episodeGroup.POST("/:id/publish", episodeHandler.Publish)
+			episodeGroup.POST("/:id/unpublish", episodeHandler.Unpublish)
+			episodeGroup.POST("/:id/schedule", episodeHandler.Schedule)
+			episodeGroup.PUT("/:id/advisories", advisoryHandler.SetEpisodeAdvisories)
+			episodeGroup.POST("/:id/move", episodeHandler.Move)
 		}
 
 		// --- WATCHLIST Routes ---
@@ -276,6 +516,43 @@ func main() {
 			analyticsGroup.DELETE("/users/:id", authHandler.DeleteUser)
 		}
 
+		// --- TRASH Routes ---
+		// Admin
+		trashGroup := api.Group("/trash")
+		trashGroup.Use(auth.Middleware(), auth.AdminMiddleware())
+		{
+			trashGroup.GET("", trashHandler.GetAll)
+			trashGroup.DELETE("", trashHandler.PurgeExpired)
+			trashGroup.POST("/:type/:id/restore", trashHandler.Restore)
+			trashGroup.DELETE("/:type/:id", trashHandler.Purge)
+		}
+
+		// --- IMPORT Routes ---
+		// Admin
+		importGroup := api.Group("/import")
+		importGroup.Use(auth.Middleware(), auth.AdminMiddleware())
+		{
+			importGroup.POST("", importHandler.Import)
+		}
+
+		// --- EXPORT Routes ---
+		// Admin
+		exportGroup := api.Group("/export")
+		exportGroup.Use(auth.Middleware(), auth.AdminMiddleware())
+		{
+			exportGroup.GET("", exportHandler.Export)
+		}
+
+		// --- METADATA Routes ---
+		// Admin
+		metadataGroup := api.Group("/metadata")
+		metadataGroup.Use(auth.Middleware(), auth.AdminMiddleware())
+		{
+			metadataGroup.GET("/:provider/search", metadataHandler.Search)
+			metadataGroup.GET("/:provider/titles/:externalId/preview", metadataHandler.Preview)
+			metadataGroup.POST("/:provider/titles/:externalId/import", metadataHandler.Import)
+		}
+
 		authGroup := api.Group("/auth")
 		{
 			authGroup.POST("/register", authHandler.Register)
@@ -286,6 +563,8 @@ func main() {
 			{
 				protected.GET("/me", authHandler.GetProfile)
 				protected.PUT("/profile", authHandler.UpdateProfile)
+				protected.GET("/preferences/advisories", advisoryHandler.GetPreferences)
+				protected.PUT("/preferences/advisories", advisoryHandler.UpdatePreferences)
 			}
 		}
 	}
@@ -309,6 +588,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("Shutting down server...")
+	stopJobs()
 
 	// Close database connection
 	database.Close()
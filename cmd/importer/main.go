@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"drakor-backend/internal/importer"
+	"drakor-backend/pkg/database"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	file := flag.String("file", "", "path to the JSON or CSV bundle")
+	format := flag.String("format", "", "json or csv (default: from the file extension)")
+	dryRun := flag.Bool("dry-run", false, "validate and report without saving")
+	userEmail := flag.String("user", "", "email of the user recorded as the author (optional)")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *format == "" {
+		*format = "json"
+		if strings.EqualFold(filepath.Ext(*file), ".csv") {
+			*format = "csv"
+		}
+	}
+
+	// Load env
+	if err := godotenv.Load("../.env"); err != nil {
+		// Try root .env if not found in parent (running from root)
+		if err := godotenv.Load(".env"); err != nil {
+			log.Println("Warning: .env file not found")
+		}
+	}
+
+	// Connect DB
+	if err := database.Connect(); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+
+	ctx := context.Background()
+
+	var userID string
+	if *userEmail != "" {
+		if err := database.GetDB().QueryRow(ctx, "SELECT id FROM users WHERE email = $1", *userEmail).Scan(&userID); err != nil {
+			log.Fatalf("User %s not found: %v", *userEmail, err)
+		}
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Failed to open bundle: %v", err)
+	}
+	defer f.Close()
+
+	service := importer.NewService(importer.NewRepository())
+	report, err := service.Import(ctx, userID, *format, f, *dryRun)
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	out, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(out))
+
+	if !report.Valid {
+		fmt.Println("❌ Import rejected, nothing was saved")
+		os.Exit(1)
+	}
+	if *dryRun {
+		fmt.Println("✅ Dry run passed, nothing was saved")
+		return
+	}
+	fmt.Println("✅ Import complete!")
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+
+	"drakor-backend/internal/drama"
+	"drakor-backend/pkg/database"
+
+	"github.com/joho/godotenv"
+)
+
+// reconcile repairs total_seasons and episode_count on every drama, e.g. after
+// rows were edited by hand or restored outside the API
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report drifted counts without repairing them")
+	flag.Parse()
+
+	// Load env
+	if err := godotenv.Load("../.env"); err != nil {
+		// Try root .env if not found in parent (running from root)
+		if err := godotenv.Load(".env"); err != nil {
+			log.Println("Warning: .env file not found")
+		}
+	}
+
+	// Connect DB
+	if err := database.Connect(); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+
+	drift, err := drama.NewRepository().ReconcileCounts(context.Background(), *dryRun)
+	if err != nil {
+		log.Fatalf("Reconcile failed: %v", err)
+	}
+
+	out, _ := json.MarshalIndent(drift, "", "  ")
+	fmt.Println(string(out))
+
+	switch {
+	case len(drift) == 0:
+		fmt.Println("✅ All season and episode counts are consistent")
+	case *dryRun:
+		fmt.Printf("⚠️  %d dramas have drifted counts, nothing was changed\n", len(drift))
+	default:
+		fmt.Printf("✅ Repaired counts on %d dramas\n", len(drift))
+	}
+}
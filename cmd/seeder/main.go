@@ -6,6 +6,7 @@ import (
 	"log"
 	"time"
 
+	"drakor-backend/internal/drama"
 	"drakor-backend/pkg/database"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -111,10 +112,10 @@ func seedDramas(ctx context.Context, db *pgxpool.Pool, adminID string) {
 	// Drama 1
 	var dramaID string
 	err := db.QueryRow(ctx, `
-		INSERT INTO dramas (title, synopsis, year, rating, total_seasons, status, added_by, created_at, updated_at)
+		INSERT INTO dramas (title, slug, synopsis, year, rating, status, added_by, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id
-	`, "Goblin: The Lonely and Great God", "In ancient times, an invincible general is betrayed...", 2016, 9.5, 1, "completed", adminID, time.Now(), time.Now()).Scan(&dramaID)
+	`, "Goblin: The Lonely and Great God", "goblin-the-lonely-and-great-god", "In ancient times, an invincible general is betrayed...", 2016, 9.5, "completed", adminID, time.Now(), time.Now()).Scan(&dramaID)
 
 	if err == nil {
 		// Attach Genre (Fantasy)
@@ -133,14 +134,18 @@ func seedDramas(ctx context.Context, db *pgxpool.Pool, adminID string) {
 
 		// Add Episode 1
 		db.Exec(ctx, `
-			INSERT INTO episodes (season_id, episode_number, title, video_url, duration, created_at)
-			VALUES ($1, $2, $3, $4, $5, $6)
-		`, seasonID, 1, "Episode 1", "https://sample-videos.com/video321/mp4/720/big_buck_bunny_720p_1mb.mp4", 3600, time.Now())
+			INSERT INTO episodes (season_id, episode_number, title, slug, video_url, duration, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, seasonID, 1, "Episode 1", "goblin-the-lonely-and-great-god-s1-e1", "https://sample-videos.com/video321/mp4/720/big_buck_bunny_720p_1mb.mp4", 3600, time.Now())
+
+		if err := drama.SyncCounts(ctx, db, dramaID); err != nil {
+			log.Printf("Error syncing counts for %s: %v\n", dramaID, err)
+		}
 	}
 
 	// Drama 2
 	db.Exec(ctx, `
-		INSERT INTO dramas (title, synopsis, year, rating, total_seasons, status, added_by, created_at, updated_at)
+		INSERT INTO dramas (title, slug, synopsis, year, rating, status, added_by, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-	`, "Queen of Tears", "The queen of department stores and the prince of supermarkets...", 2024, 8.8, 1, "ongoing", adminID, time.Now(), time.Now())
+	`, "Queen of Tears", "queen-of-tears", "The queen of department stores and the prince of supermarkets...", 2024, 8.8, "ongoing", adminID, time.Now(), time.Now())
 }
@@ -0,0 +1,103 @@
+// Command tmdbstub serves recorded TMDB responses so the metadata import can be
+// exercised without network access or an API key:
+//
+//	go run ./cmd/tmdbstub -addr :8090
+//	TMDB_BASE_URL=http://localhost:8090/3 go run ./cmd/api
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	showPath   = regexp.MustCompile(`^/3/tv/(\d+)$`)
+	seasonPath = regexp.MustCompile(`^/3/tv/(\d+)/season/(\d+)$`)
+)
+
+func main() {
+	addr := flag.String("addr", ":8090", "listen address")
+	dir := flag.String("dir", "internal/metadata/testdata/tmdb", "directory with recorded fixtures")
+	flag.Parse()
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("%s %s", r.Method, r.URL.RequestURI())
+
+		switch {
+		case r.URL.Path == "/3/search/tv":
+			serveSearch(w, r, filepath.Join(*dir, "search_tv.json"))
+		case seasonPath.MatchString(r.URL.Path):
+			m := seasonPath.FindStringSubmatch(r.URL.Path)
+			serveFile(w, filepath.Join(*dir, "tv_"+m[1]+"_season_"+m[2]+".json"))
+		case showPath.MatchString(r.URL.Path):
+			m := showPath.FindStringSubmatch(r.URL.Path)
+			serveFile(w, filepath.Join(*dir, "tv_"+m[1]+".json"))
+		default:
+			notFound(w)
+		}
+	})
+
+	log.Printf("🎬 TMDB stub serving %s on %s", *dir, *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
+
+// serveSearch filters the recorded results by name and first-air year like the real API
+func serveSearch(w http.ResponseWriter, r *http.Request, path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		notFound(w)
+		return
+	}
+
+	var page map[string]interface{}
+	if err := json.Unmarshal(data, &page); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	query := strings.ToLower(r.URL.Query().Get("query"))
+	year := r.URL.Query().Get("first_air_date_year")
+	results, _ := page["results"].([]interface{})
+	filtered := []interface{}{}
+	for _, item := range results {
+		show, _ := item.(map[string]interface{})
+		name, _ := show["name"].(string)
+		original, _ := show["original_name"].(string)
+		aired, _ := show["first_air_date"].(string)
+		if query != "" && !strings.Contains(strings.ToLower(name), query) && !strings.Contains(strings.ToLower(original), query) {
+			continue
+		}
+		if year != "" && !strings.HasPrefix(aired, year) {
+			continue
+		}
+		filtered = append(filtered, show)
+	}
+	page["results"] = filtered
+	page["total_results"] = len(filtered)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+func serveFile(w http.ResponseWriter, path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		notFound(w)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// notFound mirrors the TMDB error body
+func notFound(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	w.Write([]byte(`{"success":false,"status_code":34,"status_message":"The resource you requested could not be found."}`))
+}
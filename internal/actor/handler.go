@@ -58,6 +58,14 @@ func (h *Handler) Create(c *gin.Context) {
 
 	actor, err := h.service.Create(c.Request.Context(), req)
 	if err != nil {
+		if err.Error() == "slug already exists" {
+			response.Error(c, http.StatusConflict, err.Error(), "slug_exists")
+			return
+		}
+		if err.Error() == "invalid slug" {
+			response.BadRequest(c, "Invalid slug", "validation_error")
+			return
+		}
 		response.InternalError(c, "Failed to create actor", err.Error())
 		return
 	}
@@ -83,6 +91,47 @@ func (h *Handler) Update(c *gin.Context) {
 			response.NotFound(c, "Actor not found")
 			return
 		}
+		if err.Error() == "slug already exists" {
+			response.Error(c, http.StatusConflict, err.Error(), "slug_exists")
+			return
+		}
+		if err.Error() == "invalid slug" {
+			response.BadRequest(c, "Invalid slug", "validation_error")
+			return
+		}
+		response.InternalError(c, "Failed to update actor", err.Error())
+		return
+	}
+	response.Success(c, "Actor updated successfully", actor)
+}
+
+func (h *Handler) Patch(c *gin.Context) {
+	id := c.Param("id")
+	var req PatchActorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	actor, err := h.service.Patch(c.Request.Context(), id, req)
+	if err != nil {
+		if err.Error() == "actor not found" {
+			response.NotFound(c, "Actor not found")
+			return
+		}
+		if err.Error() == "slug already exists" {
+			response.Error(c, http.StatusConflict, err.Error(), "slug_exists")
+			return
+		}
+		if err.Error() == "invalid slug" {
+			response.BadRequest(c, "Invalid slug", "validation_error")
+			return
+		}
 		response.InternalError(c, "Failed to update actor", err.Error())
 		return
 	}
@@ -97,3 +146,141 @@ func (h *Handler) Delete(c *gin.Context) {
 	}
 	response.Success(c, "Actor deleted successfully", nil)
 }
+
+// GetCrew lists directors, screenwriters, composers and producers; ?job= narrows it to one
+func (h *Handler) GetCrew(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	job := c.Query("job")
+	if job != "" && job != "director" && job != "screenwriter" && job != "composer" && job != "producer" {
+		response.BadRequest(c, "Invalid job", "validation_error")
+		return
+	}
+
+	crew, total, err := h.service.GetCrew(c.Request.Context(), page, limit, job, c.Query("search"))
+	if err != nil {
+		response.InternalError(c, "Failed to fetch crew", err.Error())
+		return
+	}
+	response.Paginated(c, crew, total, page, limit)
+}
+
+func (h *Handler) GetCrewProfile(c *gin.Context) {
+	profile, err := h.service.GetCrewProfile(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.InternalError(c, "Failed to fetch crew member", err.Error())
+		return
+	}
+	if profile == nil {
+		response.NotFound(c, "Person not found")
+		return
+	}
+	response.Success(c, "Crew member detail", profile)
+}
+
+// GetFilmography lists the published dramas an actor appears in; ?sort= is latest, oldest, rating or title
+func (h *Handler) GetFilmography(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	sort := c.DefaultQuery("sort", "latest")
+	if sort != "latest" && sort != "oldest" && sort != "rating" && sort != "title" {
+		response.BadRequest(c, "Invalid sort", "validation_error")
+		return
+	}
+
+	entries, total, err := h.service.GetFilmography(c.Request.Context(), c.Param("id"), page, limit, sort)
+	if err != nil {
+		if err.Error() == "actor not found" {
+			response.NotFound(c, "Actor not found")
+			return
+		}
+		response.InternalError(c, "Failed to fetch filmography", err.Error())
+		return
+	}
+	response.Paginated(c, entries, total, page, limit)
+}
+
+// GetCoStars lists the actors who most often share a cast with this one
+func (h *Handler) GetCoStars(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	coStars, err := h.service.GetCoStars(c.Request.Context(), c.Param("id"), limit)
+	if err != nil {
+		if err.Error() == "actor not found" {
+			response.NotFound(c, "Actor not found")
+			return
+		}
+		response.InternalError(c, "Failed to fetch co-stars", err.Error())
+		return
+	}
+	response.Success(c, "Frequent co-stars", coStars)
+}
+
+func (h *Handler) AddPhoto(c *gin.Context) {
+	var req PhotoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	photo, err := h.service.AddPhoto(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		if err.Error() == "actor not found" {
+			response.NotFound(c, "Actor not found")
+			return
+		}
+		response.InternalError(c, "Failed to add photo", err.Error())
+		return
+	}
+	response.Created(c, "Photo added successfully", photo)
+}
+
+func (h *Handler) UpdatePhoto(c *gin.Context) {
+	var req PhotoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	photo, err := h.service.UpdatePhoto(c.Request.Context(), c.Param("id"), c.Param("photoId"), req)
+	if err != nil {
+		if err.Error() == "actor not found" {
+			response.NotFound(c, "Actor not found")
+			return
+		}
+		if err.Error() == "photo not found" {
+			response.NotFound(c, "Photo not found")
+			return
+		}
+		response.InternalError(c, "Failed to update photo", err.Error())
+		return
+	}
+	response.Success(c, "Photo updated successfully", photo)
+}
+
+func (h *Handler) DeletePhoto(c *gin.Context) {
+	err := h.service.DeletePhoto(c.Request.Context(), c.Param("id"), c.Param("photoId"))
+	if err != nil {
+		if err.Error() == "actor not found" {
+			response.NotFound(c, "Actor not found")
+			return
+		}
+		if err.Error() == "photo not found" {
+			response.NotFound(c, "Photo not found")
+			return
+		}
+		response.InternalError(c, "Failed to delete photo", err.Error())
+		return
+	}
+	response.Success(c, "Photo deleted successfully", nil)
+}
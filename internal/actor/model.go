@@ -1,22 +1,141 @@
 package actor
 
 import (
+	"drakor-backend/pkg/patch"
+	"drakor-backend/pkg/validator"
 	"time"
 )
 
+func init() {
+	validator.RegisterPatchTypes(patch.Field[map[string]string]{})
+}
+
+// Actor is a person in the catalog. Lists and cast credits carry only the
+// identity fields; the profile fields and photos are loaded on detail.
 type Actor struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	NativeName  string            `json:"native_name,omitempty"` // Name in its original script
+	Slug        string            `json:"slug"`
+	PhotoURL    string            `json:"photo_url"`             // Mirrors the primary photo
+	BirthDate   string            `json:"birth_date,omitempty"`  // YYYY-MM-DD
+	Gender      string            `json:"gender,omitempty"`      // 'female', 'male', 'non_binary', 'other'
+	Nationality string            `json:"nationality,omitempty"` // ISO 3166-1 alpha-2
+	Biography   string            `json:"biography,omitempty"`
+	Agency      string            `json:"agency,omitempty"`
+	Height      int               `json:"height_cm,omitempty"`
+	DebutYear   int               `json:"debut_year,omitempty"`
+	SocialLinks map[string]string `json:"social_links,omitempty"` // Platform -> profile URL
+	Photos      []Photo           `json:"photos,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// Photo is one image in an actor's gallery
+type Photo struct {
 	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	PhotoURL  string    `json:"photo_url"`
+	ActorID   string    `json:"actor_id"`
+	URL       string    `json:"url"`
+	Caption   string    `json:"caption,omitempty"`
+	Position  int       `json:"position"`
+	IsPrimary bool      `json:"is_primary"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
 type CreateActorRequest struct {
-	Name     string `json:"name" validate:"required,min=2,max=100"`
-	PhotoURL string `json:"photo_url" validate:"omitempty,url"`
+	Name        string            `json:"name" validate:"required,min=2,max=100"`
+	NativeName  string            `json:"native_name" validate:"omitempty,max=100"`
+	Slug        string            `json:"slug" validate:"omitempty,min=2,max=150"` // Auto-generated from name if empty
+	PhotoURL    string            `json:"photo_url" validate:"omitempty,url"`
+	BirthDate   string            `json:"birth_date" validate:"omitempty,datetime=2006-01-02"`
+	Gender      string            `json:"gender" validate:"omitempty,oneof=female male non_binary other"`
+	Nationality string            `json:"nationality" validate:"omitempty,iso3166_1_alpha2"`
+	Biography   string            `json:"biography"`
+	Agency      string            `json:"agency" validate:"omitempty,max=150"`
+	Height      int               `json:"height_cm" validate:"omitempty,min=50,max=250"`
+	DebutYear   int               `json:"debut_year" validate:"omitempty,min=1900,max=2100"`
+	SocialLinks map[string]string `json:"social_links" validate:"omitempty,dive,keys,oneof=instagram x youtube tiktok weibo facebook website,endkeys,url"`
 }
 
 type UpdateActorRequest struct {
-	Name     string `json:"name" validate:"required,min=2,max=100"`
-	PhotoURL string `json:"photo_url" validate:"omitempty,url"`
+	Name        string            `json:"name" validate:"required,min=2,max=100"`
+	NativeName  string            `json:"native_name" validate:"omitempty,max=100"`
+	Slug        string            `json:"slug" validate:"omitempty,min=2,max=150"` // Auto-generated from name if empty
+	PhotoURL    string            `json:"photo_url" validate:"omitempty,url"`      // Becomes the primary photo; empty keeps the gallery's
+	BirthDate   string            `json:"birth_date" validate:"omitempty,datetime=2006-01-02"`
+	Gender      string            `json:"gender" validate:"omitempty,oneof=female male non_binary other"`
+	Nationality string            `json:"nationality" validate:"omitempty,iso3166_1_alpha2"`
+	Biography   string            `json:"biography"`
+	Agency      string            `json:"agency" validate:"omitempty,max=150"`
+	Height      int               `json:"height_cm" validate:"omitempty,min=50,max=250"`
+	DebutYear   int               `json:"debut_year" validate:"omitempty,min=1900,max=2100"`
+	SocialLinks map[string]string `json:"social_links" validate:"omitempty,dive,keys,oneof=instagram x youtube tiktok weibo facebook website,endkeys,url"`
+}
+
+// PatchActorRequest follows JSON Merge Patch: absent members are left untouched,
+// null clears optional fields
+type PatchActorRequest struct {
+	Name        patch.Field[string]            `json:"name" validate:"omitnil,min=2,max=100"`
+	NativeName  patch.Field[string]            `json:"native_name" validate:"omitnil,max=100"`
+	Slug        patch.Field[string]            `json:"slug" validate:"omitnil,min=2,max=150"`
+	PhotoURL    patch.Field[string]            `json:"photo_url" validate:"omitnil,omitempty,url"`
+	BirthDate   patch.Field[string]            `json:"birth_date" validate:"omitnil,omitempty,datetime=2006-01-02"`
+	Gender      patch.Field[string]            `json:"gender" validate:"omitnil,omitempty,oneof=female male non_binary other"`
+	Nationality patch.Field[string]            `json:"nationality" validate:"omitnil,omitempty,iso3166_1_alpha2"`
+	Biography   patch.Field[string]            `json:"biography"`
+	Agency      patch.Field[string]            `json:"agency" validate:"omitnil,max=150"`
+	Height      patch.Field[int]               `json:"height_cm" validate:"omitnil,omitempty,min=50,max=250"`
+	DebutYear   patch.Field[int]               `json:"debut_year" validate:"omitnil,omitempty,min=1900,max=2100"`
+	SocialLinks patch.Field[map[string]string] `json:"social_links" validate:"omitnil,dive,keys,oneof=instagram x youtube tiktok weibo facebook website,endkeys,url"`
+}
+
+type PhotoRequest struct {
+	URL       string `json:"url" validate:"required,url"`
+	Caption   string `json:"caption" validate:"omitempty,max=255"`
+	Position  int    `json:"position" validate:"min=0"`
+	IsPrimary bool   `json:"is_primary"` // The first photo of an actor is always primary
+}
+
+// CrewMember is a person listed with the crew jobs they are credited for
+type CrewMember struct {
+	Actor
+	Jobs        []string `json:"jobs"`
+	CreditCount int      `json:"credit_count"`
+}
+
+// CrewCredit is one behind-the-camera credit on a published drama
+type CrewCredit struct {
+	DramaID   string `json:"drama_id"`
+	Title     string `json:"title"`
+	Slug      string `json:"slug"`
+	PosterURL string `json:"poster_url"`
+	Year      int    `json:"year"`
+	Job       string `json:"job"`
+}
+
+type CrewProfile struct {
+	Actor
+	Credits []CrewCredit `json:"credits"`
+}
+
+// FilmographyEntry is a published drama an actor appears in. An actor playing
+// several characters in one drama gets a single entry listing all of them.
+type FilmographyEntry struct {
+	DramaID    string   `json:"drama_id"`
+	Title      string   `json:"title"`
+	Slug       string   `json:"slug"`
+	PosterURL  string   `json:"poster_url"`
+	Year       int      `json:"year"`
+	Rating     float64  `json:"rating"`
+	Status     string   `json:"status"`
+	Role       string   `json:"role"` // 'main' when any of the credits is a main role
+	Characters []string `json:"characters"`
+	Billing    int      `json:"billing_order"` // Highest billing among the credits
+	IsCameo    bool     `json:"is_cameo"`
+	IsGuest    bool     `json:"is_guest"`
+}
+
+// CoStar is an actor who shared the cast of published dramas with another actor
+type CoStar struct {
+	Actor
+	SharedDramas int `json:"shared_dramas"`
 }
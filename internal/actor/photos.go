@@ -0,0 +1,224 @@
+package actor
+
+import (
+	"context"
+	"drakor-backend/pkg/database"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const photoColumns = `id, actor_id, url, caption, position, is_primary, created_at`
+
+// scanPhoto reads a row selected with the actor_photos columns in photoColumns order
+func scanPhoto(row pgx.Row) (*Photo, error) {
+	var p Photo
+	var caption *string
+	if err := row.Scan(&p.ID, &p.ActorID, &p.URL, &caption, &p.Position, &p.IsPrimary, &p.CreatedAt); err != nil {
+		return nil, err
+	}
+	if caption != nil {
+		p.Caption = *caption
+	}
+	return &p, nil
+}
+
+func (r *repository) FindPhotos(ctx context.Context, actorID string) ([]Photo, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	query := `SELECT ` + photoColumns + ` FROM actor_photos WHERE actor_id = $1 ORDER BY is_primary DESC, position, created_at`
+	rows, err := db.Query(ctx, query, actorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	photos := []Photo{}
+	for rows.Next() {
+		p, err := scanPhoto(rows)
+		if err != nil {
+			return nil, err
+		}
+		photos = append(photos, *p)
+	}
+	return photos, rows.Err()
+}
+
+func (r *repository) FindPhoto(ctx context.Context, id string) (*Photo, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	p, err := scanPhoto(db.QueryRow(ctx, `SELECT `+photoColumns+` FROM actor_photos WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *repository) CreatePhoto(ctx context.Context, p *Photo) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if p.IsPrimary {
+		if err := clearPrimaryPhoto(ctx, tx, p.ActorID); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		INSERT INTO actor_photos (actor_id, url, caption, position, is_primary)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRow(ctx, query, p.ActorID, p.URL, p.Caption, p.Position, p.IsPrimary).Scan(&p.ID, &p.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := settlePhotos(ctx, tx, p.ActorID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *repository) UpdatePhoto(ctx context.Context, p *Photo) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if p.IsPrimary {
+		if err := clearPrimaryPhoto(ctx, tx, p.ActorID); err != nil {
+			return err
+		}
+	}
+
+	query := `UPDATE actor_photos SET url = $1, caption = NULLIF($2, ''), position = $3, is_primary = $4 WHERE id = $5`
+	if _, err := tx.Exec(ctx, query, p.URL, p.Caption, p.Position, p.IsPrimary, p.ID); err != nil {
+		return err
+	}
+
+	if err := settlePhotos(ctx, tx, p.ActorID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *repository) DeletePhoto(ctx context.Context, id string) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var actorID string
+	err = tx.QueryRow(ctx, "DELETE FROM actor_photos WHERE id = $1 RETURNING actor_id", id).Scan(&actorID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	if err := settlePhotos(ctx, tx, actorID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func clearPrimaryPhoto(ctx context.Context, tx pgx.Tx, actorID string) error {
+	_, err := tx.Exec(ctx, "UPDATE actor_photos SET is_primary = FALSE WHERE actor_id = $1 AND is_primary", actorID)
+	return err
+}
+
+// settlePhotos makes sure an actor with photos keeps a primary one, promoting the
+// first when needed, and mirrors it into actors.photo_url
+func settlePhotos(ctx context.Context, tx pgx.Tx, actorID string) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE actor_photos SET is_primary = TRUE
+		WHERE id = (SELECT id FROM actor_photos WHERE actor_id = $1 ORDER BY position, created_at LIMIT 1)
+		AND NOT EXISTS (SELECT 1 FROM actor_photos WHERE actor_id = $1 AND is_primary)
+	`, actorID)
+	if err != nil {
+		return err
+	}
+
+	// Without any photo left the actor keeps its last photo_url
+	_, err = tx.Exec(ctx, `
+		UPDATE actors a SET photo_url = p.url
+		FROM actor_photos p
+		WHERE a.id = $1 AND p.actor_id = a.id AND p.is_primary
+		  AND a.photo_url IS DISTINCT FROM p.url
+	`, actorID)
+	return err
+}
+
+// setPhoto makes url the actor's primary photo inside tx, adding it to the gallery
+// when it is not there yet, and mirrors it into actors.photo_url, so a later
+// gallery edit keeps it. An empty url falls back to the gallery's primary photo.
+func setPhoto(ctx context.Context, tx pgx.Tx, actorID, url string) error {
+	if url == "" {
+		_, err := tx.Exec(ctx, `
+			UPDATE actors a
+			SET photo_url = (SELECT p.url FROM actor_photos p WHERE p.actor_id = a.id AND p.is_primary)
+			WHERE a.id = $1
+		`, actorID)
+		return err
+	}
+
+	var photoID string
+	err := tx.QueryRow(ctx, `
+		SELECT id FROM actor_photos
+		WHERE actor_id = $1 AND url = $2
+		ORDER BY is_primary DESC, position, created_at
+		LIMIT 1
+	`, actorID, url).Scan(&photoID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	// Demote the current primary first; at most one primary photo may exist
+	_, err = tx.Exec(ctx, "UPDATE actor_photos SET is_primary = FALSE WHERE actor_id = $1 AND is_primary AND id::text <> $2", actorID, photoID)
+	if err != nil {
+		return err
+	}
+	if photoID == "" {
+		_, err = tx.Exec(ctx, "INSERT INTO actor_photos (actor_id, url, is_primary, created_at) VALUES ($1, $2, TRUE, $3)", actorID, url, time.Now())
+	} else {
+		_, err = tx.Exec(ctx, "UPDATE actor_photos SET is_primary = TRUE WHERE id = $1 AND NOT is_primary", photoID)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE actors SET photo_url = $1 WHERE id = $2 AND photo_url IS DISTINCT FROM $1", url, actorID)
+	return err
+}
@@ -3,7 +3,9 @@ package actor
 import (
 	"context"
 	"drakor-backend/pkg/database"
+	"drakor-backend/pkg/patch"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -12,9 +14,26 @@ import (
 type Repository interface {
 	FindAll(ctx context.Context, limit, offset int, search string) ([]Actor, int64, error)
 	FindByID(ctx context.Context, id string) (*Actor, error)
+	FindBySlug(ctx context.Context, slug string) (*Actor, error)
+	SlugExists(ctx context.Context, slug, excludeID string) (bool, error)
 	Create(ctx context.Context, actor *Actor) error
 	Update(ctx context.Context, actor *Actor) error
+	Patch(ctx context.Context, id string, fields map[string]interface{}) error
 	Delete(ctx context.Context, id string) error
+	// FindCrew lists people with at least one crew credit on a published drama, optionally for one job
+	FindCrew(ctx context.Context, limit, offset int, job, search string) ([]CrewMember, int64, error)
+	// FindCrewCredits lists a person's crew credits on published dramas, newest first
+	FindCrewCredits(ctx context.Context, id string) ([]CrewCredit, error)
+	// FindFilmography lists the published dramas an actor is cast in
+	FindFilmography(ctx context.Context, id string, limit, offset int, sort string) ([]FilmographyEntry, int64, error)
+	// FindCoStars lists the actors sharing the most published dramas with an actor
+	FindCoStars(ctx context.Context, id string, limit int) ([]CoStar, error)
+	// FindPhotos lists an actor's photos in display order
+	FindPhotos(ctx context.Context, actorID string) ([]Photo, error)
+	FindPhoto(ctx context.Context, id string) (*Photo, error)
+	CreatePhoto(ctx context.Context, p *Photo) error
+	UpdatePhoto(ctx context.Context, p *Photo) error
+	DeletePhoto(ctx context.Context, id string) error
 }
 
 type repository struct{}
@@ -23,42 +42,59 @@ func NewRepository() Repository {
 	return &repository{}
 }
 
+// searchThreshold is the minimum trigram similarity for a fuzzy name match
+const searchThreshold = "0.3"
+
 func (r *repository) FindAll(ctx context.Context, limit, offset int, search string) ([]Actor, int64, error) {
 	db := database.GetDB()
 	if db == nil {
 		return nil, 0, errors.New("database not connected")
 	}
 
-	// Count total
-	var total int64
-	var countQuery string
-	var args []interface{}
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback(ctx)
 
+	// Search matches substrings of either name, then falls back to trigram
+	// similarity so romanization differences ("Lee Minho", "Yi Min-ho") still match.
+	// The % and <% operators can use the trigram indexes; their threshold is set
+	// for this transaction only, and the similarity functions are left to ranking.
+	where := " WHERE a.deleted_at IS NULL"
+	orderBy := "a.name ASC"
+	var args []interface{}
 	if search != "" {
-		countQuery = "SELECT COUNT(*) FROM actors WHERE name ILIKE $1"
-		args = append(args, "%"+search+"%")
-	} else {
-		countQuery = "SELECT COUNT(*) FROM actors"
+		_, err := tx.Exec(ctx, `
+			SELECT set_config('pg_trgm.similarity_threshold', $1, true),
+			       set_config('pg_trgm.word_similarity_threshold', $1, true)
+		`, searchThreshold)
+		if err != nil {
+			return nil, 0, err
+		}
+		where += ` AND (a.name ILIKE $1 OR a.native_name ILIKE $1
+			OR a.name % $2 OR $2 <% a.name OR a.native_name % $2)`
+		orderBy = `(a.name ILIKE $1 OR a.native_name ILIKE $1) DESC,
+			GREATEST(similarity(a.name, $2), word_similarity($2, a.name), COALESCE(similarity(a.native_name, $2), 0)) DESC,
+			a.name ASC`
+		args = append(args, "%"+search+"%", search)
 	}
 
-	err := db.QueryRow(ctx, countQuery, args...).Scan(&total)
+	// Count total
+	var total int64
+	err = tx.QueryRow(ctx, "SELECT COUNT(*) FROM actors a"+where, args...).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	// Get data
-	var query string
-	var queryArgs []interface{}
-
-	if search != "" {
-		query = `SELECT id, name, photo_url, created_at FROM actors WHERE name ILIKE $1 ORDER BY name ASC LIMIT $2 OFFSET $3`
-		queryArgs = append(queryArgs, "%"+search+"%", limit, offset)
-	} else {
-		query = `SELECT id, name, photo_url, created_at FROM actors ORDER BY name ASC LIMIT $1 OFFSET $2`
-		queryArgs = append(queryArgs, limit, offset)
-	}
-
-	rows, err := db.Query(ctx, query, queryArgs...)
+	query := fmt.Sprintf(`
+		SELECT a.id, a.name, a.native_name, a.slug, a.photo_url, a.created_at
+		FROM actors a%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, where, orderBy, len(args)+1, len(args)+2)
+	rows, err := tx.Query(ctx, query, append(args, limit, offset)...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -67,11 +103,13 @@ func (r *repository) FindAll(ctx context.Context, limit, offset int, search stri
 	var actors []Actor
 	for rows.Next() {
 		var a Actor
-		// photo_url may be null in DB, handle appropriately if needed but for now assuming string match
-		var photoURL *string
-		if err := rows.Scan(&a.ID, &a.Name, &photoURL, &a.CreatedAt); err != nil {
+		var nativeName, photoURL *string
+		if err := rows.Scan(&a.ID, &a.Name, &nativeName, &a.Slug, &photoURL, &a.CreatedAt); err != nil {
 			return nil, 0, err
 		}
+		if nativeName != nil {
+			a.NativeName = *nativeName
+		}
 		if photoURL != nil {
 			a.PhotoURL = *photoURL
 		}
@@ -81,36 +119,124 @@ func (r *repository) FindAll(ctx context.Context, limit, offset int, search stri
 	return actors, total, nil
 }
 
+// FindByID loads the full profile, including the photo gallery
 func (r *repository) FindByID(ctx context.Context, id string) (*Actor, error) {
 	db := database.GetDB()
 	if db == nil {
 		return nil, errors.New("database not connected")
 	}
 
-	query := `SELECT id, name, photo_url, created_at FROM actors WHERE id = $1`
+	query := `
+		SELECT id, name, native_name, slug, photo_url, to_char(birth_date, 'YYYY-MM-DD'), gender, nationality,
+		       biography, agency, height_cm, debut_year, social_links, created_at
+		FROM actors WHERE id = $1 AND deleted_at IS NULL
+	`
 	var a Actor
-	var photoURL *string
-	err := db.QueryRow(ctx, query, id).Scan(&a.ID, &a.Name, &photoURL, &a.CreatedAt)
+	var nativeName, photoURL, birthDate, gender, nationality, biography, agency *string
+	var height, debutYear *int
+	err := db.QueryRow(ctx, query, id).Scan(
+		&a.ID, &a.Name, &nativeName, &a.Slug, &photoURL, &birthDate, &gender, &nationality,
+		&biography, &agency, &height, &debutYear, &a.SocialLinks, &a.CreatedAt,
+	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
 	}
+	if nativeName != nil {
+		a.NativeName = *nativeName
+	}
 	if photoURL != nil {
 		a.PhotoURL = *photoURL
 	}
+	if birthDate != nil {
+		a.BirthDate = *birthDate
+	}
+	if gender != nil {
+		a.Gender = *gender
+	}
+	if nationality != nil {
+		a.Nationality = *nationality
+	}
+	if biography != nil {
+		a.Biography = *biography
+	}
+	if agency != nil {
+		a.Agency = *agency
+	}
+	if height != nil {
+		a.Height = *height
+	}
+	if debutYear != nil {
+		a.DebutYear = *debutYear
+	}
+
+	a.Photos, err = r.FindPhotos(ctx, a.ID)
+	if err != nil {
+		return nil, err
+	}
 	return &a, nil
 }
 
+func (r *repository) FindBySlug(ctx context.Context, slug string) (*Actor, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	// Current slug wins over a redirect left behind by a rename
+	query := `
+		SELECT id FROM (
+			SELECT id, 0 AS priority FROM actors WHERE slug = $1
+			UNION ALL
+			SELECT entity_id, 1 FROM slug_redirects WHERE entity_type = 'actor' AND slug = $1
+		) s
+		ORDER BY priority
+		LIMIT 1
+	`
+	var id string
+	if err := db.QueryRow(ctx, query, slug).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.FindByID(ctx, id)
+}
+
+func (r *repository) SlugExists(ctx context.Context, slug, excludeID string) (bool, error) {
+	db := database.GetDB()
+	if db == nil {
+		return false, errors.New("database not connected")
+	}
+
+	query := `
+		SELECT EXISTS(SELECT 1 FROM actors WHERE slug = $1 AND id::text <> $2)
+		    OR EXISTS(SELECT 1 FROM slug_redirects WHERE entity_type = 'actor' AND slug = $1 AND entity_id::text <> $2)
+	`
+	var exists bool
+	err := db.QueryRow(ctx, query, slug, excludeID).Scan(&exists)
+	return exists, err
+}
+
 func (r *repository) Create(ctx context.Context, actor *Actor) error {
 	db := database.GetDB()
 	if db == nil {
 		return errors.New("database not connected")
 	}
 
-	query := `INSERT INTO actors (name, photo_url, created_at) VALUES ($1, $2, $3) RETURNING id`
-	return db.QueryRow(ctx, query, actor.Name, actor.PhotoURL, time.Now()).Scan(&actor.ID)
+	query := `
+		INSERT INTO actors (name, native_name, slug, photo_url, birth_date, gender, nationality, biography, agency,
+		                    height_cm, debut_year, social_links, created_at)
+		VALUES ($1, NULLIF($2, ''), $3, $4, NULLIF($5, '')::date, NULLIF($6, ''), NULLIF($7, ''), $8, NULLIF($9, ''),
+		        NULLIF($10, 0), NULLIF($11, 0), $12, $13)
+		RETURNING id
+	`
+	return db.QueryRow(ctx, query,
+		actor.Name, actor.NativeName, actor.Slug, actor.PhotoURL, actor.BirthDate, actor.Gender, actor.Nationality,
+		actor.Biography, actor.Agency, actor.Height, actor.DebutYear, socialLinks(actor.SocialLinks), time.Now(),
+	).Scan(&actor.ID)
 }
 
 func (r *repository) Update(ctx context.Context, actor *Actor) error {
@@ -119,9 +245,93 @@ func (r *repository) Update(ctx context.Context, actor *Actor) error {
 		return errors.New("database not connected")
 	}
 
-	query := `UPDATE actors SET name = $1, photo_url = $2 WHERE id = $3`
-	_, err := db.Exec(ctx, query, actor.Name, actor.PhotoURL, actor.ID)
-	return err
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Keep the old slug reachable if it is being renamed
+	var oldSlug string
+	if err := tx.QueryRow(ctx, "SELECT slug FROM actors WHERE id = $1", actor.ID).Scan(&oldSlug); err != nil {
+		return err
+	}
+	if err := database.RedirectSlug(ctx, tx, "actor", oldSlug, actor.Slug, actor.ID); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE actors
+		SET name = $1, native_name = NULLIF($2, ''), slug = $3, birth_date = NULLIF($4, '')::date,
+		    gender = NULLIF($5, ''), nationality = NULLIF($6, ''), biography = $7, agency = NULLIF($8, ''),
+		    height_cm = NULLIF($9, 0), debut_year = NULLIF($10, 0), social_links = $11
+		WHERE id = $12
+	`
+	_, err = tx.Exec(ctx, query,
+		actor.Name, actor.NativeName, actor.Slug, actor.BirthDate, actor.Gender, actor.Nationality,
+		actor.Biography, actor.Agency, actor.Height, actor.DebutYear, socialLinks(actor.SocialLinks), actor.ID,
+	)
+	if err != nil {
+		return err
+	}
+	if err := setPhoto(ctx, tx, actor.ID, actor.PhotoURL); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// patchColumns lists the actor columns a patch may set; photo_url goes through setPhoto
+var patchColumns = []string{
+	"name", "native_name", "slug", "birth_date", "gender", "nationality",
+	"biography", "agency", "height_cm", "debut_year", "social_links",
+}
+
+// socialLinks stores a missing map as an empty object rather than JSON null
+func socialLinks(links map[string]string) map[string]string {
+	if links == nil {
+		return map[string]string{}
+	}
+	return links
+}
+
+func (r *repository) Patch(ctx context.Context, id string, fields map[string]interface{}) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Keep the old slug reachable if it is being renamed
+	if newSlug, ok := fields["slug"].(string); ok {
+		var oldSlug string
+		if err := tx.QueryRow(ctx, "SELECT slug FROM actors WHERE id = $1", id).Scan(&oldSlug); err != nil {
+			return err
+		}
+		if err := database.RedirectSlug(ctx, tx, "actor", oldSlug, newSlug, id); err != nil {
+			return err
+		}
+	}
+
+	if err := patch.Update(ctx, tx, "actors", patchColumns, fields, id); err != nil {
+		return err
+	}
+	if photo, ok := fields["photo_url"]; ok {
+		url, _ := photo.(string)
+		if err := setPhoto(ctx, tx, id, url); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
 }
 
 func (r *repository) Delete(ctx context.Context, id string) error {
@@ -130,7 +340,205 @@ func (r *repository) Delete(ctx context.Context, id string) error {
 		return errors.New("database not connected")
 	}
 
-	query := `DELETE FROM actors WHERE id = $1`
-	_, err := db.Exec(ctx, query, id)
+	query := `UPDATE actors SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	_, err := db.Exec(ctx, query, time.Now(), id)
 	return err
 }
+
+func (r *repository) FindCrew(ctx context.Context, limit, offset int, job, search string) ([]CrewMember, int64, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, 0, errors.New("database not connected")
+	}
+
+	where := `
+		FROM actors a
+		JOIN drama_crew cr ON cr.actor_id = a.id
+		JOIN dramas d ON d.id = cr.drama_id
+		WHERE a.deleted_at IS NULL AND d.deleted_at IS NULL AND d.publication_status = 'published'
+	`
+	args := []interface{}{}
+	argId := 1
+	if job != "" {
+		where += fmt.Sprintf(" AND cr.job = $%d", argId)
+		args = append(args, job)
+		argId++
+	}
+	if search != "" {
+		where += fmt.Sprintf(" AND a.name ILIKE $%d", argId)
+		args = append(args, "%"+search+"%")
+		argId++
+	}
+
+	var total int64
+	if err := db.QueryRow(ctx, "SELECT COUNT(DISTINCT a.id) "+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT a.id, a.name, a.slug, a.photo_url, a.created_at, array_agg(DISTINCT cr.job ORDER BY cr.job), COUNT(*)
+		%s
+		GROUP BY a.id
+		ORDER BY a.name ASC
+		LIMIT $%d OFFSET $%d
+	`, where, argId, argId+1)
+	args = append(args, limit, offset)
+
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	crew := []CrewMember{}
+	for rows.Next() {
+		var m CrewMember
+		var photoURL *string
+		if err := rows.Scan(&m.ID, &m.Name, &m.Slug, &photoURL, &m.CreatedAt, &m.Jobs, &m.CreditCount); err != nil {
+			return nil, 0, err
+		}
+		if photoURL != nil {
+			m.PhotoURL = *photoURL
+		}
+		crew = append(crew, m)
+	}
+	return crew, total, rows.Err()
+}
+
+func (r *repository) FindCrewCredits(ctx context.Context, id string) ([]CrewCredit, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	query := `
+		SELECT d.id, d.title, d.slug, d.poster_url, d.year, cr.job
+		FROM drama_crew cr
+		JOIN dramas d ON d.id = cr.drama_id
+		WHERE cr.actor_id = $1 AND d.deleted_at IS NULL AND d.publication_status = 'published'
+		ORDER BY d.year DESC, d.title, cr.job
+	`
+	rows, err := db.Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	credits := []CrewCredit{}
+	for rows.Next() {
+		var cc CrewCredit
+		var poster *string
+		if err := rows.Scan(&cc.DramaID, &cc.Title, &cc.Slug, &poster, &cc.Year, &cc.Job); err != nil {
+			return nil, err
+		}
+		if poster != nil {
+			cc.PosterURL = *poster
+		}
+		credits = append(credits, cc)
+	}
+	return credits, rows.Err()
+}
+
+// filmographySorts maps the sort option to its ORDER BY clause
+var filmographySorts = map[string]string{
+	"latest": "d.year DESC, d.title ASC",
+	"oldest": "d.year ASC, d.title ASC",
+	"rating": "d.rating DESC NULLS LAST, d.year DESC",
+	"title":  "d.title ASC",
+}
+
+func (r *repository) FindFilmography(ctx context.Context, id string, limit, offset int, sort string) ([]FilmographyEntry, int64, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, 0, errors.New("database not connected")
+	}
+
+	orderBy, ok := filmographySorts[sort]
+	if !ok {
+		orderBy = filmographySorts["latest"]
+	}
+
+	where := `
+		FROM drama_actors da
+		JOIN dramas d ON d.id = da.drama_id
+		WHERE da.actor_id = $1 AND d.deleted_at IS NULL AND d.publication_status = 'published'
+	`
+
+	var total int64
+	if err := db.QueryRow(ctx, "SELECT COUNT(DISTINCT d.id) "+where, id).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT d.id, d.title, d.slug, d.poster_url, d.year, COALESCE(d.rating, 0)::float8, d.status,
+		       CASE WHEN bool_or(da.role = 'main') THEN 'main' ELSE 'support' END,
+		       COALESCE(array_agg(da.character_name ORDER BY da.billing_order) FILTER (WHERE da.character_name <> ''), '{}'),
+		       MIN(da.billing_order), bool_and(da.is_cameo), bool_and(da.is_guest)
+		%s
+		GROUP BY d.id
+		ORDER BY %s, d.id
+		LIMIT $2 OFFSET $3
+	`, where, orderBy)
+	rows, err := db.Query(ctx, query, id, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []FilmographyEntry{}
+	for rows.Next() {
+		var e FilmographyEntry
+		var poster *string
+		err := rows.Scan(
+			&e.DramaID, &e.Title, &e.Slug, &poster, &e.Year, &e.Rating, &e.Status,
+			&e.Role, &e.Characters, &e.Billing, &e.IsCameo, &e.IsGuest,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		if poster != nil {
+			e.PosterURL = *poster
+		}
+		entries = append(entries, e)
+	}
+	return entries, total, rows.Err()
+}
+
+func (r *repository) FindCoStars(ctx context.Context, id string, limit int) ([]CoStar, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	query := `
+		SELECT a.id, a.name, a.slug, a.photo_url, a.created_at, COUNT(DISTINCT d.id) AS shared
+		FROM drama_actors me
+		JOIN drama_actors other ON other.drama_id = me.drama_id AND other.actor_id <> me.actor_id
+		JOIN dramas d ON d.id = me.drama_id
+		JOIN actors a ON a.id = other.actor_id
+		WHERE me.actor_id = $1 AND a.deleted_at IS NULL
+		  AND d.deleted_at IS NULL AND d.publication_status = 'published'
+		GROUP BY a.id
+		ORDER BY shared DESC, a.name ASC
+		LIMIT $2
+	`
+	rows, err := db.Query(ctx, query, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	coStars := []CoStar{}
+	for rows.Next() {
+		var cs CoStar
+		var photoURL *string
+		if err := rows.Scan(&cs.ID, &cs.Name, &cs.Slug, &photoURL, &cs.CreatedAt, &cs.SharedDramas); err != nil {
+			return nil, err
+		}
+		if photoURL != nil {
+			cs.PhotoURL = *photoURL
+		}
+		coStars = append(coStars, cs)
+	}
+	return coStars, rows.Err()
+}
@@ -2,6 +2,8 @@ package actor
 
 import (
 	"context"
+	"drakor-backend/pkg/patch"
+	"drakor-backend/pkg/validator"
 	"errors"
 )
 
@@ -10,7 +12,20 @@ type Service interface {
 	GetByID(ctx context.Context, id string) (*Actor, error)
 	Create(ctx context.Context, req CreateActorRequest) (*Actor, error)
 	Update(ctx context.Context, id string, req UpdateActorRequest) (*Actor, error)
+	Patch(ctx context.Context, id string, req PatchActorRequest) (*Actor, error)
 	Delete(ctx context.Context, id string) error
+	// GetCrew lists people credited behind the camera; job narrows it to director, screenwriter, composer or producer
+	GetCrew(ctx context.Context, page, limit int, job, search string) ([]CrewMember, int64, error)
+	// GetCrewProfile accepts the person UUID or slug and includes their crew credits
+	GetCrewProfile(ctx context.Context, id string) (*CrewProfile, error)
+	// GetFilmography accepts the actor UUID or slug; sort is latest, oldest, rating or title
+	GetFilmography(ctx context.Context, id string, page, limit int, sort string) ([]FilmographyEntry, int64, error)
+	// GetCoStars accepts the actor UUID or slug and returns their most frequent co-stars
+	GetCoStars(ctx context.Context, id string, limit int) ([]CoStar, error)
+	// AddPhoto accepts the actor UUID or slug; a primary photo also becomes photo_url
+	AddPhoto(ctx context.Context, actorID string, req PhotoRequest) (*Photo, error)
+	UpdatePhoto(ctx context.Context, actorID, photoID string, req PhotoRequest) (*Photo, error)
+	DeletePhoto(ctx context.Context, actorID, photoID string) error
 }
 
 type service struct {
@@ -32,21 +47,46 @@ func (s *service) GetAll(ctx context.Context, page, limit int, search string) ([
 	return s.repo.FindAll(ctx, limit, offset, search)
 }
 
+// GetByID accepts either the actor UUID or its (current or former) slug
 func (s *service) GetByID(ctx context.Context, id string) (*Actor, error) {
-	return s.repo.FindByID(ctx, id)
+	if validator.IsUUID(id) {
+		return s.repo.FindByID(ctx, id)
+	}
+	return s.repo.FindBySlug(ctx, id)
 }
 
 func (s *service) Create(ctx context.Context, req CreateActorRequest) (*Actor, error) {
+	slug, err := s.resolveSlug(ctx, req.Slug, req.Name, "")
+	if err != nil {
+		return nil, err
+	}
+
 	actor := &Actor{
-		Name:     req.Name,
-		PhotoURL: req.PhotoURL,
+		Name:        req.Name,
+		NativeName:  req.NativeName,
+		Slug:        slug,
+		PhotoURL:    req.PhotoURL,
+		BirthDate:   req.BirthDate,
+		Gender:      req.Gender,
+		Nationality: req.Nationality,
+		Biography:   req.Biography,
+		Agency:      req.Agency,
+		Height:      req.Height,
+		DebutYear:   req.DebutYear,
+		SocialLinks: req.SocialLinks,
 	}
 
 	if err := s.repo.Create(ctx, actor); err != nil {
 		return nil, err
 	}
+	if actor.PhotoURL != "" {
+		// The first photo becomes the primary one and keeps photo_url as is
+		if err := s.repo.CreatePhoto(ctx, &Photo{ActorID: actor.ID, URL: actor.PhotoURL, IsPrimary: true}); err != nil {
+			return nil, err
+		}
+	}
 
-	return actor, nil
+	return s.repo.FindByID(ctx, actor.ID)
 }
 
 func (s *service) Update(ctx context.Context, id string, req UpdateActorRequest) (*Actor, error) {
@@ -58,8 +98,25 @@ func (s *service) Update(ctx context.Context, id string, req UpdateActorRequest)
 		return nil, errors.New("actor not found")
 	}
 
+	if req.Slug != "" {
+		slug, err := s.resolveSlug(ctx, req.Slug, req.Name, actor.ID)
+		if err != nil {
+			return nil, err
+		}
+		actor.Slug = slug
+	}
+
 	actor.Name = req.Name
+	actor.NativeName = req.NativeName
 	actor.PhotoURL = req.PhotoURL
+	actor.BirthDate = req.BirthDate
+	actor.Gender = req.Gender
+	actor.Nationality = req.Nationality
+	actor.Biography = req.Biography
+	actor.Agency = req.Agency
+	actor.Height = req.Height
+	actor.DebutYear = req.DebutYear
+	actor.SocialLinks = req.SocialLinks
 
 	if err := s.repo.Update(ctx, actor); err != nil {
 		return nil, err
@@ -68,6 +125,202 @@ func (s *service) Update(ctx context.Context, id string, req UpdateActorRequest)
 	return actor, nil
 }
 
+func (s *service) Patch(ctx context.Context, id string, req PatchActorRequest) (*Actor, error) {
+	actor, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if actor == nil {
+		return nil, errors.New("actor not found")
+	}
+
+	fields := map[string]interface{}{}
+	if req.Name.Set {
+		fields["name"] = req.Name.Value
+	}
+	if req.Slug.Set {
+		slug, err := s.resolveSlug(ctx, req.Slug.Value, actor.Name, actor.ID)
+		if err != nil {
+			return nil, err
+		}
+		fields["slug"] = slug
+	}
+	if req.PhotoURL.Set {
+		fields["photo_url"] = req.PhotoURL.Column()
+	}
+	if req.NativeName.Set {
+		fields["native_name"] = optional(req.NativeName)
+	}
+	if req.BirthDate.Set {
+		fields["birth_date"] = optional(req.BirthDate)
+	}
+	if req.Gender.Set {
+		fields["gender"] = optional(req.Gender)
+	}
+	if req.Nationality.Set {
+		fields["nationality"] = optional(req.Nationality)
+	}
+	if req.Biography.Set {
+		fields["biography"] = req.Biography.Column()
+	}
+	if req.Agency.Set {
+		fields["agency"] = optional(req.Agency)
+	}
+	if req.Height.Set {
+		fields["height_cm"] = optional(req.Height)
+	}
+	if req.DebutYear.Set {
+		fields["debut_year"] = optional(req.DebutYear)
+	}
+	if req.SocialLinks.Set {
+		// null clears every link
+		fields["social_links"] = socialLinks(req.SocialLinks.Value)
+	}
+
+	if err := s.repo.Patch(ctx, actor.ID, fields); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(ctx, actor.ID)
+}
+
 func (s *service) Delete(ctx context.Context, id string) error {
 	return s.repo.Delete(ctx, id)
 }
+
+// optional stores null for both an explicit null and the zero value, which
+// these columns use to mean "unknown"
+func optional[T comparable](f patch.Field[T]) interface{} {
+	var zero T
+	if f.Null || f.Value == zero {
+		return nil
+	}
+	return f.Value
+}
+
+// resolveSlug validates a requested slug or generates a unique one from the name
+func (s *service) resolveSlug(ctx context.Context, requested, name, excludeID string) (string, error) {
+	return validator.ResolveSlug(requested, name, "actor", nil, func(slug string) (bool, error) {
+		return s.repo.SlugExists(ctx, slug, excludeID)
+	})
+}
+
+func (s *service) GetCrew(ctx context.Context, page, limit int, job, search string) ([]CrewMember, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+	return s.repo.FindCrew(ctx, limit, offset, job, search)
+}
+
+func (s *service) GetCrewProfile(ctx context.Context, id string) (*CrewProfile, error) {
+	person, err := s.GetByID(ctx, id)
+	if err != nil || person == nil {
+		return nil, err
+	}
+
+	credits, err := s.repo.FindCrewCredits(ctx, person.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &CrewProfile{Actor: *person, Credits: credits}, nil
+}
+
+func (s *service) GetFilmography(ctx context.Context, id string, page, limit int, sort string) ([]FilmographyEntry, int64, error) {
+	actor, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if actor == nil {
+		return nil, 0, errors.New("actor not found")
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+	return s.repo.FindFilmography(ctx, actor.ID, limit, offset, sort)
+}
+
+func (s *service) GetCoStars(ctx context.Context, id string, limit int) ([]CoStar, error) {
+	actor, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if actor == nil {
+		return nil, errors.New("actor not found")
+	}
+
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 50 {
+		limit = 50
+	}
+	return s.repo.FindCoStars(ctx, actor.ID, limit)
+}
+
+func (s *service) AddPhoto(ctx context.Context, actorID string, req PhotoRequest) (*Photo, error) {
+	actor, err := s.GetByID(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if actor == nil {
+		return nil, errors.New("actor not found")
+	}
+
+	p := &Photo{ActorID: actor.ID, URL: req.URL, Caption: req.Caption, Position: req.Position, IsPrimary: req.IsPrimary}
+	if err := s.repo.CreatePhoto(ctx, p); err != nil {
+		return nil, err
+	}
+	return s.repo.FindPhoto(ctx, p.ID)
+}
+
+func (s *service) UpdatePhoto(ctx context.Context, actorID, photoID string, req PhotoRequest) (*Photo, error) {
+	existing, err := s.findPhoto(ctx, actorID, photoID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Photo{ID: existing.ID, ActorID: existing.ActorID, URL: req.URL, Caption: req.Caption, Position: req.Position, IsPrimary: req.IsPrimary}
+	if err := s.repo.UpdatePhoto(ctx, p); err != nil {
+		return nil, err
+	}
+	return s.repo.FindPhoto(ctx, p.ID)
+}
+
+func (s *service) DeletePhoto(ctx context.Context, actorID, photoID string) error {
+	p, err := s.findPhoto(ctx, actorID, photoID)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeletePhoto(ctx, p.ID)
+}
+
+// findPhoto loads a photo that belongs to the given actor (UUID or slug)
+func (s *service) findPhoto(ctx context.Context, actorID, photoID string) (*Photo, error) {
+	actor, err := s.GetByID(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if actor == nil {
+		return nil, errors.New("actor not found")
+	}
+	if !validator.IsUUID(photoID) {
+		return nil, errors.New("photo not found")
+	}
+	p, err := s.repo.FindPhoto(ctx, photoID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil || p.ActorID != actor.ID {
+		return nil, errors.New("photo not found")
+	}
+	return p, nil
+}
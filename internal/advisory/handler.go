@@ -0,0 +1,209 @@
+package advisory
+
+import (
+	"drakor-backend/pkg/response"
+	"drakor-backend/pkg/validator"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) GetAll(c *gin.Context) {
+	advisories, err := h.service.GetAll(c.Request.Context())
+	if err != nil {
+		response.InternalError(c, "Failed to fetch advisories", err.Error())
+		return
+	}
+	response.Success(c, "Advisories retrieved successfully", advisories)
+}
+
+func (h *Handler) GetByID(c *gin.Context) {
+	advisory, err := h.service.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.InternalError(c, "Failed to fetch advisory", err.Error())
+		return
+	}
+	if advisory == nil {
+		response.NotFound(c, "Advisory not found")
+		return
+	}
+	response.Success(c, "Advisory detail", advisory)
+}
+
+func (h *Handler) Create(c *gin.Context) {
+	var req CreateAdvisoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	advisory, err := h.service.Create(c.Request.Context(), req)
+	if err != nil {
+		if err.Error() == "slug already exists" {
+			response.Error(c, http.StatusConflict, err.Error(), "slug_exists")
+			return
+		}
+		if err.Error() == "invalid slug" {
+			response.BadRequest(c, "Invalid slug", "validation_error")
+			return
+		}
+		response.InternalError(c, "Failed to create advisory", err.Error())
+		return
+	}
+	response.Created(c, "Advisory created successfully", advisory)
+}
+
+func (h *Handler) Update(c *gin.Context) {
+	var req UpdateAdvisoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	advisory, err := h.service.Update(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		if err.Error() == "advisory not found" {
+			response.NotFound(c, "Advisory not found")
+			return
+		}
+		if err.Error() == "slug already exists" {
+			response.Error(c, http.StatusConflict, err.Error(), "slug_exists")
+			return
+		}
+		if err.Error() == "invalid slug" {
+			response.BadRequest(c, "Invalid slug", "validation_error")
+			return
+		}
+		response.InternalError(c, "Failed to update advisory", err.Error())
+		return
+	}
+	response.Success(c, "Advisory updated successfully", advisory)
+}
+
+func (h *Handler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		response.InternalError(c, "Failed to delete advisory", err.Error())
+		return
+	}
+	response.Success(c, "Advisory deleted successfully", nil)
+}
+
+func (h *Handler) SetDramaAdvisories(c *gin.Context) {
+	var req SetAdvisoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	advisories, err := h.service.SetDramaAdvisories(c.Request.Context(), c.Param("id"), req.AdvisoryIDs)
+	if err != nil {
+		if err.Error() == "drama not found" {
+			response.NotFound(c, "Drama not found")
+			return
+		}
+		if err.Error() == "advisory not found" {
+			response.BadRequest(c, "Unknown advisory", "advisory_not_found")
+			return
+		}
+		response.InternalError(c, "Failed to update drama advisories", err.Error())
+		return
+	}
+	response.Success(c, "Drama advisories updated successfully", advisories)
+}
+
+func (h *Handler) SetEpisodeAdvisories(c *gin.Context) {
+	var req SetAdvisoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	advisories, err := h.service.SetEpisodeAdvisories(c.Request.Context(), c.Param("id"), req.AdvisoryIDs)
+	if err != nil {
+		if err.Error() == "episode not found" {
+			response.NotFound(c, "Episode not found")
+			return
+		}
+		if err.Error() == "advisory not found" {
+			response.BadRequest(c, "Unknown advisory", "advisory_not_found")
+			return
+		}
+		response.InternalError(c, "Failed to update episode advisories", err.Error())
+		return
+	}
+	response.Success(c, "Episode advisories updated successfully", advisories)
+}
+
+// GetPreferences returns the advisories hidden from the current user's listings
+func (h *Handler) GetPreferences(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	prefs, err := h.service.GetPreferences(c.Request.Context(), userID.(string))
+	if err != nil {
+		response.InternalError(c, "Failed to fetch advisory preferences", err.Error())
+		return
+	}
+	response.Success(c, "Advisory preferences retrieved successfully", prefs)
+}
+
+func (h *Handler) UpdatePreferences(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	var req UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	prefs, err := h.service.UpdatePreferences(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		if err.Error() == "advisory not found" {
+			response.BadRequest(c, "Unknown advisory", "advisory_not_found")
+			return
+		}
+		response.InternalError(c, "Failed to update advisory preferences", err.Error())
+		return
+	}
+	response.Success(c, "Advisory preferences updated successfully", prefs)
+}
@@ -0,0 +1,35 @@
+package advisory
+
+// Advisory is a content warning from the controlled vocabulary, e.g. violence or flashing lights
+type Advisory struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Description string `json:"description,omitempty"`
+}
+
+type CreateAdvisoryRequest struct {
+	Name        string `json:"name" validate:"required,min=2,max=100"`
+	Slug        string `json:"slug" validate:"omitempty,min=2,max=100"` // Auto-generated from name if empty
+	Description string `json:"description" validate:"max=500"`
+}
+
+type UpdateAdvisoryRequest struct {
+	Name        string `json:"name" validate:"required,min=2,max=100"`
+	Slug        string `json:"slug" validate:"omitempty,min=2,max=100"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+// SetAdvisoriesRequest replaces every advisory on a drama or episode
+type SetAdvisoriesRequest struct {
+	AdvisoryIDs []string `json:"advisory_ids" validate:"dive,uuid"`
+}
+
+// Preferences are the advisories a user does not want to see in listings
+type Preferences struct {
+	ExcludedAdvisories []Advisory `json:"excluded_advisories"`
+}
+
+type UpdatePreferencesRequest struct {
+	ExcludedAdvisories []string `json:"excluded_advisories" validate:"dive,uuid"` // Advisory IDs
+}
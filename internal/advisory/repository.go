@@ -0,0 +1,256 @@
+package advisory
+
+import (
+	"context"
+	"drakor-backend/pkg/database"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type Repository interface {
+	FindAll(ctx context.Context) ([]Advisory, error)
+	FindByID(ctx context.Context, id string) (*Advisory, error)
+	FindBySlug(ctx context.Context, slug string) (*Advisory, error)
+	Create(ctx context.Context, advisory *Advisory) error
+	Update(ctx context.Context, advisory *Advisory) error
+	Delete(ctx context.Context, id string) error
+	CountAdvisories(ctx context.Context, ids []string) (int, error)
+	DramaExists(ctx context.Context, dramaID string) (bool, error)
+	EpisodeExists(ctx context.Context, episodeID string) (bool, error)
+	FindByDrama(ctx context.Context, dramaID string) ([]Advisory, error)
+	FindByEpisode(ctx context.Context, episodeID string) ([]Advisory, error)
+	FindExcluded(ctx context.Context, userID string) ([]Advisory, error)
+	SetDramaAdvisories(ctx context.Context, dramaID string, advisoryIDs []string) error
+	SetEpisodeAdvisories(ctx context.Context, episodeID string, advisoryIDs []string) error
+	SetExcluded(ctx context.Context, userID string, advisoryIDs []string) error
+}
+
+type repository struct{}
+
+func NewRepository() Repository {
+	return &repository{}
+}
+
+func scanAdvisories(rows pgx.Rows) ([]Advisory, error) {
+	defer rows.Close()
+
+	advisories := []Advisory{}
+	for rows.Next() {
+		var a Advisory
+		var description *string
+		if err := rows.Scan(&a.ID, &a.Name, &a.Slug, &description); err != nil {
+			return nil, err
+		}
+		if description != nil {
+			a.Description = *description
+		}
+		advisories = append(advisories, a)
+	}
+	return advisories, rows.Err()
+}
+
+func (r *repository) FindAll(ctx context.Context) ([]Advisory, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	rows, err := db.Query(ctx, "SELECT id, name, slug, description FROM advisories ORDER BY display_order, name")
+	if err != nil {
+		return nil, err
+	}
+	return scanAdvisories(rows)
+}
+
+func (r *repository) FindByID(ctx context.Context, id string) (*Advisory, error) {
+	return r.findOne(ctx, "id", id)
+}
+
+func (r *repository) FindBySlug(ctx context.Context, slug string) (*Advisory, error) {
+	return r.findOne(ctx, "slug", slug)
+}
+
+func (r *repository) findOne(ctx context.Context, column, value string) (*Advisory, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	var a Advisory
+	var description *string
+	err := db.QueryRow(ctx, "SELECT id, name, slug, description FROM advisories WHERE "+column+" = $1", value).Scan(&a.ID, &a.Name, &a.Slug, &description)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if description != nil {
+		a.Description = *description
+	}
+	return &a, nil
+}
+
+// Create appends the advisory to the end of the list
+func (r *repository) Create(ctx context.Context, advisory *Advisory) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	query := `
+		INSERT INTO advisories (name, slug, description, display_order)
+		VALUES ($1, $2, NULLIF($3, ''), (SELECT COALESCE(MAX(display_order), 0) + 1 FROM advisories))
+		RETURNING id
+	`
+	return db.QueryRow(ctx, query, advisory.Name, advisory.Slug, advisory.Description).Scan(&advisory.ID)
+}
+
+func (r *repository) Update(ctx context.Context, advisory *Advisory) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	query := `UPDATE advisories SET name = $1, slug = $2, description = NULLIF($3, '') WHERE id = $4`
+	_, err := db.Exec(ctx, query, advisory.Name, advisory.Slug, advisory.Description, advisory.ID)
+	return err
+}
+
+// Delete removes the advisory from every drama and episode, bumping their updated_at
+func (r *repository) Delete(ctx context.Context, id string) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	if _, err := tx.Exec(ctx, "UPDATE dramas SET updated_at = $1 WHERE id IN (SELECT drama_id FROM drama_advisories WHERE advisory_id = $2)", now, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "UPDATE episodes SET updated_at = $1 WHERE id IN (SELECT episode_id FROM episode_advisories WHERE advisory_id = $2)", now, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM advisories WHERE id = $1", id); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *repository) CountAdvisories(ctx context.Context, ids []string) (int, error) {
+	db := database.GetDB()
+	if db == nil {
+		return 0, errors.New("database not connected")
+	}
+
+	var count int
+	err := db.QueryRow(ctx, "SELECT COUNT(*) FROM advisories WHERE id = ANY($1)", ids).Scan(&count)
+	return count, err
+}
+
+func (r *repository) DramaExists(ctx context.Context, dramaID string) (bool, error) {
+	db := database.GetDB()
+	if db == nil {
+		return false, errors.New("database not connected")
+	}
+
+	var exists bool
+	err := db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM dramas WHERE id = $1 AND deleted_at IS NULL)", dramaID).Scan(&exists)
+	return exists, err
+}
+
+func (r *repository) EpisodeExists(ctx context.Context, episodeID string) (bool, error) {
+	db := database.GetDB()
+	if db == nil {
+		return false, errors.New("database not connected")
+	}
+
+	var exists bool
+	err := db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM episodes WHERE id = $1 AND deleted_at IS NULL)", episodeID).Scan(&exists)
+	return exists, err
+}
+
+func (r *repository) FindByDrama(ctx context.Context, dramaID string) ([]Advisory, error) {
+	return r.findLinked(ctx, "drama_advisories", "drama_id", dramaID)
+}
+
+func (r *repository) FindByEpisode(ctx context.Context, episodeID string) ([]Advisory, error) {
+	return r.findLinked(ctx, "episode_advisories", "episode_id", episodeID)
+}
+
+func (r *repository) FindExcluded(ctx context.Context, userID string) ([]Advisory, error) {
+	return r.findLinked(ctx, "user_excluded_advisories", "user_id", userID)
+}
+
+// findLinked lists the advisories linked to ownerID through table
+func (r *repository) findLinked(ctx context.Context, table, column, ownerID string) ([]Advisory, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT a.id, a.name, a.slug, a.description
+		FROM advisories a
+		JOIN %s l ON l.advisory_id = a.id
+		WHERE l.%s = $1
+		ORDER BY a.display_order, a.name
+	`, table, column)
+	rows, err := db.Query(ctx, query, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return scanAdvisories(rows)
+}
+
+func (r *repository) SetDramaAdvisories(ctx context.Context, dramaID string, advisoryIDs []string) error {
+	return r.setLinked(ctx, "drama_advisories", "drama_id", "dramas", dramaID, advisoryIDs)
+}
+
+func (r *repository) SetEpisodeAdvisories(ctx context.Context, episodeID string, advisoryIDs []string) error {
+	return r.setLinked(ctx, "episode_advisories", "episode_id", "episodes", episodeID, advisoryIDs)
+}
+
+func (r *repository) SetExcluded(ctx context.Context, userID string, advisoryIDs []string) error {
+	return r.setLinked(ctx, "user_excluded_advisories", "user_id", "", userID, advisoryIDs)
+}
+
+// setLinked replaces the advisories linked to ownerID; when owners is set the
+// owning row's updated_at is bumped too
+func (r *repository) setLinked(ctx context.Context, table, column, owners, ownerID string, advisoryIDs []string) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE %s = $1", table, column), ownerID); err != nil {
+		return err
+	}
+	insert := fmt.Sprintf("INSERT INTO %s (%s, advisory_id) VALUES ($1, $2)", table, column)
+	for _, advisoryID := range advisoryIDs {
+		if _, err := tx.Exec(ctx, insert, ownerID, advisoryID); err != nil {
+			return fmt.Errorf("failed to add advisory %s: %v", advisoryID, err)
+		}
+	}
+	if owners != "" {
+		if _, err := tx.Exec(ctx, fmt.Sprintf("UPDATE %s SET updated_at = $1 WHERE id = $2", owners), time.Now(), ownerID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
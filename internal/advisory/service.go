@@ -0,0 +1,166 @@
+package advisory
+
+import (
+	"context"
+	"drakor-backend/pkg/validator"
+	"errors"
+)
+
+type Service interface {
+	GetAll(ctx context.Context) ([]Advisory, error)
+	// GetByID accepts the advisory UUID or slug
+	GetByID(ctx context.Context, id string) (*Advisory, error)
+	Create(ctx context.Context, req CreateAdvisoryRequest) (*Advisory, error)
+	Update(ctx context.Context, id string, req UpdateAdvisoryRequest) (*Advisory, error)
+	Delete(ctx context.Context, id string) error
+	// SetDramaAdvisories replaces the advisories on a drama and returns them
+	SetDramaAdvisories(ctx context.Context, dramaID string, advisoryIDs []string) ([]Advisory, error)
+	// SetEpisodeAdvisories replaces the advisories on an episode and returns them
+	SetEpisodeAdvisories(ctx context.Context, episodeID string, advisoryIDs []string) ([]Advisory, error)
+	GetPreferences(ctx context.Context, userID string) (*Preferences, error)
+	UpdatePreferences(ctx context.Context, userID string, req UpdatePreferencesRequest) (*Preferences, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) GetAll(ctx context.Context) ([]Advisory, error) {
+	return s.repo.FindAll(ctx)
+}
+
+func (s *service) GetByID(ctx context.Context, id string) (*Advisory, error) {
+	if validator.IsUUID(id) {
+		return s.repo.FindByID(ctx, id)
+	}
+	return s.repo.FindBySlug(ctx, id)
+}
+
+func (s *service) Create(ctx context.Context, req CreateAdvisoryRequest) (*Advisory, error) {
+	slug, err := s.resolveSlug(ctx, req.Slug, req.Name, "")
+	if err != nil {
+		return nil, err
+	}
+
+	advisory := &Advisory{Name: req.Name, Slug: slug, Description: req.Description}
+	if err := s.repo.Create(ctx, advisory); err != nil {
+		return nil, err
+	}
+	return advisory, nil
+}
+
+func (s *service) Update(ctx context.Context, id string, req UpdateAdvisoryRequest) (*Advisory, error) {
+	existing, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, errors.New("advisory not found")
+	}
+
+	slug, err := s.resolveSlug(ctx, req.Slug, req.Name, existing.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	advisory := &Advisory{ID: existing.ID, Name: req.Name, Slug: slug, Description: req.Description}
+	if err := s.repo.Update(ctx, advisory); err != nil {
+		return nil, err
+	}
+	return advisory, nil
+}
+
+func (s *service) Delete(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *service) SetDramaAdvisories(ctx context.Context, dramaID string, advisoryIDs []string) ([]Advisory, error) {
+	exists, err := s.repo.DramaExists(ctx, dramaID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.New("drama not found")
+	}
+
+	unique, err := s.checkAdvisories(ctx, advisoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetDramaAdvisories(ctx, dramaID, unique); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByDrama(ctx, dramaID)
+}
+
+func (s *service) SetEpisodeAdvisories(ctx context.Context, episodeID string, advisoryIDs []string) ([]Advisory, error) {
+	exists, err := s.repo.EpisodeExists(ctx, episodeID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.New("episode not found")
+	}
+
+	unique, err := s.checkAdvisories(ctx, advisoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetEpisodeAdvisories(ctx, episodeID, unique); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByEpisode(ctx, episodeID)
+}
+
+func (s *service) GetPreferences(ctx context.Context, userID string) (*Preferences, error) {
+	excluded, err := s.repo.FindExcluded(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &Preferences{ExcludedAdvisories: excluded}, nil
+}
+
+func (s *service) UpdatePreferences(ctx context.Context, userID string, req UpdatePreferencesRequest) (*Preferences, error) {
+	unique, err := s.checkAdvisories(ctx, req.ExcludedAdvisories)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetExcluded(ctx, userID, unique); err != nil {
+		return nil, err
+	}
+	return s.GetPreferences(ctx, userID)
+}
+
+// checkAdvisories drops duplicate IDs and rejects unknown advisories
+func (s *service) checkAdvisories(ctx context.Context, ids []string) ([]string, error) {
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) > 0 {
+		count, err := s.repo.CountAdvisories(ctx, unique)
+		if err != nil {
+			return nil, err
+		}
+		if count != len(unique) {
+			return nil, errors.New("advisory not found")
+		}
+	}
+	return unique, nil
+}
+
+// resolveSlug validates a requested slug or generates a unique one from the name
+func (s *service) resolveSlug(ctx context.Context, requested, name, excludeID string) (string, error) {
+	return validator.ResolveSlug(requested, name, "advisory", nil, func(slug string) (bool, error) {
+		existing, err := s.repo.FindBySlug(ctx, slug)
+		return existing != nil && existing.ID != excludeID, err
+	})
+}
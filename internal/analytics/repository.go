@@ -28,14 +28,14 @@ func (r *repository) GetDashboardStats(ctx context.Context) (*DashboardStats, er
 	if err := db.QueryRow(ctx, "SELECT COUNT(*) FROM users").Scan(&stats.TotalUsers); err != nil {
 		return nil, err
 	}
-	if err := db.QueryRow(ctx, "SELECT COUNT(*) FROM dramas").Scan(&stats.TotalDramas); err != nil {
+	if err := db.QueryRow(ctx, "SELECT COUNT(*) FROM dramas WHERE deleted_at IS NULL").Scan(&stats.TotalDramas); err != nil {
 		return nil, err
 	}
-	if err := db.QueryRow(ctx, "SELECT COUNT(*) FROM episodes").Scan(&stats.TotalEpisodes); err != nil {
+	if err := db.QueryRow(ctx, "SELECT COUNT(*) FROM episodes WHERE deleted_at IS NULL").Scan(&stats.TotalEpisodes); err != nil {
 		return nil, err
 	}
 	// Total views could be sum of view_count from dramas or episodes
-	if err := db.QueryRow(ctx, "SELECT COALESCE(SUM(view_count), 0) FROM dramas").Scan(&stats.TotalViews); err != nil {
+	if err := db.QueryRow(ctx, "SELECT COALESCE(SUM(view_count), 0) FROM dramas WHERE deleted_at IS NULL").Scan(&stats.TotalViews); err != nil {
 		return nil, err
 	}
 
@@ -52,3 +52,24 @@ func AdminMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// OptionalMiddleware sets user info when a valid token is present but never rejects the request
+func OptionalMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := jwt.ExtractTokenFromHeader(c.GetHeader("Authorization"))
+		if tokenString != "" {
+			if claims, err := jwt.ValidateToken(tokenString); err == nil {
+				c.Set("userID", claims.UserID)
+				c.Set("userEmail", claims.Email)
+				c.Set("userRole", claims.Role)
+			}
+		}
+		c.Next()
+	}
+}
+
+// CanPreview reports whether an admin asked to see unpublished content (?preview=true)
+func CanPreview(c *gin.Context) bool {
+	role, exists := c.Get("userRole")
+	return exists && role == "admin" && c.Query("preview") == "true"
+}
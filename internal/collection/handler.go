@@ -0,0 +1,127 @@
+package collection
+
+import (
+	"drakor-backend/internal/auth"
+	"drakor-backend/pkg/response"
+	"drakor-backend/pkg/validator"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetAll lists the collections visible now; admins may add ?preview=true to see all
+func (h *Handler) GetAll(c *gin.Context) {
+	collections, err := h.service.GetAll(c.Request.Context(), auth.CanPreview(c))
+	if err != nil {
+		response.InternalError(c, "Failed to fetch collections", err.Error())
+		return
+	}
+	response.Success(c, "Collections retrieved successfully", collections)
+}
+
+func (h *Handler) GetByID(c *gin.Context) {
+	collection, err := h.service.GetByID(c.Request.Context(), c.Param("id"), auth.CanPreview(c))
+	if err != nil {
+		response.InternalError(c, "Failed to fetch collection", err.Error())
+		return
+	}
+	if collection == nil {
+		response.NotFound(c, "Collection not found")
+		return
+	}
+	response.Success(c, "Collection detail", collection)
+}
+
+func (h *Handler) Create(c *gin.Context) {
+	var req CreateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	collection, err := h.service.Create(c.Request.Context(), req)
+	if err != nil {
+		h.writeError(c, err, "Failed to create collection")
+		return
+	}
+	response.Created(c, "Collection created successfully", collection)
+}
+
+func (h *Handler) Update(c *gin.Context) {
+	var req UpdateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	collection, err := h.service.Update(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		h.writeError(c, err, "Failed to update collection")
+		return
+	}
+	response.Success(c, "Collection updated successfully", collection)
+}
+
+func (h *Handler) SetDramas(c *gin.Context) {
+	var req SetDramasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	collection, err := h.service.SetDramas(c.Request.Context(), c.Param("id"), req.DramaIDs)
+	if err != nil {
+		h.writeError(c, err, "Failed to update collection dramas")
+		return
+	}
+	response.Success(c, "Collection dramas updated successfully", collection)
+}
+
+func (h *Handler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		response.InternalError(c, "Failed to delete collection", err.Error())
+		return
+	}
+	response.Success(c, "Collection deleted successfully", nil)
+}
+
+func (h *Handler) writeError(c *gin.Context, err error, message string) {
+	switch err.Error() {
+	case "collection not found":
+		response.NotFound(c, "Collection not found")
+	case "slug already exists":
+		response.Error(c, http.StatusConflict, err.Error(), "slug_exists")
+	case "invalid slug":
+		response.BadRequest(c, "Invalid slug", "validation_error")
+	case "collection is rule-based":
+		response.Error(c, http.StatusConflict, "Dramas of a rule-based collection come from its rule", "rule_based")
+	case "drama not found", "visible_until must be after visible_from",
+		"rule is required for rule-based collections", "rule year_to must not be before year_from":
+		response.BadRequest(c, err.Error(), "validation_error")
+	default:
+		response.InternalError(c, message, err.Error())
+	}
+}
@@ -0,0 +1,57 @@
+package collection
+
+import (
+	"drakor-backend/internal/drama"
+	"time"
+)
+
+type Collection struct {
+	ID           string        `json:"id"`
+	Title        string        `json:"title"`
+	Slug         string        `json:"slug"`
+	Description  string        `json:"description"`
+	Type         string        `json:"type"`           // 'manual', 'rule'
+	Rule         *Rule         `json:"rule,omitempty"` // Only for rule-based collections
+	VisibleFrom  *time.Time    `json:"visible_from"`
+	VisibleUntil *time.Time    `json:"visible_until"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+	Dramas       []drama.Drama `json:"dramas,omitempty"`
+}
+
+// Rule selects published dramas for a rule-based collection; empty criteria match everything
+type Rule struct {
+	GenreID   string  `json:"genre_id,omitempty" validate:"omitempty,uuid"`
+	YearFrom  int     `json:"year_from,omitempty" validate:"omitempty,min=1900,max=2100"`
+	YearTo    int     `json:"year_to,omitempty" validate:"omitempty,min=1900,max=2100"`
+	MinRating float64 `json:"min_rating,omitempty" validate:"min=0,max=10"`
+	Sort      string  `json:"sort,omitempty" validate:"omitempty,oneof=rating popular latest"` // Defaults to rating
+	Limit     int     `json:"limit,omitempty" validate:"omitempty,min=1,max=100"`              // Defaults to 20
+}
+
+type CreateCollectionRequest struct {
+	Title        string     `json:"title" validate:"required,min=2,max=255"`
+	Slug         string     `json:"slug" validate:"omitempty,min=2,max=255"` // Auto-generated from title if empty
+	Description  string     `json:"description"`
+	Type         string     `json:"type" validate:"required,oneof=manual rule"`
+	Rule         *Rule      `json:"rule" validate:"omitempty"`                // Required for rule-based collections
+	DramaIDs     []string   `json:"drama_ids" validate:"omitempty,dive,uuid"` // Manual collections, in display order
+	VisibleFrom  *time.Time `json:"visible_from"`
+	VisibleUntil *time.Time `json:"visible_until"`
+}
+
+type UpdateCollectionRequest struct {
+	Title        string     `json:"title" validate:"required,min=2,max=255"`
+	Slug         string     `json:"slug" validate:"omitempty,min=2,max=255"`
+	Description  string     `json:"description"`
+	Type         string     `json:"type" validate:"required,oneof=manual rule"`
+	Rule         *Rule      `json:"rule" validate:"omitempty"`
+	DramaIDs     []string   `json:"drama_ids" validate:"omitempty,dive,uuid"`
+	VisibleFrom  *time.Time `json:"visible_from"`
+	VisibleUntil *time.Time `json:"visible_until"`
+}
+
+// SetDramasRequest replaces the members of a manual collection, in display order
+type SetDramasRequest struct {
+	DramaIDs []string `json:"drama_ids" validate:"dive,uuid"`
+}
@@ -0,0 +1,301 @@
+package collection
+
+import (
+	"context"
+	"drakor-backend/internal/drama"
+	"drakor-backend/pkg/database"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type Repository interface {
+	// FindAll lists collections; visibleAt limits them to those shown at that time
+	FindAll(ctx context.Context, visibleAt *time.Time) ([]Collection, error)
+	FindByID(ctx context.Context, id string) (*Collection, error)
+	FindBySlug(ctx context.Context, slug string) (*Collection, error)
+	Create(ctx context.Context, c *Collection, dramaIDs []string) error
+	Update(ctx context.Context, c *Collection, dramaIDs []string) error
+	SetDramas(ctx context.Context, id string, dramaIDs []string) error
+	Delete(ctx context.Context, id string) error
+	// CountDramas counts how many of the ids are dramas that are not trashed
+	CountDramas(ctx context.Context, ids []string) (int, error)
+	// FindDramas lists the published dramas in a collection, in display order
+	FindDramas(ctx context.Context, c *Collection) ([]drama.Drama, error)
+}
+
+type repository struct{}
+
+func NewRepository() Repository {
+	return &repository{}
+}
+
+const collectionColumns = `id, title, slug, description, type, rule, visible_from, visible_until, created_at, updated_at`
+
+func scanCollection(row pgx.Row) (*Collection, error) {
+	var c Collection
+	var description *string
+	err := row.Scan(&c.ID, &c.Title, &c.Slug, &description, &c.Type, &c.Rule, &c.VisibleFrom, &c.VisibleUntil, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if description != nil {
+		c.Description = *description
+	}
+	return &c, nil
+}
+
+func (r *repository) FindAll(ctx context.Context, visibleAt *time.Time) ([]Collection, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	query := "SELECT " + collectionColumns + " FROM collections"
+	args := []interface{}{}
+	if visibleAt != nil {
+		query += " WHERE (visible_from IS NULL OR visible_from <= $1) AND (visible_until IS NULL OR visible_until > $1)"
+		args = append(args, *visibleAt)
+	}
+	query += " ORDER BY created_at DESC"
+
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	collections := []Collection{}
+	for rows.Next() {
+		c, err := scanCollection(rows)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, *c)
+	}
+	return collections, rows.Err()
+}
+
+func (r *repository) FindByID(ctx context.Context, id string) (*Collection, error) {
+	return r.findOne(ctx, "id", id)
+}
+
+func (r *repository) FindBySlug(ctx context.Context, slug string) (*Collection, error) {
+	return r.findOne(ctx, "slug", slug)
+}
+
+func (r *repository) findOne(ctx context.Context, column, value string) (*Collection, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM collections WHERE %s = $1", collectionColumns, column)
+	c, err := scanCollection(db.QueryRow(ctx, query, value))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *repository) Create(ctx context.Context, c *Collection, dramaIDs []string) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	query := `
+		INSERT INTO collections (title, slug, description, type, rule, visible_from, visible_until, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		RETURNING id, created_at, updated_at
+	`
+	err = tx.QueryRow(ctx, query, c.Title, c.Slug, c.Description, c.Type, c.Rule, c.VisibleFrom, c.VisibleUntil, now).
+		Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := replaceDramas(ctx, tx, c.ID, dramaIDs); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *repository) Update(ctx context.Context, c *Collection, dramaIDs []string) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE collections
+		SET title = $1, slug = $2, description = $3, type = $4, rule = $5, visible_from = $6, visible_until = $7, updated_at = $8
+		WHERE id = $9
+	`
+	_, err = tx.Exec(ctx, query, c.Title, c.Slug, c.Description, c.Type, c.Rule, c.VisibleFrom, c.VisibleUntil, time.Now(), c.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := replaceDramas(ctx, tx, c.ID, dramaIDs); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *repository) SetDramas(ctx context.Context, id string, dramaIDs []string) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "UPDATE collections SET updated_at = $1 WHERE id = $2", time.Now(), id); err != nil {
+		return err
+	}
+	if err := replaceDramas(ctx, tx, id, dramaIDs); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// replaceDramas swaps the membership of a collection, numbering positions from 1
+func replaceDramas(ctx context.Context, tx pgx.Tx, collectionID string, dramaIDs []string) error {
+	if _, err := tx.Exec(ctx, "DELETE FROM collection_dramas WHERE collection_id = $1", collectionID); err != nil {
+		return err
+	}
+	for i, dramaID := range dramaIDs {
+		_, err := tx.Exec(ctx, "INSERT INTO collection_dramas (collection_id, drama_id, position) VALUES ($1, $2, $3)", collectionID, dramaID, i+1)
+		if err != nil {
+			return fmt.Errorf("failed to add drama %s: %v", dramaID, err)
+		}
+	}
+	return nil
+}
+
+func (r *repository) Delete(ctx context.Context, id string) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	_, err := db.Exec(ctx, "DELETE FROM collections WHERE id = $1", id)
+	return err
+}
+
+func (r *repository) CountDramas(ctx context.Context, ids []string) (int, error) {
+	db := database.GetDB()
+	if db == nil {
+		return 0, errors.New("database not connected")
+	}
+
+	var count int
+	err := db.QueryRow(ctx, "SELECT COUNT(*) FROM dramas WHERE id = ANY($1) AND deleted_at IS NULL", ids).Scan(&count)
+	return count, err
+}
+
+func (r *repository) FindDramas(ctx context.Context, c *Collection) ([]drama.Drama, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	sql := `SELECT d.id, d.title, d.slug, d.poster_url, d.year, d.rating, d.status, d.view_count, d.publication_status, d.publish_at, d.created_at FROM dramas d`
+	args := []interface{}{}
+	argId := 1
+
+	if c.Type == "rule" {
+		rule := Rule{}
+		if c.Rule != nil {
+			rule = *c.Rule
+		}
+		sql += " WHERE d.deleted_at IS NULL AND d.publication_status = 'published'"
+
+		if rule.GenreID != "" {
+			sql += fmt.Sprintf(" AND d.id IN (SELECT drama_id FROM drama_genres WHERE genre_id = $%d)", argId)
+			args = append(args, rule.GenreID)
+			argId++
+		}
+		if rule.YearFrom > 0 {
+			sql += fmt.Sprintf(" AND d.year >= $%d", argId)
+			args = append(args, rule.YearFrom)
+			argId++
+		}
+		if rule.YearTo > 0 {
+			sql += fmt.Sprintf(" AND d.year <= $%d", argId)
+			args = append(args, rule.YearTo)
+			argId++
+		}
+		if rule.MinRating > 0 {
+			sql += fmt.Sprintf(" AND d.rating >= $%d", argId)
+			args = append(args, rule.MinRating)
+			argId++
+		}
+
+		switch rule.Sort {
+		case "popular":
+			sql += " ORDER BY d.view_count DESC, d.id"
+		case "latest":
+			sql += " ORDER BY d.created_at DESC, d.id"
+		default: // "rating"
+			sql += " ORDER BY d.rating DESC, d.id"
+		}
+
+		limit := rule.Limit
+		if limit < 1 {
+			limit = 20
+		}
+		sql += fmt.Sprintf(" LIMIT $%d", argId)
+		args = append(args, limit)
+	} else {
+		sql += `
+			JOIN collection_dramas cd ON cd.drama_id = d.id
+			WHERE cd.collection_id = $1 AND d.deleted_at IS NULL AND d.publication_status = 'published'
+			ORDER BY cd.position
+		`
+		args = append(args, c.ID)
+	}
+
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dramas := []drama.Drama{}
+	for rows.Next() {
+		var d drama.Drama
+		var poster *string
+		if err := rows.Scan(&d.ID, &d.Title, &d.Slug, &poster, &d.Year, &d.Rating, &d.Status, &d.ViewCount, &d.Publication, &d.PublishAt, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		if poster != nil {
+			d.PosterURL = *poster
+		}
+		dramas = append(dramas, d)
+	}
+	return dramas, rows.Err()
+}
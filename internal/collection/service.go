@@ -0,0 +1,209 @@
+package collection
+
+import (
+	"context"
+	"drakor-backend/pkg/validator"
+	"errors"
+	"time"
+)
+
+type Service interface {
+	// GetAll lists collections; unless preview is set only those visible now
+	GetAll(ctx context.Context, preview bool) ([]Collection, error)
+	// GetByID accepts the collection UUID or slug and includes its dramas
+	GetByID(ctx context.Context, id string, preview bool) (*Collection, error)
+	Create(ctx context.Context, req CreateCollectionRequest) (*Collection, error)
+	Update(ctx context.Context, id string, req UpdateCollectionRequest) (*Collection, error)
+	SetDramas(ctx context.Context, id string, dramaIDs []string) (*Collection, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) GetAll(ctx context.Context, preview bool) ([]Collection, error) {
+	if preview {
+		return s.repo.FindAll(ctx, nil)
+	}
+	now := time.Now()
+	return s.repo.FindAll(ctx, &now)
+}
+
+func (s *service) GetByID(ctx context.Context, id string, preview bool) (*Collection, error) {
+	var c *Collection
+	var err error
+	if validator.IsUUID(id) {
+		c, err = s.repo.FindByID(ctx, id)
+	} else {
+		c, err = s.repo.FindBySlug(ctx, id)
+	}
+	if err != nil || c == nil {
+		return nil, err
+	}
+	if !preview && !visible(c, time.Now()) {
+		return nil, nil
+	}
+
+	c.Dramas, err = s.repo.FindDramas(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *service) Create(ctx context.Context, req CreateCollectionRequest) (*Collection, error) {
+	c := &Collection{
+		Title:        req.Title,
+		Description:  req.Description,
+		Type:         req.Type,
+		Rule:         req.Rule,
+		VisibleFrom:  req.VisibleFrom,
+		VisibleUntil: req.VisibleUntil,
+	}
+
+	slug, err := s.resolveSlug(ctx, req.Slug, req.Title, "")
+	if err != nil {
+		return nil, err
+	}
+	c.Slug = slug
+
+	dramaIDs, err := s.prepare(ctx, c, req.DramaIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, c, dramaIDs); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, c.ID, true)
+}
+
+func (s *service) Update(ctx context.Context, id string, req UpdateCollectionRequest) (*Collection, error) {
+	c, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, errors.New("collection not found")
+	}
+
+	c.Title = req.Title
+	c.Description = req.Description
+	c.Type = req.Type
+	c.Rule = req.Rule
+	c.VisibleFrom = req.VisibleFrom
+	c.VisibleUntil = req.VisibleUntil
+
+	slug, err := s.resolveSlug(ctx, req.Slug, req.Title, c.ID)
+	if err != nil {
+		return nil, err
+	}
+	c.Slug = slug
+
+	dramaIDs, err := s.prepare(ctx, c, req.DramaIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, c, dramaIDs); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, c.ID, true)
+}
+
+func (s *service) SetDramas(ctx context.Context, id string, dramaIDs []string) (*Collection, error) {
+	c, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, errors.New("collection not found")
+	}
+	if c.Type != "manual" {
+		return nil, errors.New("collection is rule-based")
+	}
+
+	dramaIDs, err = s.checkDramas(ctx, dramaIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetDramas(ctx, c.ID, dramaIDs); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, c.ID, true)
+}
+
+func (s *service) Delete(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// prepare checks the visibility window and type-specific fields, returning the
+// members to store: manual collections keep their dramas and drop any rule,
+// rule-based collections need a rule and have no members
+func (s *service) prepare(ctx context.Context, c *Collection, dramaIDs []string) ([]string, error) {
+	if c.VisibleFrom != nil && c.VisibleUntil != nil && !c.VisibleUntil.After(*c.VisibleFrom) {
+		return nil, errors.New("visible_until must be after visible_from")
+	}
+
+	if c.Type == "rule" {
+		if c.Rule == nil {
+			return nil, errors.New("rule is required for rule-based collections")
+		}
+		if c.Rule.YearFrom > 0 && c.Rule.YearTo > 0 && c.Rule.YearTo < c.Rule.YearFrom {
+			return nil, errors.New("rule year_to must not be before year_from")
+		}
+		return nil, nil
+	}
+
+	c.Rule = nil
+	return s.checkDramas(ctx, dramaIDs)
+}
+
+// checkDramas drops repeated ids, keeping the first position, and makes sure every drama exists
+func (s *service) checkDramas(ctx context.Context, dramaIDs []string) ([]string, error) {
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, id := range dramaIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 {
+		return unique, nil
+	}
+
+	count, err := s.repo.CountDramas(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+	if count != len(unique) {
+		return nil, errors.New("drama not found")
+	}
+	return unique, nil
+}
+
+// resolveSlug validates a requested slug or generates a unique one from the title
+func (s *service) resolveSlug(ctx context.Context, requested, title, excludeID string) (string, error) {
+	return validator.ResolveSlug(requested, title, "collection", nil, func(slug string) (bool, error) {
+		existing, err := s.repo.FindBySlug(ctx, slug)
+		return existing != nil && existing.ID != excludeID, err
+	})
+}
+
+// visible reports whether the collection's window includes t
+func visible(c *Collection, t time.Time) bool {
+	if c.VisibleFrom != nil && c.VisibleFrom.After(t) {
+		return false
+	}
+	if c.VisibleUntil != nil && !c.VisibleUntil.After(t) {
+		return false
+	}
+	return true
+}
@@ -50,6 +50,10 @@ func (h *Handler) Create(c *gin.Context) {
 
 	comment, err := h.service.Create(c.Request.Context(), userID.(string), req)
 	if err != nil {
+		if err.Error() == "episode not found" {
+			response.NotFound(c, "Episode not found")
+			return
+		}
 		response.InternalError(c, "Failed to create comment", err.Error())
 		return
 	}
@@ -16,8 +16,19 @@ type Repository interface {
 	GetByID(ctx context.Context, id string) (*Comment, error)
 	Update(ctx context.Context, comment *Comment) error
 	Delete(ctx context.Context, id string) error
+	// EpisodeExists reports whether the episode, its season and its drama are all out of the trash
+	EpisodeExists(ctx context.Context, episodeID string) (bool, error)
 }
 
+// liveEpisode holds while neither the episode bound to $1 nor its season or
+// drama is in the trash; comments below a trashed parent are hidden until it is restored
+const liveEpisode = `EXISTS (
+	SELECT 1 FROM episodes e
+	JOIN seasons s ON s.id = e.season_id
+	JOIN dramas d ON d.id = s.drama_id
+	WHERE e.id = $1 AND e.deleted_at IS NULL AND s.deleted_at IS NULL AND d.deleted_at IS NULL
+)`
+
 type repository struct{}
 
 func NewRepository() Repository {
@@ -48,7 +59,7 @@ func (r *repository) GetByEpisodeID(ctx context.Context, episodeID string, limit
 
 	// Count
 	var total int64
-	err := db.QueryRow(ctx, "SELECT COUNT(*) FROM comments WHERE episode_id = $1", episodeID).Scan(&total)
+	err := db.QueryRow(ctx, "SELECT COUNT(*) FROM comments WHERE episode_id = $1 AND "+liveEpisode, episodeID).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -58,7 +69,7 @@ func (r *repository) GetByEpisodeID(ctx context.Context, episodeID string, limit
 		       u.id, u.name, u.avatar_url
 		FROM comments c
 		JOIN users u ON c.user_id = u.id
-		WHERE c.episode_id = $1
+		WHERE c.episode_id = $1 AND ` + liveEpisode + `
 		ORDER BY c.created_at DESC
 		LIMIT $2 OFFSET $3
 	`
@@ -89,6 +100,17 @@ func (r *repository) GetByEpisodeID(ctx context.Context, episodeID string, limit
 	return comments, total, nil
 }
 
+func (r *repository) EpisodeExists(ctx context.Context, episodeID string) (bool, error) {
+	db := database.GetDB()
+	if db == nil {
+		return false, errors.New("database not connected")
+	}
+
+	var exists bool
+	err := db.QueryRow(ctx, "SELECT "+liveEpisode, episodeID).Scan(&exists)
+	return exists, err
+}
+
 func (r *repository) GetByID(ctx context.Context, id string) (*Comment, error) {
 	db := database.GetDB()
 	if db == nil {
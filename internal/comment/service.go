@@ -21,6 +21,14 @@ func NewService(repo Repository) Service {
 }
 
 func (s *service) Create(ctx context.Context, userID string, req CreateCommentRequest) (*Comment, error) {
+	exists, err := s.repo.EpisodeExists(ctx, req.EpisodeID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.New("episode not found")
+	}
+
 	comment := &Comment{
 		UserID:      userID,
 		EpisodeID:   req.EpisodeID,
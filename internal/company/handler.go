@@ -0,0 +1,135 @@
+package company
+
+import (
+	"drakor-backend/pkg/response"
+	"drakor-backend/pkg/validator"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves one company type, so networks and production companies get their own routes
+type Handler struct {
+	service     Service
+	companyType string // 'network', 'production'
+	label       string // Used in messages, e.g. "Network"
+}
+
+func NewHandler(service Service, companyType, label string) *Handler {
+	return &Handler{service: service, companyType: companyType, label: label}
+}
+
+func (h *Handler) GetAll(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	companies, total, err := h.service.GetAll(c.Request.Context(), h.companyType, page, limit, c.Query("search"))
+	if err != nil {
+		response.InternalError(c, "Failed to fetch "+h.label+" list", err.Error())
+		return
+	}
+	response.Paginated(c, companies, total, page, limit)
+}
+
+func (h *Handler) GetByID(c *gin.Context) {
+	company, err := h.service.GetByID(c.Request.Context(), h.companyType, c.Param("id"))
+	if err != nil {
+		response.InternalError(c, "Failed to fetch "+h.label, err.Error())
+		return
+	}
+	if company == nil {
+		response.NotFound(c, h.label+" not found")
+		return
+	}
+	response.Success(c, h.label+" detail", company)
+}
+
+func (h *Handler) Create(c *gin.Context) {
+	var req CreateCompanyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	company, err := h.service.Create(c.Request.Context(), h.companyType, req)
+	if err != nil {
+		if err.Error() == "slug already exists" {
+			response.Error(c, http.StatusConflict, err.Error(), "slug_exists")
+			return
+		}
+		if err.Error() == "invalid slug" {
+			response.BadRequest(c, "Invalid slug", "validation_error")
+			return
+		}
+		response.InternalError(c, "Failed to create "+h.label, err.Error())
+		return
+	}
+	response.Created(c, h.label+" created successfully", company)
+}
+
+func (h *Handler) Update(c *gin.Context) {
+	var req UpdateCompanyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	company, err := h.service.Update(c.Request.Context(), h.companyType, c.Param("id"), req)
+	if err != nil {
+		h.writeError(c, err, "Failed to update "+h.label)
+		return
+	}
+	response.Success(c, h.label+" updated successfully", company)
+}
+
+func (h *Handler) Patch(c *gin.Context) {
+	var req PatchCompanyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	company, err := h.service.Patch(c.Request.Context(), h.companyType, c.Param("id"), req)
+	if err != nil {
+		h.writeError(c, err, "Failed to update "+h.label)
+		return
+	}
+	response.Success(c, h.label+" updated successfully", company)
+}
+
+func (h *Handler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), h.companyType, c.Param("id")); err != nil {
+		h.writeError(c, err, "Failed to delete "+h.label)
+		return
+	}
+	response.Success(c, h.label+" deleted successfully", nil)
+}
+
+func (h *Handler) writeError(c *gin.Context, err error, message string) {
+	switch err.Error() {
+	case "company not found":
+		response.NotFound(c, h.label+" not found")
+	case "slug already exists":
+		response.Error(c, http.StatusConflict, err.Error(), "slug_exists")
+	case "invalid slug":
+		response.BadRequest(c, "Invalid slug", "validation_error")
+	default:
+		response.InternalError(c, message, err.Error())
+	}
+}
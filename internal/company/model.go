@@ -0,0 +1,37 @@
+package company
+
+import (
+	"drakor-backend/pkg/patch"
+	"time"
+)
+
+// Company is a broadcasting network / streamer or a production company
+type Company struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Slug       string    `json:"slug"`
+	Type       string    `json:"type"` // 'network', 'production'
+	LogoURL    string    `json:"logo_url"`
+	DramaCount int       `json:"drama_count,omitempty"` // Published dramas credited to the company; not loaded on drama detail
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type CreateCompanyRequest struct {
+	Name    string `json:"name" validate:"required,min=2,max=150"`
+	Slug    string `json:"slug" validate:"omitempty,min=2,max=150"` // Auto-generated from name if empty
+	LogoURL string `json:"logo_url" validate:"omitempty,url"`
+}
+
+type UpdateCompanyRequest struct {
+	Name    string `json:"name" validate:"required,min=2,max=150"`
+	Slug    string `json:"slug" validate:"omitempty,min=2,max=150"`
+	LogoURL string `json:"logo_url" validate:"omitempty,url"`
+}
+
+// PatchCompanyRequest follows JSON Merge Patch: absent members are left untouched,
+// null clears optional fields
+type PatchCompanyRequest struct {
+	Name    patch.Field[string] `json:"name" validate:"omitnil,min=2,max=150"`
+	Slug    patch.Field[string] `json:"slug" validate:"omitnil,min=2,max=150"`
+	LogoURL patch.Field[string] `json:"logo_url" validate:"omitnil,omitempty,url"`
+}
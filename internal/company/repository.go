@@ -0,0 +1,181 @@
+package company
+
+import (
+	"context"
+	"drakor-backend/pkg/database"
+	"drakor-backend/pkg/patch"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type Repository interface {
+	FindAll(ctx context.Context, companyType, search string, limit, offset int) ([]Company, int64, error)
+	FindByID(ctx context.Context, companyType, id string) (*Company, error)
+	FindBySlug(ctx context.Context, companyType, slug string) (*Company, error)
+	// SlugExists checks every company, whatever its type
+	SlugExists(ctx context.Context, slug, excludeID string) (bool, error)
+	Create(ctx context.Context, company *Company) error
+	Update(ctx context.Context, company *Company) error
+	Patch(ctx context.Context, id string, fields map[string]interface{}) error
+	Delete(ctx context.Context, id string) error
+}
+
+type repository struct{}
+
+func NewRepository() Repository {
+	return &repository{}
+}
+
+const selectCompany = `
+	SELECT c.id, c.name, c.slug, c.type, c.logo_url, c.created_at,
+	       (SELECT COUNT(*) FROM drama_companies dc JOIN dramas d ON d.id = dc.drama_id
+	        WHERE dc.company_id = c.id AND d.deleted_at IS NULL AND d.publication_status = 'published')
+	FROM companies c
+`
+
+func scanCompany(row pgx.Row) (*Company, error) {
+	var c Company
+	var logo *string
+	if err := row.Scan(&c.ID, &c.Name, &c.Slug, &c.Type, &logo, &c.CreatedAt, &c.DramaCount); err != nil {
+		return nil, err
+	}
+	if logo != nil {
+		c.LogoURL = *logo
+	}
+	return &c, nil
+}
+
+func (r *repository) FindAll(ctx context.Context, companyType, search string, limit, offset int) ([]Company, int64, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, 0, errors.New("database not connected")
+	}
+
+	where := " WHERE c.type = $1"
+	args := []interface{}{companyType}
+	if search != "" {
+		where += " AND c.name ILIKE $2"
+		args = append(args, "%"+search+"%")
+	}
+
+	var total int64
+	if err := db.QueryRow(ctx, "SELECT COUNT(*) FROM companies c"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := selectCompany + where + fmt.Sprintf(" ORDER BY c.name ASC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	rows, err := db.Query(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	companies := []Company{}
+	for rows.Next() {
+		c, err := scanCompany(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		companies = append(companies, *c)
+	}
+	return companies, total, rows.Err()
+}
+
+func (r *repository) FindByID(ctx context.Context, companyType, id string) (*Company, error) {
+	return r.findOne(ctx, companyType, "c.id", id)
+}
+
+func (r *repository) FindBySlug(ctx context.Context, companyType, slug string) (*Company, error) {
+	return r.findOne(ctx, companyType, "c.slug", slug)
+}
+
+func (r *repository) findOne(ctx context.Context, companyType, column, value string) (*Company, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	query := selectCompany + " WHERE c.type = $1 AND " + column + " = $2"
+	c, err := scanCompany(db.QueryRow(ctx, query, companyType, value))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *repository) SlugExists(ctx context.Context, slug, excludeID string) (bool, error) {
+	db := database.GetDB()
+	if db == nil {
+		return false, errors.New("database not connected")
+	}
+
+	var exists bool
+	err := db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM companies WHERE slug = $1 AND id::text <> $2)", slug, excludeID).Scan(&exists)
+	return exists, err
+}
+
+func (r *repository) Create(ctx context.Context, company *Company) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	var logo *string
+	if company.LogoURL != "" {
+		logo = &company.LogoURL
+	}
+	query := `INSERT INTO companies (name, slug, type, logo_url, created_at) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+	return db.QueryRow(ctx, query, company.Name, company.Slug, company.Type, logo, time.Now()).Scan(&company.ID, &company.CreatedAt)
+}
+
+func (r *repository) Update(ctx context.Context, company *Company) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	var logo *string
+	if company.LogoURL != "" {
+		logo = &company.LogoURL
+	}
+	query := `UPDATE companies SET name = $1, slug = $2, logo_url = $3 WHERE id = $4`
+	_, err := db.Exec(ctx, query, company.Name, company.Slug, logo, company.ID)
+	return err
+}
+
+func (r *repository) Patch(ctx context.Context, id string, fields map[string]interface{}) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+	return patch.Update(ctx, db, "companies", []string{"name", "slug", "logo_url"}, fields, id)
+}
+
+// Delete removes the company from every drama, bumping their updated_at
+func (r *repository) Delete(ctx context.Context, id string) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, "UPDATE dramas SET updated_at = $1 WHERE id IN (SELECT drama_id FROM drama_companies WHERE company_id = $2)", time.Now(), id)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM companies WHERE id = $1", id); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
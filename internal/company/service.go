@@ -0,0 +1,132 @@
+package company
+
+import (
+	"context"
+	"drakor-backend/pkg/validator"
+	"errors"
+)
+
+// Service methods take the company type ('network' or 'production') they operate on
+type Service interface {
+	GetAll(ctx context.Context, companyType string, page, limit int, search string) ([]Company, int64, error)
+	// GetByID accepts the company UUID or slug
+	GetByID(ctx context.Context, companyType, id string) (*Company, error)
+	Create(ctx context.Context, companyType string, req CreateCompanyRequest) (*Company, error)
+	Update(ctx context.Context, companyType, id string, req UpdateCompanyRequest) (*Company, error)
+	Patch(ctx context.Context, companyType, id string, req PatchCompanyRequest) (*Company, error)
+	Delete(ctx context.Context, companyType, id string) error
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) GetAll(ctx context.Context, companyType string, page, limit int, search string) ([]Company, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	return s.repo.FindAll(ctx, companyType, search, limit, (page-1)*limit)
+}
+
+func (s *service) GetByID(ctx context.Context, companyType, id string) (*Company, error) {
+	if validator.IsUUID(id) {
+		return s.repo.FindByID(ctx, companyType, id)
+	}
+	return s.repo.FindBySlug(ctx, companyType, id)
+}
+
+func (s *service) Create(ctx context.Context, companyType string, req CreateCompanyRequest) (*Company, error) {
+	slug, err := s.resolveSlug(ctx, req.Slug, req.Name, "")
+	if err != nil {
+		return nil, err
+	}
+
+	company := &Company{
+		Name:    req.Name,
+		Slug:    slug,
+		Type:    companyType,
+		LogoURL: req.LogoURL,
+	}
+	if err := s.repo.Create(ctx, company); err != nil {
+		return nil, err
+	}
+	return company, nil
+}
+
+func (s *service) Update(ctx context.Context, companyType, id string, req UpdateCompanyRequest) (*Company, error) {
+	company, err := s.repo.FindByID(ctx, companyType, id)
+	if err != nil {
+		return nil, err
+	}
+	if company == nil {
+		return nil, errors.New("company not found")
+	}
+
+	slug, err := s.resolveSlug(ctx, req.Slug, req.Name, company.ID)
+	if err != nil {
+		return nil, err
+	}
+	company.Name = req.Name
+	company.Slug = slug
+	company.LogoURL = req.LogoURL
+
+	if err := s.repo.Update(ctx, company); err != nil {
+		return nil, err
+	}
+	return company, nil
+}
+
+func (s *service) Patch(ctx context.Context, companyType, id string, req PatchCompanyRequest) (*Company, error) {
+	company, err := s.repo.FindByID(ctx, companyType, id)
+	if err != nil {
+		return nil, err
+	}
+	if company == nil {
+		return nil, errors.New("company not found")
+	}
+
+	fields := map[string]interface{}{}
+	if req.Name.Set {
+		fields["name"] = req.Name.Value
+	}
+	if req.Slug.Set {
+		slug, err := s.resolveSlug(ctx, req.Slug.Value, company.Name, company.ID)
+		if err != nil {
+			return nil, err
+		}
+		fields["slug"] = slug
+	}
+	if req.LogoURL.Set {
+		fields["logo_url"] = req.LogoURL.Column()
+	}
+
+	if err := s.repo.Patch(ctx, company.ID, fields); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, companyType, company.ID)
+}
+
+func (s *service) Delete(ctx context.Context, companyType, id string) error {
+	company, err := s.repo.FindByID(ctx, companyType, id)
+	if err != nil {
+		return err
+	}
+	if company == nil {
+		return errors.New("company not found")
+	}
+	return s.repo.Delete(ctx, company.ID)
+}
+
+// resolveSlug validates a requested slug or generates a unique one from the name
+func (s *service) resolveSlug(ctx context.Context, requested, name, excludeID string) (string, error) {
+	return validator.ResolveSlug(requested, name, "company", nil, func(slug string) (bool, error) {
+		return s.repo.SlugExists(ctx, slug, excludeID)
+	})
+}
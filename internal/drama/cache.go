@@ -0,0 +1,68 @@
+package drama
+
+import (
+	"sync"
+	"time"
+)
+
+// versionTTL is how long a catalog version read from the database is trusted;
+// recommendations may lag a catalog change by up to this long
+const versionTTL = 30 * time.Second
+
+// similarCache keeps scored recommendations per drama for one catalog version;
+// a new version drops every entry
+type similarCache struct {
+	mu        sync.Mutex
+	version   string
+	checkedAt time.Time // When version was last read from the database
+	entries   map[string][]SimilarDrama
+}
+
+func newSimilarCache() *similarCache {
+	return &similarCache{entries: map[string][]SimilarDrama{}}
+}
+
+// currentVersion returns the last catalog version seen, unless it is older than versionTTL
+func (c *similarCache) currentVersion(now time.Time) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.checkedAt.IsZero() || now.Sub(c.checkedAt) >= versionTTL {
+		return "", false
+	}
+	return c.version, true
+}
+
+// checked records a catalog version just read from the database
+func (c *similarCache) checked(version string, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.version != version {
+		c.version = version
+		c.entries = map[string][]SimilarDrama{}
+	}
+	c.checkedAt = now
+}
+
+func (c *similarCache) get(version, id string) ([]SimilarDrama, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.version != version {
+		c.version = version
+		c.entries = map[string][]SimilarDrama{}
+		return nil, false
+	}
+	similar, ok := c.entries[id]
+	return similar, ok
+}
+
+func (c *similarCache) set(version, id string, similar []SimilarDrama) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.version == version {
+		c.entries[id] = similar
+	}
+}
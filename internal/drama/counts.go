@@ -0,0 +1,102 @@
+package drama
+
+import (
+	"context"
+	"drakor-backend/pkg/database"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// execer is satisfied by both the connection pool and a transaction
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// countsQuery derives the season and episode counts of every drama from its
+// non-trashed seasons and episodes
+const countsQuery = `
+	SELECT d.id,
+	       (SELECT COUNT(*) FROM seasons s WHERE s.drama_id = d.id AND s.deleted_at IS NULL) AS seasons,
+	       (SELECT COUNT(*) FROM episodes e JOIN seasons s ON s.id = e.season_id
+	        WHERE s.drama_id = d.id AND s.deleted_at IS NULL AND e.deleted_at IS NULL) AS episodes
+	FROM dramas d
+`
+
+// SyncCounts refreshes total_seasons and episode_count of the given dramas. Callers
+// that add, trash or restore seasons and episodes run it in the same transaction.
+func SyncCounts(ctx context.Context, q execer, dramaIDs ...string) error {
+	if len(dramaIDs) == 0 {
+		return nil
+	}
+	// Lock the dramas first: a concurrent writer holding them commits before we
+	// go on, and the count below runs in a fresh snapshot that includes its rows
+	if _, err := q.Exec(ctx, "SELECT 1 FROM dramas WHERE id = ANY($1) ORDER BY id FOR UPDATE", dramaIDs); err != nil {
+		return err
+	}
+	_, err := q.Exec(ctx, `
+		UPDATE dramas d SET total_seasons = c.seasons, episode_count = c.episodes
+		FROM (`+countsQuery+` WHERE d.id = ANY($1)) c
+		WHERE d.id = c.id AND (d.total_seasons, d.episode_count) IS DISTINCT FROM (c.seasons, c.episodes)
+	`, dramaIDs)
+	return err
+}
+
+// CountDrift is a drama whose stored counts did not match its seasons and episodes
+type CountDrift struct {
+	DramaID        string `json:"drama_id"`
+	Title          string `json:"title"`
+	StoredSeasons  int    `json:"stored_seasons"`
+	ActualSeasons  int    `json:"actual_seasons"`
+	StoredEpisodes int    `json:"stored_episodes"`
+	ActualEpisodes int    `json:"actual_episodes"`
+}
+
+func (r *repository) ReconcileCounts(ctx context.Context, dryRun bool) ([]CountDrift, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the dramas so counts cannot move between reading and repairing them
+	rows, err := tx.Query(ctx, `
+		SELECT d.id, d.title, d.total_seasons, c.seasons, d.episode_count, c.episodes
+		FROM dramas d
+		JOIN (`+countsQuery+`) c ON c.id = d.id
+		WHERE (d.total_seasons, d.episode_count) IS DISTINCT FROM (c.seasons, c.episodes)
+		ORDER BY d.title
+		FOR UPDATE OF d
+	`)
+	if err != nil {
+		return nil, err
+	}
+	drift := []CountDrift{}
+	ids := []string{}
+	for rows.Next() {
+		var cd CountDrift
+		if err := rows.Scan(&cd.DramaID, &cd.Title, &cd.StoredSeasons, &cd.ActualSeasons, &cd.StoredEpisodes, &cd.ActualEpisodes); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		drift = append(drift, cd)
+		ids = append(ids, cd.DramaID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if dryRun || len(ids) == 0 {
+		return drift, nil
+	}
+	if err := SyncCounts(ctx, tx, ids...); err != nil {
+		return nil, err
+	}
+	return drift, tx.Commit(ctx)
+}
@@ -1,10 +1,13 @@
 package drama
 
 import (
+	"drakor-backend/internal/auth"
 	"drakor-backend/pkg/response"
 	"drakor-backend/pkg/validator"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,16 +20,94 @@ func NewHandler(service Service) *Handler {
 	return &Handler{service: service}
 }
 
+// listFilter reads the public list filters from the query string
+func listFilter(c *gin.Context) Filter {
+	filter := Filter{
+		Query:       c.Query("q"),
+		GenreID:     c.Query("genre"),
+		Status:      c.Query("status"),
+		Sort:        c.Query("sort"),
+		Year:        c.Query("year"),
+		Publication: "published",
+		Network:     c.Query("network"),
+		Company:     c.Query("company"),
+		Director:    c.Query("director"),
+		Country:     strings.ToUpper(c.Query("country")),
+		Language:    strings.ToLower(c.Query("language")),
+	}
+	// ?tag=a&tag=b and ?tag=a,b both require every listed tag
+	for _, v := range c.QueryArray("tag") {
+		for _, slug := range strings.Split(v, ",") {
+			if slug = strings.TrimSpace(slug); slug != "" {
+				filter.Tags = append(filter.Tags, slug)
+			}
+		}
+	}
+	// ?exclude_advisory=violence,self-harm hides dramas carrying any of them
+	for _, v := range c.QueryArray("exclude_advisory") {
+		for _, slug := range strings.Split(v, ",") {
+			if slug = strings.TrimSpace(slug); slug != "" {
+				filter.ExcludeAdvisories = append(filter.ExcludeAdvisories, slug)
+			}
+		}
+	}
+	// Admins previewing may list any publication state, or filter by one
+	if auth.CanPreview(c) {
+		filter.Publication = c.Query("publication")
+	} else if userID, exists := c.Get("userID"); exists {
+		// Signed-in viewers also skip the advisories excluded in their preferences
+		filter.Viewer = userID.(string)
+	}
+	return filter
+}
+
 func (h *Handler) GetAll(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	query := c.Query("q")
-	genreID := c.Query("genre")
-	status := c.Query("status")
-	sort := c.Query("sort")
-	year := c.Query("year")
+	filter := listFilter(c)
+
+	dramas, total, err := h.service.GetAll(c.Request.Context(), page, limit, filter)
+	if err != nil {
+		response.InternalError(c, "Failed to fetch dramas", err.Error())
+		return
+	}
+	response.Paginated(c, dramas, total, page, limit)
+}
+
+// GetFacets counts the dramas matching the list filters by country and original language
+func (h *Handler) GetFacets(c *gin.Context) {
+	facets, err := h.service.GetFacets(c.Request.Context(), listFilter(c))
+	if err != nil {
+		response.InternalError(c, "Failed to fetch facets", err.Error())
+		return
+	}
+	response.Success(c, "Drama facets", facets)
+}
+
+// GetCountries lists the countries with published dramas, largest catalog first
+func (h *Handler) GetCountries(c *gin.Context) {
+	facets, err := h.service.GetFacets(c.Request.Context(), Filter{Publication: "published"})
+	if err != nil {
+		response.InternalError(c, "Failed to fetch countries", err.Error())
+		return
+	}
+	response.Success(c, "Countries retrieved successfully", facets.Countries)
+}
+
+// GetByCountry browses one country's dramas; it accepts the same filters as GetAll
+func (h *Handler) GetByCountry(c *gin.Context) {
+	code := strings.ToUpper(c.Param("code"))
+	if errs := validator.Validate.Var(code, "iso3166_1_alpha2"); errs != nil {
+		response.BadRequest(c, "Invalid country code", "validation_error")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	filter := listFilter(c)
+	filter.Country = code
 
-	dramas, total, err := h.service.GetAll(c.Request.Context(), page, limit, query, genreID, status, sort, year)
+	dramas, total, err := h.service.GetAll(c.Request.Context(), page, limit, filter)
 	if err != nil {
 		response.InternalError(c, "Failed to fetch dramas", err.Error())
 		return
@@ -34,9 +115,28 @@ func (h *Handler) GetAll(c *gin.Context) {
 	response.Paginated(c, dramas, total, page, limit)
 }
 
+// ComingSoon lists published upcoming dramas, soonest expected premiere first
+func (h *Handler) ComingSoon(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	filter := Filter{
+		GenreID:     c.Query("genre"),
+		Status:      "upcoming",
+		Sort:        "premiere",
+		Publication: "published",
+	}
+
+	dramas, total, err := h.service.GetAll(c.Request.Context(), page, limit, filter)
+	if err != nil {
+		response.InternalError(c, "Failed to fetch upcoming dramas", err.Error())
+		return
+	}
+	response.Paginated(c, dramas, total, page, limit)
+}
+
 func (h *Handler) GetByID(c *gin.Context) {
 	id := c.Param("id")
-	drama, err := h.service.GetByID(c.Request.Context(), id)
+	drama, err := h.service.GetByID(c.Request.Context(), id, auth.CanPreview(c))
 	if err != nil {
 		response.InternalError(c, "Failed to fetch drama", err.Error())
 		return
@@ -68,6 +168,18 @@ func (h *Handler) Create(c *gin.Context) {
 
 	drama, err := h.service.Create(c.Request.Context(), userID.(string), req)
 	if err != nil {
+		if err.Error() == "slug already exists" {
+			response.Error(c, http.StatusConflict, err.Error(), "slug_exists")
+			return
+		}
+		if err.Error() == "invalid slug" {
+			response.BadRequest(c, "Invalid slug", "validation_error")
+			return
+		}
+		if err.Error() == "publish_at must be in the future" {
+			response.BadRequest(c, "Invalid publish_at", err.Error())
+			return
+		}
 		response.InternalError(c, "Failed to create drama", err.Error())
 		return
 	}
@@ -75,6 +187,12 @@ func (h *Handler) Create(c *gin.Context) {
 }
 
 func (h *Handler) Update(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
 	id := c.Param("id")
 	var req UpdateDramaRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -87,12 +205,67 @@ func (h *Handler) Update(c *gin.Context) {
 		return
 	}
 
-	drama, err := h.service.Update(c.Request.Context(), id, req)
+	drama, err := h.service.Update(c.Request.Context(), userID.(string), id, req)
 	if err != nil {
 		if err.Error() == "drama not found" {
 			response.NotFound(c, "Drama not found")
 			return
 		}
+		if err.Error() == "slug already exists" {
+			response.Error(c, http.StatusConflict, err.Error(), "slug_exists")
+			return
+		}
+		if err.Error() == "invalid slug" {
+			response.BadRequest(c, "Invalid slug", "validation_error")
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid status transition") {
+			response.Error(c, http.StatusConflict, err.Error(), "invalid_status_transition")
+			return
+		}
+		response.InternalError(c, "Failed to update drama", err.Error())
+		return
+	}
+	response.Success(c, "Drama updated successfully", drama)
+}
+
+func (h *Handler) Patch(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	id := c.Param("id")
+	var req PatchDramaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	drama, err := h.service.Patch(c.Request.Context(), userID.(string), id, req)
+	if err != nil {
+		if err.Error() == "drama not found" {
+			response.NotFound(c, "Drama not found")
+			return
+		}
+		if err.Error() == "slug already exists" {
+			response.Error(c, http.StatusConflict, err.Error(), "slug_exists")
+			return
+		}
+		if err.Error() == "invalid slug" {
+			response.BadRequest(c, "Invalid slug", "validation_error")
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid status transition") {
+			response.Error(c, http.StatusConflict, err.Error(), "invalid_status_transition")
+			return
+		}
 		response.InternalError(c, "Failed to update drama", err.Error())
 		return
 	}
@@ -107,3 +280,218 @@ func (h *Handler) Delete(c *gin.Context) {
 	}
 	response.Success(c, "Drama deleted successfully", nil)
 }
+
+func (h *Handler) Publish(c *gin.Context) {
+	h.setPublication(c, "published", nil)
+}
+
+func (h *Handler) Unpublish(c *gin.Context) {
+	h.setPublication(c, "unpublished", nil)
+}
+
+func (h *Handler) Schedule(c *gin.Context) {
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	h.setPublication(c, "scheduled", &req.PublishAt)
+}
+
+func (h *Handler) setPublication(c *gin.Context, publication string, publishAt *time.Time) {
+	id := c.Param("id")
+	drama, err := h.service.SetPublication(c.Request.Context(), id, publication, publishAt)
+	if err != nil {
+		if err.Error() == "drama not found" {
+			response.NotFound(c, "Drama not found")
+			return
+		}
+		if err.Error() == "publish_at must be in the future" {
+			response.BadRequest(c, "Invalid publish_at", err.Error())
+			return
+		}
+		response.InternalError(c, "Failed to update publication", err.Error())
+		return
+	}
+	response.Success(c, "Drama publication updated", drama)
+}
+
+func (h *Handler) SetCrew(c *gin.Context) {
+	var req SetCrewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	drama, err := h.service.SetCrew(c.Request.Context(), c.Param("id"), req.Crew)
+	if err != nil {
+		if err.Error() == "drama not found" {
+			response.NotFound(c, "Drama not found")
+			return
+		}
+		if err.Error() == "actor not found" {
+			response.BadRequest(c, "Unknown person in crew", "actor_not_found")
+			return
+		}
+		response.InternalError(c, "Failed to update crew", err.Error())
+		return
+	}
+	response.Success(c, "Crew updated successfully", drama)
+}
+
+func (h *Handler) SetCompanies(c *gin.Context) {
+	var req SetCompaniesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	drama, err := h.service.SetCompanies(c.Request.Context(), c.Param("id"), req.CompanyIDs)
+	if err != nil {
+		if err.Error() == "drama not found" {
+			response.NotFound(c, "Drama not found")
+			return
+		}
+		if err.Error() == "company not found" {
+			response.BadRequest(c, "Unknown network or production company", "company_not_found")
+			return
+		}
+		response.InternalError(c, "Failed to update companies", err.Error())
+		return
+	}
+	response.Success(c, "Companies updated successfully", drama)
+}
+
+// GetSimilar lists dramas to recommend on the detail page; signed-in users
+// don't see dramas they have already finished
+func (h *Handler) GetSimilar(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	userID := ""
+	if v, exists := c.Get("userID"); exists {
+		userID = v.(string)
+	}
+
+	similar, err := h.service.GetSimilar(c.Request.Context(), c.Param("id"), userID, limit)
+	if err != nil {
+		if err.Error() == "drama not found" {
+			response.NotFound(c, "Drama not found")
+			return
+		}
+		response.InternalError(c, "Failed to fetch similar dramas", err.Error())
+		return
+	}
+	response.Success(c, "Similar dramas", similar)
+}
+
+func (h *Handler) GetRevisions(c *gin.Context) {
+	id := c.Param("id")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	revisions, total, err := h.service.GetRevisions(c.Request.Context(), id, page, limit)
+	if err != nil {
+		response.InternalError(c, "Failed to fetch revisions", err.Error())
+		return
+	}
+	response.Paginated(c, revisions, total, page, limit)
+}
+
+func (h *Handler) GetRevision(c *gin.Context) {
+	id := c.Param("id")
+	number, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		response.BadRequest(c, "Invalid revision number", err.Error())
+		return
+	}
+
+	revision, err := h.service.GetRevision(c.Request.Context(), id, number)
+	if err != nil {
+		response.InternalError(c, "Failed to fetch revision", err.Error())
+		return
+	}
+	if revision == nil {
+		response.NotFound(c, "Revision not found")
+		return
+	}
+	response.Success(c, "Revision detail", revision)
+}
+
+func (h *Handler) Rollback(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	id := c.Param("id")
+	number, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		response.BadRequest(c, "Invalid revision number", err.Error())
+		return
+	}
+
+	drama, err := h.service.Rollback(c.Request.Context(), userID.(string), id, number)
+	if err != nil {
+		if err.Error() == "drama not found" {
+			response.NotFound(c, "Drama not found")
+			return
+		}
+		if err.Error() == "revision not found" {
+			response.NotFound(c, "Revision not found")
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid status transition") {
+			response.Error(c, http.StatusConflict, err.Error(), "invalid_status_transition")
+			return
+		}
+		if err.Error() == "revision references purged records" {
+			response.Error(c, http.StatusConflict, err.Error(), "revision_not_restorable")
+			return
+		}
+		response.InternalError(c, "Failed to roll back drama", err.Error())
+		return
+	}
+	response.Success(c, "Drama rolled back successfully", drama)
+}
+
+func (h *Handler) GetStatusHistory(c *gin.Context) {
+	history, err := h.service.GetStatusHistory(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err.Error() == "drama not found" {
+			response.NotFound(c, "Drama not found")
+			return
+		}
+		response.InternalError(c, "Failed to fetch status history", err.Error())
+		return
+	}
+	response.Success(c, "Status history retrieved successfully", history)
+}
+
+// IDMiddleware answers 404 when a route's :id is not a drama UUID; admin routes
+// look dramas up by id only, and the database would reject the cast otherwise
+func IDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if id := c.Param("id"); id != "" && !validator.IsUUID(id) {
+			response.NotFound(c, "Drama not found")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
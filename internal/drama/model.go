@@ -2,58 +2,250 @@ package drama
 
 import (
 	"drakor-backend/internal/actor"
+	"drakor-backend/internal/advisory"
+	"drakor-backend/internal/company"
 	"drakor-backend/internal/genre"
+	"drakor-backend/internal/media"
+	"drakor-backend/internal/schedule"
+	"drakor-backend/internal/tag"
+	"drakor-backend/pkg/patch"
+	"drakor-backend/pkg/validator"
 	"time"
 )
 
+func init() {
+	validator.RegisterPatchTypes(patch.Field[[]DramaActorReq]{})
+}
+
 type Drama struct {
-	ID           string        `json:"id"`
-	Title        string        `json:"title"`
-	Synopsis     string        `json:"synopsis"`
-	PosterURL    string        `json:"poster_url"`
-	Year         int           `json:"year"`
-	Rating       float64       `json:"rating"`
-	TotalSeasons int           `json:"total_seasons"`
-	Status       string        `json:"status"` // 'ongoing', 'completed'
-	ViewCount    int           `json:"view_count"`
-	SourceURL    string        `json:"source_url"` // Trailer or internal source
-	AddedBy      string        `json:"added_by,omitempty"`
-	CreatedAt    time.Time     `json:"created_at"`
-	UpdatedAt    time.Time     `json:"updated_at"`
-	Genres       []genre.Genre `json:"genres,omitempty"`
-	Actors       []DramaActor  `json:"actors,omitempty"`
+	ID           string              `json:"id"`
+	Title        string              `json:"title"`
+	Slug         string              `json:"slug"`
+	Synopsis     string              `json:"synopsis"`
+	PosterURL    string              `json:"poster_url"`
+	Year         int                 `json:"year"`
+	Rating       float64             `json:"rating"`
+	TotalSeasons int                 `json:"total_seasons"`           // Derived from the seasons table
+	EpisodeCount int                 `json:"episode_count"`           // Derived from the episodes table
+	Status       string              `json:"status"`                  // 'upcoming', 'ongoing', 'hiatus', 'completed', 'cancelled'
+	PremiereDate string              `json:"premiere_date,omitempty"` // Expected premiere (YYYY-MM-DD) while upcoming
+	Country      string              `json:"country"`                 // ISO 3166-1 alpha-2, e.g. KR, CN, JP, TH
+	Language     string              `json:"original_language"`       // ISO 639-1, e.g. ko, zh, ja, th
+	ViewCount    int                 `json:"view_count"`
+	SourceURL    string              `json:"source_url"`         // Internal source; trailers and teasers live in Gallery
+	Publication  string              `json:"publication_status"` // 'draft', 'scheduled', 'published', 'unpublished'
+	PublishAt    *time.Time          `json:"publish_at,omitempty"`
+	AddedBy      string              `json:"added_by,omitempty"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+	Genres       []genre.Genre       `json:"genres,omitempty"`
+	Actors       []DramaActor        `json:"actors,omitempty"`
+	Tags         []tag.Tag           `json:"tags,omitempty"`
+	Advisories   []advisory.Advisory `json:"advisories,omitempty"` // Content warnings on the drama itself
+	Crew         []CrewCredit        `json:"crew,omitempty"`
+	Networks     []company.Company   `json:"networks,omitempty"`
+	Production   []company.Company   `json:"production_companies,omitempty"`
+	Gallery      media.Gallery       `json:"gallery,omitempty"` // Media grouped by type; the primary poster mirrors PosterURL
+	Broadcast    []schedule.Slot     `json:"broadcast,omitempty"`
+	NextEpisode  *time.Time          `json:"next_episode_at,omitempty"` // Computed from Broadcast while ongoing
+}
+
+// Filter holds the optional list filters for dramas
+type Filter struct {
+	Query       string
+	GenreID     string
+	Status      string
+	Sort        string // 'latest', 'popular', 'rating', 'oldest'
+	Year        string
+	Publication string   // Empty means any publication state
+	Tags        []string // Tag slugs; a drama must carry all of them
+	Network     string   // Network id or slug
+	Company     string   // Production company id or slug
+	Director    string   // Director (person) id or slug
+	Country     string   // ISO 3166-1 alpha-2, upper case
+	Language    string   // ISO 639-1 original language, lower case
+	// Dramas carrying any of these advisory slugs, on the drama or one of its episodes, are left out
+	ExcludeAdvisories []string
+	Viewer            string // User whose excluded advisories are left out too; empty for guests
+}
+
+// DefaultCountry and DefaultLanguage apply when a drama is created without them
+const (
+	DefaultCountry  = "KR"
+	DefaultLanguage = "ko"
+)
+
+// FacetCount is the number of dramas sharing one facet value
+type FacetCount struct {
+	Code  string `json:"code"`
+	Count int64  `json:"count"`
+}
+
+// Facets counts the dramas matching a filter by country and by original language.
+// Each facet ignores its own filter so clients can offer the alternatives.
+type Facets struct {
+	Countries []FacetCount `json:"countries"`
+	Languages []FacetCount `json:"original_languages"`
 }
 
 type DramaActor struct {
-	Actor actor.Actor `json:"actor"`
-	Role  string      `json:"role"` // 'main', 'support'
+	Actor     actor.Actor `json:"actor"`
+	Role      string      `json:"role"` // 'main', 'support'
+	Character string      `json:"character_name,omitempty"`
+	Billing   int         `json:"billing_order"` // 1 is top billed
+	IsCameo   bool        `json:"is_cameo"`
+	IsGuest   bool        `json:"is_guest"`
+}
+
+// CrewCredit is a person credited behind the camera; people share the actors table
+type CrewCredit struct {
+	Person actor.Actor `json:"person"`
+	Job    string      `json:"job"` // 'director', 'screenwriter', 'composer', 'producer'
+}
+
+type CrewCreditReq struct {
+	ActorID string `json:"actor_id" validate:"required,uuid"`
+	Job     string `json:"job" validate:"required,oneof=director screenwriter composer producer"`
+}
+
+// SetCrewRequest replaces every crew credit on a drama
+type SetCrewRequest struct {
+	Crew []CrewCreditReq `json:"crew" validate:"dive"`
+}
+
+// SetCompaniesRequest replaces the networks and production companies of a drama
+type SetCompaniesRequest struct {
+	CompanyIDs []string `json:"company_ids" validate:"dive,uuid"`
 }
 
 type CreateDramaRequest struct {
 	Title        string          `json:"title" validate:"required,min=2,max=255"`
+	Slug         string          `json:"slug" validate:"omitempty,min=2,max=255"` // Auto-generated from title if empty
 	Synopsis     string          `json:"synopsis"`
-	PosterURL    string          `json:"poster_url" validate:"omitempty,url"`
+	PosterURL    string          `json:"poster_url" validate:"omitempty,url"` // Becomes the primary gallery poster; empty keeps the gallery's
 	Year         int             `json:"year" validate:"required,min=1900,max=2100"`
-	TotalSeasons int             `json:"total_seasons" validate:"min=1"`
-	Status       string          `json:"status" validate:"required,oneof=ongoing completed"`
+	Status       string          `json:"status" validate:"required,oneof=upcoming ongoing hiatus completed cancelled"`
+	PremiereDate string          `json:"premiere_date" validate:"omitempty,datetime=2006-01-02"`
+	Country      string          `json:"country" validate:"omitempty,iso3166_1_alpha2"`   // Defaults to KR
+	Language     string          `json:"original_language" validate:"omitempty,iso639_1"` // Defaults to ko
 	SourceURL    string          `json:"source_url" validate:"omitempty,url"`
 	GenreIDs     []string        `json:"genre_ids" validate:"required,min=1"`
 	Actors       []DramaActorReq `json:"actors" validate:"omitempty,dive"`
+	Publication  string          `json:"publication_status" validate:"omitempty,oneof=draft scheduled published"` // Defaults to draft
+	PublishAt    *time.Time      `json:"publish_at"`                                                              // Required when scheduled
 }
 
+// DramaActorReq is one cast credit; the same actor may appear once per character.
+// A zero billing order bills the credit by its position in the list.
 type DramaActorReq struct {
-	ActorID string `json:"actor_id" validate:"required,uuid"`
-	Role    string `json:"role" validate:"required,oneof=main support"`
+	ActorID   string `json:"actor_id" validate:"required,uuid"`
+	Role      string `json:"role" validate:"required,oneof=main support"`
+	Character string `json:"character_name,omitempty" validate:"omitempty,max=150"`
+	Billing   int    `json:"billing_order,omitempty" validate:"min=0"`
+	IsCameo   bool   `json:"is_cameo,omitempty"`
+	IsGuest   bool   `json:"is_guest,omitempty"`
 }
 
 type UpdateDramaRequest struct {
 	Title        string          `json:"title" validate:"required,min=2,max=255"`
+	Slug         string          `json:"slug" validate:"omitempty,min=2,max=255"` // Auto-generated from title if empty
 	Synopsis     string          `json:"synopsis"`
-	PosterURL    string          `json:"poster_url" validate:"omitempty,url"`
+	PosterURL    string          `json:"poster_url" validate:"omitempty,url"` // Becomes the primary gallery poster; empty keeps the gallery's
 	Year         int             `json:"year" validate:"required,min=1900,max=2100"`
-	TotalSeasons int             `json:"total_seasons" validate:"min=1"`
-	Status       string          `json:"status" validate:"required,oneof=ongoing completed"`
+	Status       string          `json:"status" validate:"required,oneof=upcoming ongoing hiatus completed cancelled"`
+	PremiereDate string          `json:"premiere_date" validate:"omitempty,datetime=2006-01-02"`
+	Country      string          `json:"country" validate:"omitempty,iso3166_1_alpha2"`   // Empty keeps the current country
+	Language     string          `json:"original_language" validate:"omitempty,iso639_1"` // Empty keeps the current language
 	SourceURL    string          `json:"source_url" validate:"omitempty,url"`
 	GenreIDs     []string        `json:"genre_ids" validate:"required,min=1"`
 	Actors       []DramaActorReq `json:"actors" validate:"omitempty,dive"`
 }
+
+// PatchDramaRequest follows JSON Merge Patch: absent members are left untouched,
+// null clears optional fields, genre_ids/actors replace the current associations
+type PatchDramaRequest struct {
+	Title        patch.Field[string]          `json:"title" validate:"omitnil,min=2,max=255"`
+	Slug         patch.Field[string]          `json:"slug" validate:"omitnil,min=2,max=255"`
+	Synopsis     patch.Field[string]          `json:"synopsis"`
+	PosterURL    patch.Field[string]          `json:"poster_url" validate:"omitnil,omitempty,url"`
+	Year         patch.Field[int]             `json:"year" validate:"omitnil,min=1900,max=2100"`
+	Status       patch.Field[string]          `json:"status" validate:"omitnil,oneof=upcoming ongoing hiatus completed cancelled"`
+	PremiereDate patch.Field[string]          `json:"premiere_date" validate:"omitnil,omitempty,datetime=2006-01-02"`
+	Country      patch.Field[string]          `json:"country" validate:"omitnil,iso3166_1_alpha2"`
+	Language     patch.Field[string]          `json:"original_language" validate:"omitnil,iso639_1"`
+	SourceURL    patch.Field[string]          `json:"source_url" validate:"omitnil,omitempty,url"`
+	GenreIDs     patch.Field[[]string]        `json:"genre_ids" validate:"omitnil,min=1,dive,uuid"`
+	Actors       patch.Field[[]DramaActorReq] `json:"actors" validate:"omitnil,dive"`
+}
+
+// Snapshot is the editable state of a drama captured in a revision
+type Snapshot struct {
+	Title        string          `json:"title"`
+	Slug         string          `json:"slug"`
+	Synopsis     string          `json:"synopsis"`
+	PosterURL    string          `json:"poster_url"`
+	Year         int             `json:"year"`
+	Status       string          `json:"status"`
+	PremiereDate string          `json:"premiere_date"`
+	Country      string          `json:"country"`
+	Language     string          `json:"original_language"`
+	SourceURL    string          `json:"source_url"`
+	GenreIDs     []string        `json:"genre_ids"`
+	Actors       []DramaActorReq `json:"actors"`
+}
+
+type Revision struct {
+	ID             string        `json:"id"`
+	DramaID        string        `json:"drama_id"`
+	RevisionNumber int           `json:"revision_number"`
+	Action         string        `json:"action"` // 'create', 'update', 'rollback'
+	RestoredFrom   *int          `json:"restored_from,omitempty"`
+	EditedBy       string        `json:"edited_by,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+	Snapshot       Snapshot      `json:"snapshot"`
+	Changes        []FieldChange `json:"changes"` // Compared with the previous revision
+}
+
+// Edit describes the revision a write records in its own transaction
+type Edit struct {
+	UserID       string
+	Action       string // 'create', 'update', 'rollback'
+	RestoredFrom *int
+}
+
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+type ScheduleRequest struct {
+	PublishAt time.Time `json:"publish_at" validate:"required"`
+}
+
+// SimilarDrama is a drama recommended alongside another one
+type SimilarDrama struct {
+	Drama
+	Score        float64 `json:"score"`
+	SharedGenres int     `json:"shared_genres"`
+	SharedActors int     `json:"shared_actors"`
+}
+
+// statusTransitions lists the statuses a drama may move to from each status
+var statusTransitions = map[string][]string{
+	"upcoming":  {"ongoing", "cancelled"},
+	"ongoing":   {"hiatus", "completed", "cancelled"},
+	"hiatus":    {"ongoing", "completed", "cancelled"},
+	"completed": {"ongoing"},  // Renewed for another season
+	"cancelled": {"upcoming"}, // Revived
+}
+
+// StatusChange is one entry of a drama's status history
+type StatusChange struct {
+	ID         string    `json:"id"`
+	DramaID    string    `json:"drama_id"`
+	FromStatus string    `json:"from_status,omitempty"` // Empty for the status the drama was created with
+	ToStatus   string    `json:"to_status"`
+	ChangedBy  string    `json:"changed_by,omitempty"` // Empty for automatic changes
+	ChangedAt  time.Time `json:"changed_at"`
+}
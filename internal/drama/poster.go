@@ -0,0 +1,58 @@
+package drama
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SetPoster makes url the drama's primary poster inside tx, adding it to the media
+// gallery when it is not there yet, and mirrors it into dramas.poster_url. Every
+// writer of poster_url goes through it, so later gallery edits keep the poster.
+// An empty url falls back to the gallery's primary poster, if there is one.
+func SetPoster(ctx context.Context, tx pgx.Tx, dramaID, url string, now time.Time) error {
+	if url == "" {
+		_, err := tx.Exec(ctx, `
+			UPDATE dramas d
+			SET poster_url = (SELECT m.url FROM drama_media m WHERE m.drama_id = d.id AND m.type = 'poster' AND m.is_primary)
+			WHERE d.id = $1
+		`, dramaID)
+		return err
+	}
+
+	var mediaID string
+	err := tx.QueryRow(ctx, `
+		SELECT id FROM drama_media
+		WHERE drama_id = $1 AND type = 'poster' AND url = $2
+		ORDER BY is_primary DESC, position, created_at
+		LIMIT 1
+	`, dramaID, url).Scan(&mediaID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	// Demote the current primary first; at most one primary poster may exist
+	_, err = tx.Exec(ctx, `
+		UPDATE drama_media SET is_primary = FALSE, updated_at = $3
+		WHERE drama_id = $1 AND type = 'poster' AND is_primary AND id::text <> $2
+	`, dramaID, mediaID, now)
+	if err != nil {
+		return err
+	}
+	if mediaID == "" {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO drama_media (drama_id, type, url, is_primary, created_at, updated_at)
+			VALUES ($1, 'poster', $2, TRUE, $3, $3)
+		`, dramaID, url, now)
+	} else {
+		_, err = tx.Exec(ctx, "UPDATE drama_media SET is_primary = TRUE, updated_at = $2 WHERE id = $1 AND NOT is_primary", mediaID, now)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE dramas SET poster_url = $1 WHERE id = $2 AND poster_url IS DISTINCT FROM $1", url, dramaID)
+	return err
+}
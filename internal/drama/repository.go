@@ -2,21 +2,56 @@ package drama
 
 import (
 	"context"
+	"drakor-backend/internal/advisory"
+	"drakor-backend/internal/company"
 	"drakor-backend/internal/genre"
+	"drakor-backend/internal/media"
+	"drakor-backend/internal/schedule"
+	"drakor-backend/internal/tag"
 	"drakor-backend/pkg/database"
+	"drakor-backend/pkg/patch"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 )
 
 type Repository interface {
-	FindAll(ctx context.Context, page, limit int, query, genreID, status, sort, year string) ([]Drama, int64, error)
+	FindAll(ctx context.Context, page, limit int, filter Filter) ([]Drama, int64, error)
+	// FindFacets counts the dramas matching filter by country and original language
+	FindFacets(ctx context.Context, filter Filter) (*Facets, error)
 	FindByID(ctx context.Context, id string) (*Drama, error)
-	Create(ctx context.Context, drama *Drama, genreIDs []string, actors []DramaActorReq, startTime time.Time) error
-	Update(ctx context.Context, drama *Drama, genreIDs []string, actors []DramaActorReq) error
+	FindBySlug(ctx context.Context, slug string) (*Drama, error)
+	SlugExists(ctx context.Context, slug, excludeID string) (bool, error)
+	// Create, Update and Patch record the revision described by edit in the same transaction
+	Create(ctx context.Context, drama *Drama, genreIDs []string, actors []DramaActorReq, startTime time.Time, edit Edit) error
+	Update(ctx context.Context, drama *Drama, genreIDs []string, actors []DramaActorReq, edit Edit) error
+	Patch(ctx context.Context, id string, fields map[string]interface{}, genreIDs []string, actors []DramaActorReq, edit Edit) error
 	Delete(ctx context.Context, id string) error
+	SetPublication(ctx context.Context, id, publication string, publishAt *time.Time) error
+	PublishDue(ctx context.Context, now time.Time) (int64, error)
+	// Status history
+	FindStatusHistory(ctx context.Context, dramaID string) ([]StatusChange, error)
+	FindRevisions(ctx context.Context, dramaID string, limit, offset int) ([]Revision, int64, error)
+	FindRevision(ctx context.Context, dramaID string, number int) (*Revision, error)
+	// SnapshotRestorable reports whether every genre and actor in snap still exists, trashed or not
+	SnapshotRestorable(ctx context.Context, snap Snapshot) (bool, error)
+	// Credits
+	SetCrew(ctx context.Context, id string, crew []CrewCreditReq) error
+	SetCompanies(ctx context.Context, id string, companyIDs []string) error
+	CountActors(ctx context.Context, ids []string) (int, error)
+	CountCompanies(ctx context.Context, ids []string) (int, error)
+	// ReconcileCounts repairs total_seasons and episode_count across the catalog and
+	// reports the dramas that had drifted; dryRun only reports them
+	ReconcileCounts(ctx context.Context, dryRun bool) ([]CountDrift, error)
+	// Recommendations
+	FindSimilar(ctx context.Context, id string, limit int) ([]SimilarDrama, error)
+	FindFinished(ctx context.Context, userID string, ids []string) (map[string]bool, error)
+	// FindExcluded reports which of the dramas carry an advisory the user excluded
+	FindExcluded(ctx context.Context, userID string, ids []string) (map[string]bool, error)
+	CatalogVersion(ctx context.Context) (string, error)
 }
 
 type repository struct{}
@@ -25,54 +60,21 @@ func NewRepository() Repository {
 	return &repository{}
 }
 
-func (r *repository) FindAll(ctx context.Context, page, limit int, queryStr, genreID, status, sort, year string) ([]Drama, int64, error) {
+func (r *repository) FindAll(ctx context.Context, page, limit int, filter Filter) ([]Drama, int64, error) {
 	db := database.GetDB()
 	if db == nil {
 		return nil, 0, errors.New("database not connected")
 	}
 
 	// Base query
-	sql := `SELECT id, title, poster_url, year, rating, status, view_count, created_at FROM dramas WHERE 1=1`
-	countSql := `SELECT COUNT(*) FROM dramas WHERE 1=1`
-	args := []interface{}{}
-	argId := 1
+	sql := `SELECT id, title, slug, poster_url, year, rating, total_seasons, episode_count, status, to_char(premiere_date, 'YYYY-MM-DD'), country, original_language, view_count, publication_status, publish_at, created_at FROM dramas WHERE deleted_at IS NULL`
+	countSql := `SELECT COUNT(*) FROM dramas WHERE deleted_at IS NULL`
 
 	// Dynamic filters
-	if queryStr != "" {
-		filter := fmt.Sprintf(" AND (title ILIKE $%d)", argId)
-		sql += filter
-		countSql += filter
-		args = append(args, "%"+queryStr+"%")
-		argId++
-	}
-
-	if genreID != "" {
-		// Subquery to check if drama has this genre
-		filter := fmt.Sprintf(" AND id IN (SELECT drama_id FROM drama_genres WHERE genre_id = $%d)", argId)
-		sql += filter
-		countSql += filter
-		args = append(args, genreID)
-		argId++
-	}
-
-	if status != "" {
-		filter := fmt.Sprintf(" AND status = $%d", argId)
-		sql += filter
-		countSql += filter
-		args = append(args, status)
-		argId++
-	}
-
-	if year != "" {
-		filter := fmt.Sprintf(" AND year = $%d", argId)
-		sql += filter
-		countSql += filter
-		// Year is int in DB struct but string in query, let's parse or let driver handle it if column is int
-		// Assuming year column is int, we should probably cast or ensure year string is valid.
-		// However, postgres driver often handles string-to-int if compatible.
-		args = append(args, year)
-		argId++
-	}
+	conds, args := filterConditions(filter)
+	sql += conds
+	countSql += conds
+	argId := len(args) + 1
 
 	// Counting total
 	var total int64
@@ -82,13 +84,15 @@ func (r *repository) FindAll(ctx context.Context, page, limit int, queryStr, gen
 	}
 
 	// Sorting
-	switch sort {
+	switch filter.Sort {
 	case "popular":
 		sql += " ORDER BY view_count DESC"
 	case "rating":
 		sql += " ORDER BY rating DESC"
 	case "oldest":
 		sql += " ORDER BY created_at ASC"
+	case "premiere": // Soonest expected premiere first, undated announcements last
+		sql += " ORDER BY premiere_date ASC NULLS LAST, created_at DESC"
 	default: // "latest"
 		sql += " ORDER BY created_at DESC"
 	}
@@ -106,19 +110,188 @@ func (r *repository) FindAll(ctx context.Context, page, limit int, queryStr, gen
 	var dramas []Drama
 	for rows.Next() {
 		var d Drama
-		var poster *string
-		if err := rows.Scan(&d.ID, &d.Title, &poster, &d.Year, &d.Rating, &d.Status, &d.ViewCount, &d.CreatedAt); err != nil {
+		var poster, premiere *string
+		if err := rows.Scan(&d.ID, &d.Title, &d.Slug, &poster, &d.Year, &d.Rating, &d.TotalSeasons, &d.EpisodeCount, &d.Status, &premiere, &d.Country, &d.Language, &d.ViewCount, &d.Publication, &d.PublishAt, &d.CreatedAt); err != nil {
 			return nil, 0, err
 		}
 		if poster != nil {
 			d.PosterURL = *poster
 		}
+		if premiere != nil {
+			d.PremiereDate = *premiere
+		}
 		dramas = append(dramas, d)
 	}
 
 	return dramas, total, nil
 }
 
+// filterConditions builds the " AND ..." conditions and arguments for a drama
+// list filter, numbering placeholders from $1
+func filterConditions(filter Filter) (string, []interface{}) {
+	conds := ""
+	args := []interface{}{}
+	argId := 1
+
+	if filter.Query != "" {
+		cond := fmt.Sprintf(" AND (title ILIKE $%d)", argId)
+		conds += cond
+		args = append(args, "%"+filter.Query+"%")
+		argId++
+	}
+
+	if filter.GenreID != "" {
+		// Subquery to check if drama has this genre
+		cond := fmt.Sprintf(" AND id IN (SELECT drama_id FROM drama_genres WHERE genre_id = $%d)", argId)
+		conds += cond
+		args = append(args, filter.GenreID)
+		argId++
+	}
+
+	if filter.Status != "" {
+		cond := fmt.Sprintf(" AND status = $%d", argId)
+		conds += cond
+		args = append(args, filter.Status)
+		argId++
+	}
+
+	if filter.Year != "" {
+		cond := fmt.Sprintf(" AND year = $%d", argId)
+		conds += cond
+		// Year is int in DB struct but string in query, let's parse or let driver handle it if column is int
+		// Assuming year column is int, we should probably cast or ensure year string is valid.
+		// However, postgres driver often handles string-to-int if compatible.
+		args = append(args, filter.Year)
+		argId++
+	}
+
+	if filter.Publication != "" {
+		cond := fmt.Sprintf(" AND publication_status = $%d", argId)
+		conds += cond
+		args = append(args, filter.Publication)
+		argId++
+	}
+
+	companyFilters := []struct{ companyType, value string }{
+		{"network", filter.Network},
+		{"production", filter.Company},
+	}
+	for _, f := range companyFilters {
+		if f.value == "" {
+			continue
+		}
+		cond := fmt.Sprintf(" AND id IN (SELECT dc.drama_id FROM drama_companies dc JOIN companies c ON c.id = dc.company_id WHERE c.type = '%s' AND (c.id::text = $%d OR c.slug = $%d))", f.companyType, argId, argId)
+		conds += cond
+		args = append(args, f.value)
+		argId++
+	}
+
+	if filter.Director != "" {
+		cond := fmt.Sprintf(" AND id IN (SELECT cr.drama_id FROM drama_crew cr JOIN actors a ON a.id = cr.actor_id WHERE cr.job = 'director' AND (a.id::text = $%d OR a.slug = $%d))", argId, argId)
+		conds += cond
+		args = append(args, filter.Director)
+		argId++
+	}
+
+	for _, slug := range filter.Tags {
+		cond := fmt.Sprintf(" AND id IN (SELECT dt.drama_id FROM drama_tags dt JOIN tags t ON t.id = dt.tag_id WHERE t.slug = $%d)", argId)
+		conds += cond
+		args = append(args, slug)
+		argId++
+	}
+
+	if filter.Country != "" {
+		cond := fmt.Sprintf(" AND country = $%d", argId)
+		conds += cond
+		args = append(args, filter.Country)
+		argId++
+	}
+
+	if filter.Language != "" {
+		cond := fmt.Sprintf(" AND original_language = $%d", argId)
+		conds += cond
+		args = append(args, filter.Language)
+		argId++
+	}
+
+	excluded := []string{}
+	if len(filter.ExcludeAdvisories) > 0 {
+		excluded = append(excluded, fmt.Sprintf("SELECT id FROM advisories WHERE slug = ANY($%d)", argId))
+		args = append(args, filter.ExcludeAdvisories)
+		argId++
+	}
+	if filter.Viewer != "" {
+		excluded = append(excluded, ViewerExclusions(fmt.Sprintf("$%d", argId)))
+		args = append(args, filter.Viewer)
+	}
+	if len(excluded) > 0 {
+		conds += " AND id NOT IN (" + ExcludedDramas(strings.Join(excluded, " UNION ")) + ")"
+	}
+
+	return conds, args
+}
+
+// ExcludedDramas is a subquery of the dramas carrying any advisory of set, itself
+// a subquery of advisory ids. An advisory on any live episode counts against the whole drama.
+func ExcludedDramas(set string) string {
+	return fmt.Sprintf(`
+		SELECT drama_id FROM drama_advisories WHERE advisory_id IN (%s)
+		UNION
+		SELECT s.drama_id FROM episode_advisories ea
+		JOIN episodes e ON e.id = ea.episode_id AND e.deleted_at IS NULL
+		JOIN seasons s ON s.id = e.season_id
+		WHERE ea.advisory_id IN (%s)`, set, set)
+}
+
+// ViewerExclusions is the advisory id subquery of the user bound to param
+func ViewerExclusions(param string) string {
+	return "SELECT advisory_id FROM user_excluded_advisories WHERE user_id = " + param
+}
+
+func (r *repository) FindFacets(ctx context.Context, filter Filter) (*Facets, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	facets := &Facets{Countries: []FacetCount{}, Languages: []FacetCount{}}
+	groups := []struct {
+		column string
+		filter Filter
+		dst    *[]FacetCount
+	}{
+		{"country", filter, &facets.Countries},
+		{"original_language", filter, &facets.Languages},
+	}
+	groups[0].filter.Country = ""
+	groups[1].filter.Language = ""
+
+	for _, g := range groups {
+		conds, args := filterConditions(g.filter)
+		query := fmt.Sprintf(`
+			SELECT %s, COUNT(*) FROM dramas WHERE deleted_at IS NULL%s
+			GROUP BY %s ORDER BY COUNT(*) DESC, %s
+		`, g.column, conds, g.column, g.column)
+		rows, err := db.Query(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var fc FacetCount
+			if err := rows.Scan(&fc.Code, &fc.Count); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			*g.dst = append(*g.dst, fc)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return facets, nil
+}
+
 func (r *repository) FindByID(ctx context.Context, id string) (*Drama, error) {
 	db := database.GetDB()
 	if db == nil {
@@ -127,15 +300,18 @@ func (r *repository) FindByID(ctx context.Context, id string) (*Drama, error) {
 
 	// 1. Fetch Drama Details
 	query := `
-		SELECT id, title, synopsis, poster_url, year, rating, total_seasons, status, view_count, source_url, added_by, created_at, updated_at
-		FROM dramas WHERE id = $1
+		SELECT id, title, slug, synopsis, poster_url, year, rating, total_seasons, episode_count, status,
+		       to_char(premiere_date, 'YYYY-MM-DD'), country, original_language, view_count, source_url,
+		       publication_status, publish_at, added_by, created_at, updated_at
+		FROM dramas WHERE id = $1 AND deleted_at IS NULL
 	`
 	var d Drama
-	var synopsis, poster, source, addedBy *string
+	var synopsis, poster, premiere, source, addedBy *string
 
 	err := db.QueryRow(ctx, query, id).Scan(
-		&d.ID, &d.Title, &synopsis, &poster, &d.Year, &d.Rating, &d.TotalSeasons,
-		&d.Status, &d.ViewCount, &source, &addedBy, &d.CreatedAt, &d.UpdatedAt,
+		&d.ID, &d.Title, &d.Slug, &synopsis, &poster, &d.Year, &d.Rating, &d.TotalSeasons, &d.EpisodeCount,
+		&d.Status, &premiere, &d.Country, &d.Language, &d.ViewCount, &source, &d.Publication, &d.PublishAt, &addedBy,
+		&d.CreatedAt, &d.UpdatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -153,6 +329,9 @@ func (r *repository) FindByID(ctx context.Context, id string) (*Drama, error) {
 	if source != nil {
 		d.SourceURL = *source
 	}
+	if premiere != nil {
+		d.PremiereDate = *premiere
+	}
 	if addedBy != nil {
 		d.AddedBy = *addedBy
 	}
@@ -162,7 +341,8 @@ func (r *repository) FindByID(ctx context.Context, id string) (*Drama, error) {
 		SELECT g.id, g.name, g.slug 
 		FROM genres g
 		JOIN drama_genres dg ON g.id = dg.genre_id
-		WHERE dg.drama_id = $1
+		WHERE dg.drama_id = $1 AND g.deleted_at IS NULL
+		ORDER BY g.display_order, g.name
 	`
 	gRows, err := db.Query(ctx, genreQuery, id)
 	if err == nil {
@@ -177,10 +357,11 @@ func (r *repository) FindByID(ctx context.Context, id string) (*Drama, error) {
 
 	// 3. Fetch Actors
 	actorQuery := `
-		SELECT a.id, a.name, a.photo_url, da.role
+		SELECT a.id, a.name, a.slug, a.photo_url, da.role, da.character_name, da.billing_order, da.is_cameo, da.is_guest
 		FROM actors a
 		JOIN drama_actors da ON a.id = da.actor_id
-		WHERE da.drama_id = $1
+		WHERE da.drama_id = $1 AND a.deleted_at IS NULL
+		ORDER BY da.billing_order, a.name, da.character_name
 	`
 	aRows, err := db.Query(ctx, actorQuery, id)
 	if err == nil {
@@ -188,7 +369,7 @@ func (r *repository) FindByID(ctx context.Context, id string) (*Drama, error) {
 		for aRows.Next() {
 			var da DramaActor
 			var photo *string
-			if err := aRows.Scan(&da.Actor.ID, &da.Actor.Name, &photo, &da.Role); err == nil {
+			if err := aRows.Scan(&da.Actor.ID, &da.Actor.Name, &da.Actor.Slug, &photo, &da.Role, &da.Character, &da.Billing, &da.IsCameo, &da.IsGuest); err == nil {
 				if photo != nil {
 					da.Actor.PhotoURL = *photo
 				}
@@ -197,10 +378,191 @@ func (r *repository) FindByID(ctx context.Context, id string) (*Drama, error) {
 		}
 	}
 
+	// 4. Fetch Crew
+	crewQuery := `
+		SELECT a.id, a.name, a.slug, a.photo_url, cr.job
+		FROM actors a
+		JOIN drama_crew cr ON a.id = cr.actor_id
+		WHERE cr.drama_id = $1 AND a.deleted_at IS NULL
+		ORDER BY cr.job, a.name
+	`
+	cRows, err := db.Query(ctx, crewQuery, id)
+	if err == nil {
+		defer cRows.Close()
+		for cRows.Next() {
+			var cc CrewCredit
+			var photo *string
+			if err := cRows.Scan(&cc.Person.ID, &cc.Person.Name, &cc.Person.Slug, &photo, &cc.Job); err == nil {
+				if photo != nil {
+					cc.Person.PhotoURL = *photo
+				}
+				d.Crew = append(d.Crew, cc)
+			}
+		}
+	}
+
+	// 5. Fetch Networks and Production Companies
+	companyQuery := `
+		SELECT c.id, c.name, c.slug, c.type, c.logo_url, c.created_at
+		FROM companies c
+		JOIN drama_companies dc ON c.id = dc.company_id
+		WHERE dc.drama_id = $1
+		ORDER BY c.name
+	`
+	coRows, err := db.Query(ctx, companyQuery, id)
+	if err == nil {
+		defer coRows.Close()
+		for coRows.Next() {
+			var co company.Company
+			var logo *string
+			if err := coRows.Scan(&co.ID, &co.Name, &co.Slug, &co.Type, &logo, &co.CreatedAt); err == nil {
+				if logo != nil {
+					co.LogoURL = *logo
+				}
+				if co.Type == "network" {
+					d.Networks = append(d.Networks, co)
+				} else {
+					d.Production = append(d.Production, co)
+				}
+			}
+		}
+	}
+
+	// 6. Fetch Tags
+	tagQuery := `
+		SELECT t.id, t.name, t.slug, t.category
+		FROM tags t
+		JOIN drama_tags dt ON t.id = dt.tag_id
+		WHERE dt.drama_id = $1
+		ORDER BY t.category, t.name
+	`
+	tRows, err := db.Query(ctx, tagQuery, id)
+	if err == nil {
+		defer tRows.Close()
+		for tRows.Next() {
+			var t tag.Tag
+			if err := tRows.Scan(&t.ID, &t.Name, &t.Slug, &t.Category); err == nil {
+				d.Tags = append(d.Tags, t)
+			}
+		}
+	}
+
+	// 7. Fetch Advisories
+	advisoryQuery := `
+		SELECT a.id, a.name, a.slug, a.description
+		FROM advisories a
+		JOIN drama_advisories da ON a.id = da.advisory_id
+		WHERE da.drama_id = $1
+		ORDER BY a.display_order, a.name
+	`
+	advRows, err := db.Query(ctx, advisoryQuery, id)
+	if err == nil {
+		defer advRows.Close()
+		for advRows.Next() {
+			var a advisory.Advisory
+			var description *string
+			if err := advRows.Scan(&a.ID, &a.Name, &a.Slug, &description); err == nil {
+				if description != nil {
+					a.Description = *description
+				}
+				d.Advisories = append(d.Advisories, a)
+			}
+		}
+	}
+
+	// 8. Fetch Broadcast Slots
+	slotQuery := `
+		SELECT id, drama_id, weekday, to_char(air_time, 'HH24:MI'), timezone,
+		       to_char(start_date, 'YYYY-MM-DD'), to_char(end_date, 'YYYY-MM-DD'), created_at
+		FROM broadcast_slots
+		WHERE drama_id = $1
+		ORDER BY start_date, weekday, air_time
+	`
+	bRows, err := db.Query(ctx, slotQuery, id)
+	if err == nil {
+		defer bRows.Close()
+		for bRows.Next() {
+			var sl schedule.Slot
+			var endDate *string
+			if err := bRows.Scan(&sl.ID, &sl.DramaID, &sl.Weekday, &sl.AirTime, &sl.Timezone, &sl.StartDate, &endDate, &sl.CreatedAt); err == nil {
+				if endDate != nil {
+					sl.EndDate = *endDate
+				}
+				d.Broadcast = append(d.Broadcast, sl)
+			}
+		}
+	}
+
+	// 9. Fetch Media Gallery
+	mediaQuery := `
+		SELECT id, drama_id, type, url, COALESCE(title, ''), COALESCE(language, ''), COALESCE(aspect_ratio, ''),
+		       COALESCE(width, 0), COALESCE(height, 0), position, is_primary, created_at, updated_at
+		FROM drama_media
+		WHERE drama_id = $1
+		ORDER BY type, is_primary DESC, position, created_at
+	`
+	mRows, err := db.Query(ctx, mediaQuery, id)
+	if err == nil {
+		defer mRows.Close()
+		var items []media.Media
+		for mRows.Next() {
+			var m media.Media
+			if err := mRows.Scan(&m.ID, &m.DramaID, &m.Type, &m.URL, &m.Title, &m.Language, &m.AspectRatio,
+				&m.Width, &m.Height, &m.Position, &m.IsPrimary, &m.CreatedAt, &m.UpdatedAt); err == nil {
+				items = append(items, m)
+			}
+		}
+		if len(items) > 0 {
+			d.Gallery = media.Group(items)
+		}
+	}
+
 	return &d, nil
 }
 
-func (r *repository) Create(ctx context.Context, drama *Drama, genreIDs []string, actors []DramaActorReq, startTime time.Time) error {
+func (r *repository) FindBySlug(ctx context.Context, slug string) (*Drama, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	// Current slug wins over a redirect left behind by a rename
+	query := `
+		SELECT id FROM (
+			SELECT id, 0 AS priority FROM dramas WHERE slug = $1
+			UNION ALL
+			SELECT entity_id, 1 FROM slug_redirects WHERE entity_type = 'drama' AND slug = $1
+		) s
+		ORDER BY priority
+		LIMIT 1
+	`
+	var id string
+	if err := db.QueryRow(ctx, query, slug).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.FindByID(ctx, id)
+}
+
+func (r *repository) SlugExists(ctx context.Context, slug, excludeID string) (bool, error) {
+	db := database.GetDB()
+	if db == nil {
+		return false, errors.New("database not connected")
+	}
+
+	// Slugs held by redirects of other dramas are taken as well, otherwise old links would change target
+	query := `
+		SELECT EXISTS(SELECT 1 FROM dramas WHERE slug = $1 AND id::text <> $2)
+		    OR EXISTS(SELECT 1 FROM slug_redirects WHERE entity_type = 'drama' AND slug = $1 AND entity_id::text <> $2)
+	`
+	var exists bool
+	err := db.QueryRow(ctx, query, slug, excludeID).Scan(&exists)
+	return exists, err
+}
+
+func (r *repository) Create(ctx context.Context, drama *Drama, genreIDs []string, actors []DramaActorReq, startTime time.Time, edit Edit) error {
 	db := database.GetDB()
 	if db == nil {
 		return errors.New("database not connected")
@@ -214,17 +576,25 @@ func (r *repository) Create(ctx context.Context, drama *Drama, genreIDs []string
 
 	// 1. Insert Drama
 	query := `
-		INSERT INTO dramas (title, synopsis, poster_url, year, total_seasons, status, source_url, added_by, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO dramas (title, slug, synopsis, year, status, premiere_date, country, original_language,
+		                    source_url, publication_status, publish_at, added_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, '')::date, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING id
 	`
 	err = tx.QueryRow(ctx, query,
-		drama.Title, drama.Synopsis, drama.PosterURL, drama.Year, drama.TotalSeasons,
-		drama.Status, drama.SourceURL, drama.AddedBy, startTime, startTime,
+		drama.Title, drama.Slug, drama.Synopsis, drama.Year, drama.Status, drama.PremiereDate,
+		drama.Country, drama.Language, drama.SourceURL, drama.Publication, drama.PublishAt, drama.AddedBy, startTime, startTime,
 	).Scan(&drama.ID)
 	if err != nil {
 		return err
 	}
+	if err := SetPoster(ctx, tx, drama.ID, drama.PosterURL, startTime); err != nil {
+		return err
+	}
+
+	if err := RecordInitialStatus(ctx, tx, drama.ID, drama.Status, edit.UserID, startTime); err != nil {
+		return err
+	}
 
 	// 2. Insert Genres
 	if len(genreIDs) > 0 {
@@ -240,18 +610,20 @@ func (r *repository) Create(ctx context.Context, drama *Drama, genreIDs []string
 
 	// 3. Insert Actors
 	if len(actors) > 0 {
-		for _, act := range actors {
-			_, err := tx.Exec(ctx, "INSERT INTO drama_actors (drama_id, actor_id, role) VALUES ($1, $2, $3)", drama.ID, act.ActorID, act.Role)
-			if err != nil {
-				return fmt.Errorf("failed to add actor %s: %v", act.ActorID, err)
-			}
+		if err := SyncCast(ctx, tx, drama.ID, actors); err != nil {
+			return err
 		}
 	}
 
+	// 4. First revision
+	if err := RecordRevision(ctx, tx, drama.ID, edit.Action, edit.UserID, edit.RestoredFrom, startTime); err != nil {
+		return err
+	}
+
 	return tx.Commit(ctx)
 }
 
-func (r *repository) Update(ctx context.Context, drama *Drama, genreIDs []string, actors []DramaActorReq) error {
+func (r *repository) Update(ctx context.Context, drama *Drama, genreIDs []string, actors []DramaActorReq, edit Edit) error {
 	db := database.GetDB()
 	if db == nil {
 		return errors.New("database not connected")
@@ -263,48 +635,120 @@ func (r *repository) Update(ctx context.Context, drama *Drama, genreIDs []string
 	}
 	defer tx.Rollback(ctx)
 
-	// 1. Update Drama Fields
+	// 1. Keep the old slug reachable if it is being renamed; the row stays locked
+	// until the revision is recorded
+	var oldSlug string
+	if err := tx.QueryRow(ctx, "SELECT slug FROM dramas WHERE id = $1 FOR UPDATE", drama.ID).Scan(&oldSlug); err != nil {
+		return err
+	}
+	if err := database.RedirectSlug(ctx, tx, "drama", oldSlug, drama.Slug, drama.ID); err != nil {
+		return err
+	}
+
+	// 2. Update Drama Fields; status goes through the lifecycle check
+	now := time.Now()
+	if err := ChangeStatus(ctx, tx, drama.ID, drama.Status, edit.UserID, now); err != nil {
+		return err
+	}
 	query := `
 		UPDATE dramas
-		SET title=$1, synopsis=$2, poster_url=$3, year=$4, total_seasons=$5, status=$6, source_url=$7, updated_at=$8
-		WHERE id=$9
+		SET title=$1, slug=$2, synopsis=$3, year=$4, premiere_date=NULLIF($5, '')::date,
+		    country=$6, original_language=$7, source_url=$8, updated_at=$9
+		WHERE id=$10
 	`
 	_, err = tx.Exec(ctx, query,
-		drama.Title, drama.Synopsis, drama.PosterURL, drama.Year, drama.TotalSeasons,
-		drama.Status, drama.SourceURL, time.Now(), drama.ID,
+		drama.Title, drama.Slug, drama.Synopsis, drama.Year, drama.PremiereDate,
+		drama.Country, drama.Language, drama.SourceURL, now, drama.ID,
 	)
 	if err != nil {
 		return err
 	}
+	if err := SetPoster(ctx, tx, drama.ID, drama.PosterURL, now); err != nil {
+		return err
+	}
 
-	// 2. Update Genres (Strategy: Delete All & Re-insert) helps avoid diff logic complexity
-	_, err = tx.Exec(ctx, "DELETE FROM drama_genres WHERE drama_id = $1", drama.ID)
+	// 3. Sync Genres & Actors (only rows that actually differ are touched)
+	if err := SyncGenres(ctx, tx, drama.ID, genreIDs); err != nil {
+		return err
+	}
+	if err := SyncCast(ctx, tx, drama.ID, actors); err != nil {
+		return err
+	}
+
+	// 4. Revision
+	if err := RecordRevision(ctx, tx, drama.ID, edit.Action, edit.UserID, edit.RestoredFrom, now); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// patchColumns lists the drama columns a patch may set; status and poster_url go
+// through ChangeStatus and SetPoster
+var patchColumns = []string{
+	"title", "slug", "synopsis", "year", "premiere_date", "country", "original_language", "source_url", "updated_at",
+}
+
+// Patch writes the supplied fields; nil genreIDs/actors leave the associations untouched
+func (r *repository) Patch(ctx context.Context, id string, fields map[string]interface{}, genreIDs []string, actors []DramaActorReq, edit Edit) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
 	if err != nil {
 		return err
 	}
-	if len(genreIDs) > 0 {
-		for _, gid := range genreIDs {
-			_, err := tx.Exec(ctx, "INSERT INTO drama_genres (drama_id, genre_id) VALUES ($1, $2)", drama.ID, gid)
-			if err != nil {
-				return err
-			}
+	defer tx.Rollback(ctx)
+
+	// 1. Lock the row until the revision is recorded and keep the old slug
+	// reachable if it is being renamed
+	var oldSlug string
+	if err := tx.QueryRow(ctx, "SELECT slug FROM dramas WHERE id = $1 FOR UPDATE", id).Scan(&oldSlug); err != nil {
+		return err
+	}
+	if newSlug, ok := fields["slug"].(string); ok {
+		if err := database.RedirectSlug(ctx, tx, "drama", oldSlug, newSlug, id); err != nil {
+			return err
 		}
 	}
 
-	// 3. Update Actors (Strategy: Delete All & Re-insert)
-	_, err = tx.Exec(ctx, "DELETE FROM drama_actors WHERE drama_id = $1", drama.ID)
-	if err != nil {
+	// 2. Update supplied fields only
+	now := time.Now()
+	if status, ok := fields["status"].(string); ok {
+		if err := ChangeStatus(ctx, tx, id, status, edit.UserID, now); err != nil {
+			return err
+		}
+	}
+	fields["updated_at"] = now
+	if err := patch.Update(ctx, tx, "dramas", patchColumns, fields, id); err != nil {
 		return err
 	}
-	if len(actors) > 0 {
-		for _, act := range actors {
-			_, err := tx.Exec(ctx, "INSERT INTO drama_actors (drama_id, actor_id, role) VALUES ($1, $2, $3)", drama.ID, act.ActorID, act.Role)
-			if err != nil {
-				return err
-			}
+	if poster, ok := fields["poster_url"]; ok {
+		url, _ := poster.(string)
+		if err := SetPoster(ctx, tx, id, url, now); err != nil {
+			return err
 		}
 	}
 
+	// 3. Sync associations that were supplied
+	if genreIDs != nil {
+		if err := SyncGenres(ctx, tx, id, genreIDs); err != nil {
+			return err
+		}
+	}
+	if actors != nil {
+		if err := SyncCast(ctx, tx, id, actors); err != nil {
+			return err
+		}
+	}
+
+	// 4. Revision
+	if err := RecordRevision(ctx, tx, id, edit.Action, edit.UserID, edit.RestoredFrom, now); err != nil {
+		return err
+	}
+
 	return tx.Commit(ctx)
 }
 
@@ -313,7 +757,477 @@ func (r *repository) Delete(ctx context.Context, id string) error {
 	if db == nil {
 		return errors.New("database not connected")
 	}
-	// Cascade delete handles relations
-	_, err := db.Exec(ctx, "DELETE FROM dramas WHERE id = $1", id)
+	// Soft delete: seasons, episodes and user data stay intact until the trash is purged
+	_, err := db.Exec(ctx, "UPDATE dramas SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL", time.Now(), id)
 	return err
 }
+
+func (r *repository) SetPublication(ctx context.Context, id, publication string, publishAt *time.Time) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	query := `UPDATE dramas SET publication_status = $1, publish_at = $2, updated_at = $3 WHERE id = $4`
+	_, err := db.Exec(ctx, query, publication, publishAt, time.Now(), id)
+	return err
+}
+
+// PublishDue flips scheduled dramas whose publish time has passed to published
+func (r *repository) PublishDue(ctx context.Context, now time.Time) (int64, error) {
+	db := database.GetDB()
+	if db == nil {
+		return 0, errors.New("database not connected")
+	}
+
+	query := `
+		UPDATE dramas SET publication_status = 'published', updated_at = $1
+		WHERE publication_status = 'scheduled' AND publish_at <= $1 AND deleted_at IS NULL
+	`
+	tag, err := db.Exec(ctx, query, now)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *repository) FindStatusHistory(ctx context.Context, dramaID string) ([]StatusChange, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	query := `
+		SELECT id, drama_id, from_status, to_status, changed_by, changed_at
+		FROM drama_status_history
+		WHERE drama_id = $1
+		ORDER BY changed_at DESC
+	`
+	rows, err := db.Query(ctx, query, dramaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []StatusChange{}
+	for rows.Next() {
+		var sc StatusChange
+		var fromStatus, changedBy *string
+		if err := rows.Scan(&sc.ID, &sc.DramaID, &fromStatus, &sc.ToStatus, &changedBy, &sc.ChangedAt); err != nil {
+			return nil, err
+		}
+		if fromStatus != nil {
+			sc.FromStatus = *fromStatus
+		}
+		if changedBy != nil {
+			sc.ChangedBy = *changedBy
+		}
+		history = append(history, sc)
+	}
+	return history, rows.Err()
+}
+
+func (r *repository) FindRevisions(ctx context.Context, dramaID string, limit, offset int) ([]Revision, int64, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, 0, errors.New("database not connected")
+	}
+
+	var total int64
+	err := db.QueryRow(ctx, "SELECT COUNT(*) FROM drama_revisions WHERE drama_id = $1", dramaID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT id, drama_id, revision_number, action, restored_from, snapshot, edited_by, created_at
+		FROM drama_revisions
+		WHERE drama_id = $1
+		ORDER BY revision_number DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := db.Query(ctx, query, dramaID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var revisions []Revision
+	for rows.Next() {
+		var rev Revision
+		var editedBy *string
+		if err := rows.Scan(
+			&rev.ID, &rev.DramaID, &rev.RevisionNumber, &rev.Action, &rev.RestoredFrom,
+			&rev.Snapshot, &editedBy, &rev.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		if editedBy != nil {
+			rev.EditedBy = *editedBy
+		}
+		revisions = append(revisions, rev)
+	}
+
+	return revisions, total, nil
+}
+
+func (r *repository) FindRevision(ctx context.Context, dramaID string, number int) (*Revision, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	query := `
+		SELECT id, drama_id, revision_number, action, restored_from, snapshot, edited_by, created_at
+		FROM drama_revisions
+		WHERE drama_id = $1 AND revision_number = $2
+	`
+	var rev Revision
+	var editedBy *string
+	err := db.QueryRow(ctx, query, dramaID, number).Scan(
+		&rev.ID, &rev.DramaID, &rev.RevisionNumber, &rev.Action, &rev.RestoredFrom,
+		&rev.Snapshot, &editedBy, &rev.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if editedBy != nil {
+		rev.EditedBy = *editedBy
+	}
+	return &rev, nil
+}
+
+func (r *repository) SnapshotRestorable(ctx context.Context, snap Snapshot) (bool, error) {
+	db := database.GetDB()
+	if db == nil {
+		return false, errors.New("database not connected")
+	}
+
+	genreIDs := append([]string{}, snap.GenreIDs...)
+	actorIDs := []string{}
+	for _, a := range snap.Actors {
+		actorIDs = append(actorIDs, a.ActorID)
+	}
+
+	query := `
+		SELECT (SELECT COUNT(*) FROM genres WHERE id::text = ANY($1)) = cardinality(ARRAY(SELECT DISTINCT unnest($1::text[])))
+		   AND (SELECT COUNT(*) FROM actors WHERE id::text = ANY($2)) = cardinality(ARRAY(SELECT DISTINCT unnest($2::text[])))
+	`
+	var ok bool
+	err := db.QueryRow(ctx, query, genreIDs, actorIDs).Scan(&ok)
+	return ok, err
+}
+
+// SlugTaken checks a slug of the given entity type against current rows, trashed
+// ones included, and the redirects left by renames. Importers and metadata
+// syncs use it to pick slugs inside their transaction.
+func SlugTaken(ctx context.Context, tx pgx.Tx, entityType, table, slug string) (bool, error) {
+	query := fmt.Sprintf(`
+		SELECT EXISTS(SELECT 1 FROM %s WHERE slug = $1)
+		    OR EXISTS(SELECT 1 FROM slug_redirects WHERE entity_type = $2 AND slug = $1)
+	`, table)
+	var exists bool
+	err := tx.QueryRow(ctx, query, slug, entityType).Scan(&exists)
+	return exists, err
+}
+
+// SyncGenres removes genres missing from genreIDs and adds new ones, leaving
+// unchanged rows alone. Importers and metadata syncs share it with drama edits.
+func SyncGenres(ctx context.Context, tx pgx.Tx, dramaID string, genreIDs []string) error {
+	if genreIDs == nil {
+		genreIDs = []string{} // nil would be sent as NULL and match nothing
+	}
+	_, err := tx.Exec(ctx, "DELETE FROM drama_genres WHERE drama_id = $1 AND NOT (genre_id::text = ANY($2))", dramaID, genreIDs)
+	if err != nil {
+		return err
+	}
+	for _, gid := range genreIDs {
+		_, err := tx.Exec(ctx, "INSERT INTO drama_genres (drama_id, genre_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", dramaID, gid)
+		if err != nil {
+			return fmt.Errorf("failed to add genre %s: %v", gid, err)
+		}
+	}
+	return nil
+}
+
+// NormalizeCast drops repeated actor/character credits, keeping the first, and
+// bills credits without a billing order by their position in the list
+func NormalizeCast(actors []DramaActorReq) []DramaActorReq {
+	cast := make([]DramaActorReq, 0, len(actors))
+	seen := map[[2]string]bool{}
+	for i, act := range actors {
+		act.Character = strings.TrimSpace(act.Character)
+		key := [2]string{act.ActorID, act.Character}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if act.Billing == 0 {
+			act.Billing = i + 1
+		}
+		cast = append(cast, act)
+	}
+	return cast
+}
+
+// SyncCast removes credits missing from actors, adds new credits and updates
+// changed ones. Importers and metadata syncs share it with drama edits.
+func SyncCast(ctx context.Context, q execer, dramaID string, actors []DramaActorReq) error {
+	actors = NormalizeCast(actors)
+	actorIDs := make([]string, 0, len(actors))
+	characters := make([]string, 0, len(actors))
+	for _, act := range actors {
+		actorIDs = append(actorIDs, act.ActorID)
+		characters = append(characters, act.Character)
+	}
+
+	_, err := q.Exec(ctx, `
+		DELETE FROM drama_actors da
+		WHERE da.drama_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM unnest($2::uuid[], $3::text[]) AS k(actor_id, character_name)
+			WHERE k.actor_id = da.actor_id AND k.character_name = da.character_name
+		)
+	`, dramaID, actorIDs, characters)
+	if err != nil {
+		return err
+	}
+	for _, act := range actors {
+		_, err := q.Exec(ctx, `
+			INSERT INTO drama_actors (drama_id, actor_id, role, character_name, billing_order, is_cameo, is_guest)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (drama_id, actor_id, character_name) DO UPDATE
+			SET role = EXCLUDED.role, billing_order = EXCLUDED.billing_order,
+			    is_cameo = EXCLUDED.is_cameo, is_guest = EXCLUDED.is_guest
+			WHERE (drama_actors.role, drama_actors.billing_order, drama_actors.is_cameo, drama_actors.is_guest)
+			      IS DISTINCT FROM (EXCLUDED.role, EXCLUDED.billing_order, EXCLUDED.is_cameo, EXCLUDED.is_guest)
+		`, dramaID, act.ActorID, act.Role, act.Character, act.Billing, act.IsCameo, act.IsGuest)
+		if err != nil {
+			return fmt.Errorf("failed to add actor %s: %v", act.ActorID, err)
+		}
+	}
+	return nil
+}
+
+func (r *repository) SetCrew(ctx context.Context, id string, crew []CrewCreditReq) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DELETE FROM drama_crew WHERE drama_id = $1", id); err != nil {
+		return err
+	}
+	for _, cr := range crew {
+		_, err := tx.Exec(ctx, "INSERT INTO drama_crew (drama_id, actor_id, job) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING", id, cr.ActorID, cr.Job)
+		if err != nil {
+			return fmt.Errorf("failed to add crew %s: %v", cr.ActorID, err)
+		}
+	}
+	if _, err := tx.Exec(ctx, "UPDATE dramas SET updated_at = $1 WHERE id = $2", time.Now(), id); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *repository) SetCompanies(ctx context.Context, id string, companyIDs []string) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DELETE FROM drama_companies WHERE drama_id = $1", id); err != nil {
+		return err
+	}
+	for _, companyID := range companyIDs {
+		_, err := tx.Exec(ctx, "INSERT INTO drama_companies (drama_id, company_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", id, companyID)
+		if err != nil {
+			return fmt.Errorf("failed to add company %s: %v", companyID, err)
+		}
+	}
+	if _, err := tx.Exec(ctx, "UPDATE dramas SET updated_at = $1 WHERE id = $2", time.Now(), id); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *repository) CountActors(ctx context.Context, ids []string) (int, error) {
+	db := database.GetDB()
+	if db == nil {
+		return 0, errors.New("database not connected")
+	}
+
+	var count int
+	err := db.QueryRow(ctx, "SELECT COUNT(*) FROM actors WHERE id = ANY($1) AND deleted_at IS NULL", ids).Scan(&count)
+	return count, err
+}
+
+func (r *repository) CountCompanies(ctx context.Context, ids []string) (int, error) {
+	db := database.GetDB()
+	if db == nil {
+		return 0, errors.New("database not connected")
+	}
+
+	var count int
+	err := db.QueryRow(ctx, "SELECT COUNT(*) FROM companies WHERE id = ANY($1)", ids).Scan(&count)
+	return count, err
+}
+
+// FindSimilar scores published dramas sharing a genre or cast member with the
+// given drama: 3 points per shared genre, 2 per shared actor, up to 2 for a
+// release year within ten years and up to 1 for rating
+func (r *repository) FindSimilar(ctx context.Context, id string, limit int) ([]SimilarDrama, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	query := `
+		WITH shared_genres AS (
+			SELECT dg.drama_id, COUNT(*) AS n
+			FROM drama_genres dg
+			JOIN genres g ON g.id = dg.genre_id AND g.deleted_at IS NULL
+			WHERE dg.genre_id IN (SELECT genre_id FROM drama_genres WHERE drama_id = $1) AND dg.drama_id <> $1
+			GROUP BY dg.drama_id
+		), shared_actors AS (
+			SELECT da.drama_id, COUNT(DISTINCT da.actor_id) AS n
+			FROM drama_actors da
+			JOIN actors a ON a.id = da.actor_id AND a.deleted_at IS NULL
+			WHERE da.actor_id IN (SELECT actor_id FROM drama_actors WHERE drama_id = $1) AND da.drama_id <> $1
+			GROUP BY da.drama_id
+		)
+		SELECT d.id, d.title, d.slug, d.poster_url, d.year, d.rating, d.status, d.view_count, d.publication_status, d.publish_at, d.created_at,
+		       COALESCE(sg.n, 0), COALESCE(sa.n, 0),
+		       (COALESCE(sg.n, 0) * 3 + COALESCE(sa.n, 0) * 2
+		        + GREATEST(0, 1 - ABS(d.year - base.year) / 10.0) * 2
+		        + COALESCE(d.rating, 0) / 10.0)::float8 AS score
+		FROM dramas d
+		CROSS JOIN (SELECT year FROM dramas WHERE id = $1) base
+		LEFT JOIN shared_genres sg ON sg.drama_id = d.id
+		LEFT JOIN shared_actors sa ON sa.drama_id = d.id
+		WHERE d.id <> $1 AND d.deleted_at IS NULL AND d.publication_status = 'published'
+		  AND (sg.n IS NOT NULL OR sa.n IS NOT NULL)
+		ORDER BY score DESC, d.view_count DESC, d.id
+		LIMIT $2
+	`
+	rows, err := db.Query(ctx, query, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	similar := []SimilarDrama{}
+	for rows.Next() {
+		var s SimilarDrama
+		var poster *string
+		err := rows.Scan(
+			&s.ID, &s.Title, &s.Slug, &poster, &s.Year, &s.Rating, &s.Status, &s.ViewCount, &s.Publication, &s.PublishAt, &s.CreatedAt,
+			&s.SharedGenres, &s.SharedActors, &s.Score,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if poster != nil {
+			s.PosterURL = *poster
+		}
+		similar = append(similar, s)
+	}
+	return similar, rows.Err()
+}
+
+func (r *repository) FindExcluded(ctx context.Context, userID string, ids []string) (map[string]bool, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	query := "SELECT id FROM dramas WHERE id = ANY($2) AND id IN (" + ExcludedDramas(ViewerExclusions("$1")) + ")"
+	rows, err := db.Query(ctx, query, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	excluded := map[string]bool{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		excluded[id] = true
+	}
+	return excluded, rows.Err()
+}
+
+// FindFinished reports which of the dramas the user has completed every published episode of
+func (r *repository) FindFinished(ctx context.Context, userID string, ids []string) (map[string]bool, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	query := `
+		SELECT s.drama_id
+		FROM episodes e
+		JOIN seasons s ON s.id = e.season_id
+		LEFT JOIN watch_history wh ON wh.episode_id = e.id AND wh.user_id = $1 AND wh.completed
+		WHERE s.drama_id = ANY($2) AND e.deleted_at IS NULL AND s.deleted_at IS NULL AND e.publication_status = 'published'
+		GROUP BY s.drama_id
+		HAVING COUNT(*) = COUNT(wh.id)
+	`
+	rows, err := db.Query(ctx, query, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	finished := map[string]bool{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		finished[id] = true
+	}
+	return finished, rows.Err()
+}
+
+// CatalogVersion fingerprints the data recommendations are scored from; it
+// changes whenever a drama is added, edited, rated, published, trashed or
+// purged, or a genre or actor is trashed or restored. It scans several whole
+// tables, so the service caches it for a short while.
+func (r *repository) CatalogVersion(ctx context.Context) (string, error) {
+	db := database.GetDB()
+	if db == nil {
+		return "", errors.New("database not connected")
+	}
+
+	var version string
+	err := db.QueryRow(ctx, `
+		SELECT concat_ws('|',
+			(SELECT COUNT(*) FROM dramas),
+			(SELECT MAX(updated_at) FROM dramas),
+			(SELECT MAX(deleted_at) FROM dramas),
+			(SELECT SUM(rating) FROM dramas),
+			(SELECT COUNT(*) FROM drama_genres),
+			(SELECT COUNT(*) FROM drama_actors),
+			(SELECT COUNT(deleted_at) || ':' || COALESCE(MAX(deleted_at)::text, '') FROM genres),
+			(SELECT COUNT(deleted_at) || ':' || COALESCE(MAX(deleted_at)::text, '') FROM actors)
+		)
+	`).Scan(&version)
+	return version, err
+}
@@ -0,0 +1,97 @@
+package drama
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// RecordRevision snapshots the drama as it stands inside tx and appends it to
+// its revision history. Every writer (editor, importer, metadata sync) calls it
+// in the transaction of its change. The drama row is locked before the next
+// revision number is taken, so concurrent edits are numbered one after the other.
+func RecordRevision(ctx context.Context, tx pgx.Tx, dramaID, action, editedBy string, restoredFrom *int, now time.Time) error {
+	snap, err := loadSnapshot(ctx, tx, dramaID)
+	if err != nil {
+		return err
+	}
+
+	var editor *string
+	if editedBy != "" {
+		editor = &editedBy
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO drama_revisions (drama_id, revision_number, action, restored_from, snapshot, edited_by, created_at)
+		VALUES ($1, (SELECT COALESCE(MAX(revision_number), 0) + 1 FROM drama_revisions WHERE drama_id = $1), $2, $3, $4, $5, $6)
+	`, dramaID, action, restoredFrom, snap, editor, now)
+	return err
+}
+
+// loadSnapshot reads the editable state of a drama and locks its row. Genres and
+// actors in the trash are still linked, so they stay in the snapshot and a
+// rollback keeps them attached.
+func loadSnapshot(ctx context.Context, tx pgx.Tx, dramaID string) (Snapshot, error) {
+	snap := Snapshot{GenreIDs: []string{}, Actors: []DramaActorReq{}}
+	var synopsis, poster, premiere, source *string
+	err := tx.QueryRow(ctx, `
+		SELECT title, slug, synopsis, poster_url, COALESCE(year, 0), status, to_char(premiere_date, 'YYYY-MM-DD'),
+		       country, original_language, source_url
+		FROM dramas WHERE id = $1
+		FOR UPDATE
+	`, dramaID).Scan(
+		&snap.Title, &snap.Slug, &synopsis, &poster, &snap.Year, &snap.Status, &premiere,
+		&snap.Country, &snap.Language, &source,
+	)
+	if err != nil {
+		return snap, err
+	}
+	if synopsis != nil {
+		snap.Synopsis = *synopsis
+	}
+	if poster != nil {
+		snap.PosterURL = *poster
+	}
+	if premiere != nil {
+		snap.PremiereDate = *premiere
+	}
+	if source != nil {
+		snap.SourceURL = *source
+	}
+
+	gRows, err := tx.Query(ctx, "SELECT genre_id FROM drama_genres WHERE drama_id = $1 ORDER BY genre_id", dramaID)
+	if err != nil {
+		return snap, err
+	}
+	for gRows.Next() {
+		var id string
+		if err := gRows.Scan(&id); err != nil {
+			gRows.Close()
+			return snap, err
+		}
+		snap.GenreIDs = append(snap.GenreIDs, id)
+	}
+	gRows.Close()
+	if err := gRows.Err(); err != nil {
+		return snap, err
+	}
+
+	aRows, err := tx.Query(ctx, `
+		SELECT actor_id, COALESCE(role, ''), character_name, billing_order, is_cameo, is_guest
+		FROM drama_actors
+		WHERE drama_id = $1
+		ORDER BY billing_order, actor_id, character_name
+	`, dramaID)
+	if err != nil {
+		return snap, err
+	}
+	defer aRows.Close()
+	for aRows.Next() {
+		var a DramaActorReq
+		if err := aRows.Scan(&a.ActorID, &a.Role, &a.Character, &a.Billing, &a.IsCameo, &a.IsGuest); err != nil {
+			return snap, err
+		}
+		snap.Actors = append(snap.Actors, a)
+	}
+	return snap, aRows.Err()
+}
@@ -2,27 +2,58 @@ package drama
 
 import (
 	"context"
+	"drakor-backend/internal/schedule"
+	"drakor-backend/pkg/publish"
+	"drakor-backend/pkg/validator"
 	"errors"
+	"strconv"
 	"time"
 )
 
 type Service interface {
-	GetAll(ctx context.Context, page, limit int, query, genreID, status, sort, year string) ([]Drama, int64, error)
-	GetByID(ctx context.Context, id string) (*Drama, error)
+	GetAll(ctx context.Context, page, limit int, filter Filter) ([]Drama, int64, error)
+	GetFacets(ctx context.Context, filter Filter) (*Facets, error)
+	GetByID(ctx context.Context, id string, preview bool) (*Drama, error)
 	Create(ctx context.Context, userID string, req CreateDramaRequest) (*Drama, error)
-	Update(ctx context.Context, id string, req UpdateDramaRequest) (*Drama, error)
+	Update(ctx context.Context, userID, id string, req UpdateDramaRequest) (*Drama, error)
+	Patch(ctx context.Context, userID, id string, req PatchDramaRequest) (*Drama, error)
 	Delete(ctx context.Context, id string) error
+	// Publication
+	SetPublication(ctx context.Context, id, publication string, publishAt *time.Time) (*Drama, error)
+	StartScheduler(ctx context.Context, interval time.Duration)
+	// Revisions
+	GetRevisions(ctx context.Context, id string, page, limit int) ([]Revision, int64, error)
+	GetRevision(ctx context.Context, id string, number int) (*Revision, error)
+	Rollback(ctx context.Context, userID, id string, number int) (*Drama, error)
+	// Lifecycle
+	GetStatusHistory(ctx context.Context, id string) ([]StatusChange, error)
+	// Credits
+	SetCrew(ctx context.Context, id string, crew []CrewCreditReq) (*Drama, error)
+	SetCompanies(ctx context.Context, id string, companyIDs []string) (*Drama, error)
+	// Recommendations
+	GetSimilar(ctx context.Context, id, userID string, limit int) ([]SimilarDrama, error)
+	// GetExcluded reports which of the dramas carry an advisory userID excluded
+	GetExcluded(ctx context.Context, userID string, ids []string) (map[string]bool, error)
 }
 
+// similarPoolSize is how many recommendations are cached per drama, enough to
+// fill a row after dropping what the user has finished
+const similarPoolSize = 50
+
 type service struct {
-	repo Repository
+	repo    Repository
+	similar *similarCache
 }
 
 func NewService(repo Repository) Service {
-	return &service{repo: repo}
+	return &service{repo: repo, similar: newSimilarCache()}
+}
+
+func (s *service) GetFacets(ctx context.Context, filter Filter) (*Facets, error) {
+	return s.repo.FindFacets(ctx, filter)
 }
 
-func (s *service) GetAll(ctx context.Context, page, limit int, query, genreID, status, sort, year string) ([]Drama, int64, error) {
+func (s *service) GetAll(ctx context.Context, page, limit int, filter Filter) ([]Drama, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -30,36 +61,80 @@ func (s *service) GetAll(ctx context.Context, page, limit int, query, genreID, s
 		limit = 10
 	}
 
-	return s.repo.FindAll(ctx, page, limit, query, genreID, status, sort, year)
+	return s.repo.FindAll(ctx, page, limit, filter)
 }
 
-func (s *service) GetByID(ctx context.Context, id string) (*Drama, error) {
-	return s.repo.FindByID(ctx, id)
+// GetByID accepts either the drama UUID or its (current or former) slug.
+// Unpublished dramas are only returned when preview is set.
+func (s *service) GetByID(ctx context.Context, id string, preview bool) (*Drama, error) {
+	var drama *Drama
+	var err error
+	if validator.IsUUID(id) {
+		drama, err = s.repo.FindByID(ctx, id)
+	} else {
+		drama, err = s.repo.FindBySlug(ctx, id)
+	}
+	if err != nil || drama == nil {
+		return nil, err
+	}
+	if !preview && drama.Publication != "published" {
+		return nil, nil
+	}
+	if drama.Status == "ongoing" || drama.Status == "upcoming" {
+		drama.NextEpisode = schedule.Next(drama.Broadcast, time.Now())
+	}
+	return drama, nil
 }
 
 func (s *service) Create(ctx context.Context, userID string, req CreateDramaRequest) (*Drama, error) {
+	slug, err := s.resolveSlug(ctx, req.Slug, req.Title, req.Year, "")
+	if err != nil {
+		return nil, err
+	}
+
+	publication := req.Publication
+	if publication == "" {
+		publication = "draft"
+	}
+	publishAt, err := publish.ResolveAt(publication, req.PublishAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Country == "" {
+		req.Country = DefaultCountry
+	}
+	if req.Language == "" {
+		req.Language = DefaultLanguage
+	}
+
 	drama := &Drama{
 		Title:        req.Title,
+		Slug:         slug,
 		Synopsis:     req.Synopsis,
 		PosterURL:    req.PosterURL,
 		Year:         req.Year,
-		TotalSeasons: req.TotalSeasons,
 		Status:       req.Status,
+		PremiereDate: req.PremiereDate,
+		Country:      req.Country,
+		Language:     req.Language,
 		SourceURL:    req.SourceURL,
+		Publication:  publication,
+		PublishAt:    publishAt,
 		AddedBy:      userID,
 	}
 
-	if err := s.repo.Create(ctx, drama, req.GenreIDs, req.Actors, time.Now()); err != nil {
+	if err := s.repo.Create(ctx, drama, req.GenreIDs, req.Actors, time.Now(), Edit{UserID: userID, Action: "create"}); err != nil {
 		return nil, err
 	}
 
 	// Refetch full object to return complete structure (or construct it manually)
 	// For performance, constructing manually is better, but fetching guarantees data integrity.
 	// Let's refetch for simplicity and correctness of relations.
-	return s.repo.FindByID(ctx, drama.ID)
+	return s.refetch(ctx, drama.ID)
 }
 
-func (s *service) Update(ctx context.Context, id string, req UpdateDramaRequest) (*Drama, error) {
+func (s *service) Update(ctx context.Context, userID, id string, req UpdateDramaRequest) (*Drama, error) {
 	drama, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -68,22 +143,482 @@ func (s *service) Update(ctx context.Context, id string, req UpdateDramaRequest)
 		return nil, errors.New("drama not found")
 	}
 
-	// Update fields
+	// Slug stays stable when only the title changes, so existing links keep working
+	if req.Slug != "" {
+		slug, err := s.resolveSlug(ctx, req.Slug, req.Title, req.Year, drama.ID)
+		if err != nil {
+			return nil, err
+		}
+		drama.Slug = slug
+	}
+
+	// Update fields; the repository checks the status transition under a row lock
 	drama.Title = req.Title
 	drama.Synopsis = req.Synopsis
 	drama.PosterURL = req.PosterURL
 	drama.Year = req.Year
-	drama.TotalSeasons = req.TotalSeasons
 	drama.Status = req.Status
+	drama.PremiereDate = req.PremiereDate
+	if req.Country != "" {
+		drama.Country = req.Country
+	}
+	if req.Language != "" {
+		drama.Language = req.Language
+	}
 	drama.SourceURL = req.SourceURL
 
-	if err := s.repo.Update(ctx, drama, req.GenreIDs, req.Actors); err != nil {
+	if err := s.repo.Update(ctx, drama, req.GenreIDs, req.Actors, Edit{UserID: userID, Action: "update"}); err != nil {
+		return nil, err
+	}
+
+	return s.refetch(ctx, drama.ID)
+}
+
+func (s *service) Patch(ctx context.Context, userID, id string, req PatchDramaRequest) (*Drama, error) {
+	drama, err := s.repo.FindByID(ctx, id)
+	if err != nil {
 		return nil, err
 	}
+	if drama == nil {
+		return nil, errors.New("drama not found")
+	}
 
-	return s.repo.FindByID(ctx, id)
+	fields := map[string]interface{}{}
+	if req.Title.Set {
+		fields["title"] = req.Title.Value
+	}
+	if req.Slug.Set {
+		title, year := drama.Title, drama.Year
+		if req.Title.Set {
+			title = req.Title.Value
+		}
+		if req.Year.Set {
+			year = req.Year.Value
+		}
+		slug, err := s.resolveSlug(ctx, req.Slug.Value, title, year, drama.ID)
+		if err != nil {
+			return nil, err
+		}
+		fields["slug"] = slug
+	}
+	if req.Synopsis.Set {
+		fields["synopsis"] = req.Synopsis.Column()
+	}
+	if req.PosterURL.Set {
+		fields["poster_url"] = req.PosterURL.Column()
+	}
+	if req.Year.Set {
+		fields["year"] = req.Year.Value
+	}
+	if req.Status.Set {
+		fields["status"] = req.Status.Value
+	}
+	if req.PremiereDate.Set {
+		if req.PremiereDate.Null || req.PremiereDate.Value == "" {
+			fields["premiere_date"] = nil
+		} else {
+			fields["premiere_date"] = req.PremiereDate.Value
+		}
+	}
+	if req.Country.Set {
+		fields["country"] = req.Country.Value
+	}
+	if req.Language.Set {
+		fields["original_language"] = req.Language.Value
+	}
+	if req.SourceURL.Set {
+		fields["source_url"] = req.SourceURL.Column()
+	}
+
+	var genreIDs []string
+	if req.GenreIDs.Set {
+		genreIDs = req.GenreIDs.Value
+	}
+	var actors []DramaActorReq
+	if req.Actors.Set {
+		// null or [] both clear the cast; a non-nil slice tells the repository to sync
+		actors = append([]DramaActorReq{}, req.Actors.Value...)
+	}
+
+	if err := s.repo.Patch(ctx, drama.ID, fields, genreIDs, actors, Edit{UserID: userID, Action: "update"}); err != nil {
+		return nil, err
+	}
+
+	return s.refetch(ctx, drama.ID)
 }
 
 func (s *service) Delete(ctx context.Context, id string) error {
 	return s.repo.Delete(ctx, id)
 }
+
+// SetPublication moves a drama to draft, scheduled, published or unpublished
+func (s *service) SetPublication(ctx context.Context, id, publication string, publishAt *time.Time) (*Drama, error) {
+	drama, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if drama == nil {
+		return nil, errors.New("drama not found")
+	}
+
+	// Keep the original release time when re-publishing
+	if publication == "published" && publishAt == nil {
+		publishAt = drama.PublishAt
+	}
+	publishAt, err = publish.ResolveAt(publication, publishAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetPublication(ctx, drama.ID, publication, publishAt); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, drama.ID)
+}
+
+func (s *service) SetCrew(ctx context.Context, id string, crew []CrewCreditReq) (*Drama, error) {
+	drama, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if drama == nil {
+		return nil, errors.New("drama not found")
+	}
+
+	ids := []string{}
+	seen := map[string]bool{}
+	for _, cr := range crew {
+		if !seen[cr.ActorID] {
+			seen[cr.ActorID] = true
+			ids = append(ids, cr.ActorID)
+		}
+	}
+	if len(ids) > 0 {
+		count, err := s.repo.CountActors(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		if count != len(ids) {
+			return nil, errors.New("actor not found")
+		}
+	}
+
+	if err := s.repo.SetCrew(ctx, drama.ID, crew); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, drama.ID)
+}
+
+func (s *service) SetCompanies(ctx context.Context, id string, companyIDs []string) (*Drama, error) {
+	drama, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if drama == nil {
+		return nil, errors.New("drama not found")
+	}
+
+	ids := []string{}
+	seen := map[string]bool{}
+	for _, companyID := range companyIDs {
+		if !seen[companyID] {
+			seen[companyID] = true
+			ids = append(ids, companyID)
+		}
+	}
+	if len(ids) > 0 {
+		count, err := s.repo.CountCompanies(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		if count != len(ids) {
+			return nil, errors.New("company not found")
+		}
+	}
+
+	if err := s.repo.SetCompanies(ctx, drama.ID, ids); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, drama.ID)
+}
+
+// GetSimilar recommends published dramas like the given one, leaving out those
+// userID (optional) has already finished or hides by advisory
+func (s *service) GetSimilar(ctx context.Context, id, userID string, limit int) ([]SimilarDrama, error) {
+	if limit < 1 || limit > similarPoolSize {
+		limit = 10
+	}
+
+	drama, err := s.GetByID(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+	if drama == nil {
+		return nil, errors.New("drama not found")
+	}
+
+	// The version aggregates several tables, so it is only re-read every versionTTL
+	now := time.Now()
+	version, ok := s.similar.currentVersion(now)
+	if !ok {
+		version, err = s.repo.CatalogVersion(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.similar.checked(version, now)
+	}
+	pool, ok := s.similar.get(version, drama.ID)
+	if !ok {
+		pool, err = s.repo.FindSimilar(ctx, drama.ID, similarPoolSize)
+		if err != nil {
+			return nil, err
+		}
+		s.similar.set(version, drama.ID, pool)
+	}
+
+	// The pool is shared between viewers, so personal filters apply afterwards
+	finished, excluded := map[string]bool{}, map[string]bool{}
+	if userID != "" && len(pool) > 0 {
+		ids := make([]string, 0, len(pool))
+		for _, d := range pool {
+			ids = append(ids, d.ID)
+		}
+		finished, err = s.repo.FindFinished(ctx, userID, ids)
+		if err != nil {
+			return nil, err
+		}
+		excluded, err = s.repo.FindExcluded(ctx, userID, ids)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	similar := []SimilarDrama{}
+	for _, d := range pool {
+		if len(similar) == limit {
+			break
+		}
+		if !finished[d.ID] && !excluded[d.ID] {
+			similar = append(similar, d)
+		}
+	}
+	return similar, nil
+}
+
+func (s *service) GetExcluded(ctx context.Context, userID string, ids []string) (map[string]bool, error) {
+	if userID == "" || len(ids) == 0 {
+		return map[string]bool{}, nil
+	}
+	return s.repo.FindExcluded(ctx, userID, ids)
+}
+
+// StartScheduler publishes scheduled dramas every interval until ctx is cancelled
+func (s *service) StartScheduler(ctx context.Context, interval time.Duration) {
+	publish.StartScheduler(ctx, interval, "dramas", s.repo.PublishDue)
+}
+
+func (s *service) GetRevisions(ctx context.Context, id string, page, limit int) ([]Revision, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	// Fetch one extra (older) revision so the last item on the page can be diffed too
+	revisions, total, err := s.repo.FindRevisions(ctx, id, limit+1, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range revisions {
+		previous := Snapshot{}
+		if i+1 < len(revisions) {
+			previous = revisions[i+1].Snapshot
+		}
+		revisions[i].Changes = diffSnapshots(previous, revisions[i].Snapshot)
+	}
+	if len(revisions) > limit {
+		revisions = revisions[:limit]
+	}
+
+	return revisions, total, nil
+}
+
+func (s *service) GetRevision(ctx context.Context, id string, number int) (*Revision, error) {
+	rev, err := s.repo.FindRevision(ctx, id, number)
+	if err != nil || rev == nil {
+		return rev, err
+	}
+
+	previous := Snapshot{}
+	if number > 1 {
+		prev, err := s.repo.FindRevision(ctx, id, number-1)
+		if err != nil {
+			return nil, err
+		}
+		if prev != nil {
+			previous = prev.Snapshot
+		}
+	}
+	rev.Changes = diffSnapshots(previous, rev.Snapshot)
+
+	return rev, nil
+}
+
+// Rollback re-applies the snapshot of a previous revision and records it as a new revision
+func (s *service) Rollback(ctx context.Context, userID, id string, number int) (*Drama, error) {
+	drama, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if drama == nil {
+		return nil, errors.New("drama not found")
+	}
+
+	rev, err := s.repo.FindRevision(ctx, drama.ID, number)
+	if err != nil {
+		return nil, err
+	}
+	if rev == nil {
+		return nil, errors.New("revision not found")
+	}
+	snap := rev.Snapshot
+
+	// Trashed genres and actors can be linked again; purged ones are gone for good
+	restorable, err := s.repo.SnapshotRestorable(ctx, snap)
+	if err != nil {
+		return nil, err
+	}
+	if !restorable {
+		return nil, errors.New("revision references purged records")
+	}
+
+	// The old slug may have been taken by another drama since; keep the current one then
+	if snap.Slug != "" && snap.Slug != drama.Slug {
+		taken, err := s.repo.SlugExists(ctx, snap.Slug, drama.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !taken {
+			drama.Slug = snap.Slug
+		}
+	}
+
+	// Rolling back must not skip the lifecycle either; Update checks the transition
+	drama.Title = snap.Title
+	drama.Synopsis = snap.Synopsis
+	drama.PosterURL = snap.PosterURL
+	drama.Year = snap.Year
+	drama.Status = snap.Status
+	drama.PremiereDate = snap.PremiereDate
+	if snap.Country != "" { // Revisions from before countries keep the current one
+		drama.Country = snap.Country
+	}
+	if snap.Language != "" {
+		drama.Language = snap.Language
+	}
+	drama.SourceURL = snap.SourceURL
+
+	edit := Edit{UserID: userID, Action: "rollback", RestoredFrom: &number}
+	if err := s.repo.Update(ctx, drama, snap.GenreIDs, snap.Actors, edit); err != nil {
+		return nil, err
+	}
+
+	return s.refetch(ctx, drama.ID)
+}
+
+func (s *service) GetStatusHistory(ctx context.Context, id string) ([]StatusChange, error) {
+	drama, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if drama == nil {
+		return nil, errors.New("drama not found")
+	}
+	return s.repo.FindStatusHistory(ctx, drama.ID)
+}
+
+// refetch loads the drama as it stands after a write
+func (s *service) refetch(ctx context.Context, id string) (*Drama, error) {
+	drama, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if drama == nil {
+		return nil, errors.New("drama not found")
+	}
+	return drama, nil
+}
+
+// diffSnapshots lists the fields that differ between two snapshots.
+// Genres are compared as a set; the cast is compared in billing order.
+func diffSnapshots(from, to Snapshot) []FieldChange {
+	changes := []FieldChange{}
+	add := func(field string, a, b interface{}) {
+		changes = append(changes, FieldChange{Field: field, From: a, To: b})
+	}
+
+	if from.Title != to.Title {
+		add("title", from.Title, to.Title)
+	}
+	if from.Slug != to.Slug {
+		add("slug", from.Slug, to.Slug)
+	}
+	if from.Synopsis != to.Synopsis {
+		add("synopsis", from.Synopsis, to.Synopsis)
+	}
+	if from.PosterURL != to.PosterURL {
+		add("poster_url", from.PosterURL, to.PosterURL)
+	}
+	if from.Year != to.Year {
+		add("year", from.Year, to.Year)
+	}
+	if from.Status != to.Status {
+		add("status", from.Status, to.Status)
+	}
+	if from.PremiereDate != to.PremiereDate {
+		add("premiere_date", from.PremiereDate, to.PremiereDate)
+	}
+	if from.Country != to.Country {
+		add("country", from.Country, to.Country)
+	}
+	if from.Language != to.Language {
+		add("original_language", from.Language, to.Language)
+	}
+	if from.SourceURL != to.SourceURL {
+		add("source_url", from.SourceURL, to.SourceURL)
+	}
+
+	fromGenres := map[string]bool{}
+	for _, id := range from.GenreIDs {
+		fromGenres[id] = true
+	}
+	sameGenres := len(from.GenreIDs) == len(to.GenreIDs)
+	for _, id := range to.GenreIDs {
+		if !fromGenres[id] {
+			sameGenres = false
+		}
+	}
+	if !sameGenres {
+		add("genre_ids", from.GenreIDs, to.GenreIDs)
+	}
+
+	sameActors := len(from.Actors) == len(to.Actors)
+	for i := 0; sameActors && i < len(to.Actors); i++ {
+		sameActors = from.Actors[i] == to.Actors[i]
+	}
+	if !sameActors {
+		add("actors", from.Actors, to.Actors)
+	}
+
+	return changes
+}
+
+// resolveSlug validates a requested slug or generates a unique one from the title,
+// appending the year (then a counter) when the plain title is already taken
+func (s *service) resolveSlug(ctx context.Context, requested, title string, year int, excludeID string) (string, error) {
+	return validator.ResolveSlug(requested, title, "drama", []string{strconv.Itoa(year)}, func(slug string) (bool, error) {
+		return s.repo.SlugExists(ctx, slug, excludeID)
+	})
+}
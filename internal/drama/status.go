@@ -0,0 +1,66 @@
+package drama
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// checkTransition rejects status changes the lifecycle does not allow; keeping
+// the current status is always fine
+func checkTransition(from, to string) error {
+	if from == to {
+		return nil
+	}
+	for _, allowed := range statusTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid status transition from %s to %s", from, to)
+}
+
+// ChangeStatus moves a drama to status inside tx. The row is locked before the
+// current status is read, so two concurrent changes cannot both pass the
+// lifecycle check. Every writer (editor, importer, metadata sync, schedule) goes through
+// it so each change lands in the status history.
+func ChangeStatus(ctx context.Context, tx pgx.Tx, dramaID, status, changedBy string, now time.Time) error {
+	var current string
+	if err := tx.QueryRow(ctx, "SELECT status FROM dramas WHERE id = $1 FOR UPDATE", dramaID).Scan(&current); err != nil {
+		return err
+	}
+	if current == status {
+		return nil
+	}
+	if err := checkTransition(current, status); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, "UPDATE dramas SET status = $1, updated_at = $2 WHERE id = $3", status, now, dramaID); err != nil {
+		return err
+	}
+	return insertStatusChange(ctx, tx, dramaID, current, status, changedBy, now)
+}
+
+// RecordInitialStatus adds the first status history entry of a drama created in tx
+func RecordInitialStatus(ctx context.Context, tx pgx.Tx, dramaID, status, changedBy string, now time.Time) error {
+	return insertStatusChange(ctx, tx, dramaID, "", status, changedBy, now)
+}
+
+func insertStatusChange(ctx context.Context, tx pgx.Tx, dramaID, from, to, changedBy string, now time.Time) error {
+	var fromStatus, editor *string
+	if from != "" {
+		fromStatus = &from
+	}
+	if changedBy != "" {
+		editor = &changedBy
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO drama_status_history (drama_id, from_status, to_status, changed_by, changed_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, dramaID, fromStatus, to, editor, now)
+	return err
+}
@@ -1,9 +1,11 @@
 package episode
 
 import (
+	"drakor-backend/internal/auth"
 	"drakor-backend/pkg/response"
 	"drakor-backend/pkg/validator"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,7 +20,7 @@ func NewHandler(service Service) *Handler {
 
 func (h *Handler) GetBySeasonID(c *gin.Context) {
 	seasonID := c.Param("id")
-	episodes, err := h.service.GetBySeasonID(c.Request.Context(), seasonID)
+	episodes, err := h.service.GetBySeasonID(c.Request.Context(), seasonID, auth.CanPreview(c))
 	if err != nil {
 		response.InternalError(c, "Failed to fetch episodes", err.Error())
 		return
@@ -28,7 +30,7 @@ func (h *Handler) GetBySeasonID(c *gin.Context) {
 
 func (h *Handler) GetByID(c *gin.Context) {
 	id := c.Param("id")
-	episode, err := h.service.GetByID(c.Request.Context(), id)
+	episode, err := h.service.GetByID(c.Request.Context(), id, auth.CanPreview(c))
 	if err != nil {
 		response.InternalError(c, "Failed to fetch episode", err.Error())
 		return
@@ -60,12 +62,92 @@ func (h *Handler) Create(c *gin.Context) {
 
 	episode, err := h.service.Create(c.Request.Context(), userID.(string), req)
 	if err != nil {
+		if err.Error() == "season not found" {
+			response.NotFound(c, "Season not found")
+			return
+		}
+		if err.Error() == "slug already exists" {
+			response.Error(c, http.StatusConflict, err.Error(), "slug_exists")
+			return
+		}
+		if err.Error() == "episode number already exists in this season" {
+			response.Error(c, http.StatusConflict, err.Error(), "episode_number_exists")
+			return
+		}
+		if err.Error() == "episode number belongs to an episode in the trash" {
+			response.Error(c, http.StatusConflict, err.Error(), "episode_in_trash")
+			return
+		}
+		if err.Error() == "invalid slug" {
+			response.BadRequest(c, "Invalid slug", "validation_error")
+			return
+		}
+		if err.Error() == "publish_at must be in the future" {
+			response.BadRequest(c, "Invalid publish_at", err.Error())
+			return
+		}
 		response.InternalError(c, "Failed to create episode", err.Error())
 		return
 	}
 	response.Created(c, "Episode created successfully", episode)
 }
 
+// BulkCreate adds many episodes to the season in one go, listed under "episodes" or
+// generated from a "template". Nothing is saved unless every episode is valid;
+// with ?dry_run=true the episodes are checked and returned but not saved.
+func (h *Handler) BulkCreate(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	var req BulkCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+	report, err := h.service.BulkCreate(c.Request.Context(), userID.(string), c.Param("id"), req, dryRun)
+	if err != nil {
+		if err.Error() == "season not found" {
+			response.NotFound(c, "Season not found")
+			return
+		}
+		if err.Error() == "episodes and template are mutually exclusive" || err.Error() == "no episodes to create" {
+			response.BadRequest(c, "Send either episodes or a template", err.Error())
+			return
+		}
+		if err.Error() == "publish_at must be in the future" {
+			response.BadRequest(c, "Invalid publish_at", err.Error())
+			return
+		}
+		response.InternalError(c, "Failed to create episodes", err.Error())
+		return
+	}
+
+	if !report.Valid {
+		c.JSON(http.StatusBadRequest, response.Response{
+			Success: false,
+			Message: "Validation failed",
+			Data:    report,
+			Error:   "validation_error",
+		})
+		return
+	}
+	if dryRun {
+		response.Success(c, "Dry run completed, nothing was saved", report)
+		return
+	}
+	response.Created(c, "Episodes created successfully", report)
+}
+
 func (h *Handler) Update(c *gin.Context) {
 	id := c.Param("id")
 	var req UpdateEpisodeRequest
@@ -85,6 +167,63 @@ func (h *Handler) Update(c *gin.Context) {
 			response.NotFound(c, "Episode not found")
 			return
 		}
+		if err.Error() == "slug already exists" {
+			response.Error(c, http.StatusConflict, err.Error(), "slug_exists")
+			return
+		}
+		if err.Error() == "episode number already exists in this season" {
+			response.Error(c, http.StatusConflict, err.Error(), "episode_number_exists")
+			return
+		}
+		if err.Error() == "episode number belongs to an episode in the trash" {
+			response.Error(c, http.StatusConflict, err.Error(), "episode_in_trash")
+			return
+		}
+		if err.Error() == "invalid slug" {
+			response.BadRequest(c, "Invalid slug", "validation_error")
+			return
+		}
+		response.InternalError(c, "Failed to update episode", err.Error())
+		return
+	}
+	response.Success(c, "Episode updated successfully", episode)
+}
+
+func (h *Handler) Patch(c *gin.Context) {
+	id := c.Param("id")
+	var req PatchEpisodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	episode, err := h.service.Patch(c.Request.Context(), id, req)
+	if err != nil {
+		if err.Error() == "episode not found" {
+			response.NotFound(c, "Episode not found")
+			return
+		}
+		if err.Error() == "slug already exists" {
+			response.Error(c, http.StatusConflict, err.Error(), "slug_exists")
+			return
+		}
+		if err.Error() == "episode number already exists in this season" {
+			response.Error(c, http.StatusConflict, err.Error(), "episode_number_exists")
+			return
+		}
+		if err.Error() == "episode number belongs to an episode in the trash" {
+			response.Error(c, http.StatusConflict, err.Error(), "episode_in_trash")
+			return
+		}
+		if err.Error() == "invalid slug" {
+			response.BadRequest(c, "Invalid slug", "validation_error")
+			return
+		}
 		response.InternalError(c, "Failed to update episode", err.Error())
 		return
 	}
@@ -99,3 +238,110 @@ func (h *Handler) Delete(c *gin.Context) {
 	}
 	response.Success(c, "Episode deleted successfully", nil)
 }
+
+// Reorder renumbers the season's episodes in the order given; every episode must be listed
+func (h *Handler) Reorder(c *gin.Context) {
+	var req ReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	episodes, err := h.service.Reorder(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		if err.Error() == "episode not found" {
+			response.NotFound(c, "Episode not found")
+			return
+		}
+		if err.Error() == "duplicate episode" {
+			response.BadRequest(c, "Each episode may be listed once", "validation_error")
+			return
+		}
+		if err.Error() == "incomplete episode list" {
+			response.BadRequest(c, "Every episode of the season must be listed", "validation_error")
+			return
+		}
+		response.InternalError(c, "Failed to reorder episodes", err.Error())
+		return
+	}
+	response.Success(c, "Episodes reordered successfully", episodes)
+}
+
+// Move takes an episode to another season of the same drama, renumbering both seasons
+func (h *Handler) Move(c *gin.Context) {
+	var req MoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	episode, err := h.service.Move(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		if err.Error() == "episode not found" {
+			response.NotFound(c, "Episode not found")
+			return
+		}
+		if err.Error() == "season not found" {
+			response.NotFound(c, "Season not found")
+			return
+		}
+		if err.Error() == "episode is already in this season" || err.Error() == "season belongs to another drama" {
+			response.BadRequest(c, "Invalid target season", err.Error())
+			return
+		}
+		response.InternalError(c, "Failed to move episode", err.Error())
+		return
+	}
+	response.Success(c, "Episode moved successfully", episode)
+}
+
+func (h *Handler) Publish(c *gin.Context) {
+	h.setPublication(c, "published", nil)
+}
+
+func (h *Handler) Unpublish(c *gin.Context) {
+	h.setPublication(c, "unpublished", nil)
+}
+
+func (h *Handler) Schedule(c *gin.Context) {
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	h.setPublication(c, "scheduled", &req.PublishAt)
+}
+
+func (h *Handler) setPublication(c *gin.Context, publication string, publishAt *time.Time) {
+	id := c.Param("id")
+	episode, err := h.service.SetPublication(c.Request.Context(), id, publication, publishAt)
+	if err != nil {
+		if err.Error() == "episode not found" {
+			response.NotFound(c, "Episode not found")
+			return
+		}
+		if err.Error() == "publish_at must be in the future" {
+			response.BadRequest(c, "Invalid publish_at", err.Error())
+			return
+		}
+		response.InternalError(c, "Failed to update publication", err.Error())
+		return
+	}
+	response.Success(c, "Episode publication updated", episode)
+}
@@ -1,36 +1,126 @@
 package episode
 
-import "time"
+import (
+	"drakor-backend/internal/advisory"
+	"drakor-backend/pkg/patch"
+	"time"
+)
 
 type Episode struct {
-	ID            string    `json:"id"`
-	SeasonID      string    `json:"season_id"`
-	EpisodeNumber int       `json:"episode_number"`
-	Title         string    `json:"title"`
-	VideoURL      string    `json:"video_url"`
-	Duration      int       `json:"duration"` // in seconds
-	ThumbnailURL  string    `json:"thumbnail_url"`
-	ViewCount     int       `json:"view_count"`
-	SourceURL     string    `json:"source_url"`
-	AddedBy       string    `json:"added_by,omitempty"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID            string              `json:"id"`
+	SeasonID      string              `json:"season_id"`
+	EpisodeNumber int                 `json:"episode_number"`
+	Title         string              `json:"title"`
+	Slug          string              `json:"slug"`
+	VideoURL      string              `json:"video_url"`
+	Duration      int                 `json:"duration"` // in seconds
+	ThumbnailURL  string              `json:"thumbnail_url"`
+	ViewCount     int                 `json:"view_count"`
+	SourceURL     string              `json:"source_url"`
+	Publication   string              `json:"publication_status"` // 'draft', 'scheduled', 'published', 'unpublished'
+	PublishAt     *time.Time          `json:"publish_at,omitempty"`
+	AddedBy       string              `json:"added_by,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+	Advisories    []advisory.Advisory `json:"advisories,omitempty"` // Content warnings for this episode only
+
+	dramaPublished bool // Episodes are only public while their drama is published too
 }
 
 type CreateEpisodeRequest struct {
-	SeasonID      string `json:"season_id" validate:"required,uuid"`
+	SeasonID      string     `json:"season_id" validate:"required,uuid"`
+	EpisodeNumber int        `json:"episode_number" validate:"required,min=1"`
+	Title         string     `json:"title" validate:"required"`
+	Slug          string     `json:"slug" validate:"omitempty,min=2,max=300"` // Defaults to <drama-slug>-s<season>-e<episode>
+	VideoURL      string     `json:"video_url" validate:"required,url"`
+	Duration      int        `json:"duration" validate:"min=1"`
+	ThumbnailURL  string     `json:"thumbnail_url" validate:"omitempty,url"`
+	SourceURL     string     `json:"source_url" validate:"omitempty,url"`
+	Publication   string     `json:"publication_status" validate:"omitempty,oneof=draft scheduled published"` // Defaults to draft
+	PublishAt     *time.Time `json:"publish_at"`                                                              // Required when scheduled
+}
+
+type UpdateEpisodeRequest struct {
 	EpisodeNumber int    `json:"episode_number" validate:"required,min=1"`
 	Title         string `json:"title" validate:"required"`
+	Slug          string `json:"slug" validate:"omitempty,min=2,max=300"` // Defaults to <drama-slug>-s<season>-e<episode>
 	VideoURL      string `json:"video_url" validate:"required,url"`
 	Duration      int    `json:"duration" validate:"min=1"`
 	ThumbnailURL  string `json:"thumbnail_url" validate:"omitempty,url"`
 	SourceURL     string `json:"source_url" validate:"omitempty,url"`
 }
 
-type UpdateEpisodeRequest struct {
+// PatchEpisodeRequest follows JSON Merge Patch: absent members are left untouched,
+// null clears optional fields
+type PatchEpisodeRequest struct {
+	EpisodeNumber patch.Field[int]    `json:"episode_number" validate:"omitnil,min=1"`
+	Title         patch.Field[string] `json:"title" validate:"omitnil,required"`
+	Slug          patch.Field[string] `json:"slug" validate:"omitnil,min=2,max=300"`
+	VideoURL      patch.Field[string] `json:"video_url" validate:"omitnil,required,url"`
+	Duration      patch.Field[int]    `json:"duration" validate:"omitnil,min=1"`
+	ThumbnailURL  patch.Field[string] `json:"thumbnail_url" validate:"omitnil,omitempty,url"`
+	SourceURL     patch.Field[string] `json:"source_url" validate:"omitnil,omitempty,url"`
+}
+
+type ScheduleRequest struct {
+	PublishAt time.Time `json:"publish_at" validate:"required"`
+}
+
+// BulkEpisode is one episode of a bulk request; the season comes from the URL
+type BulkEpisode struct {
 	EpisodeNumber int    `json:"episode_number" validate:"required,min=1"`
 	Title         string `json:"title" validate:"required"`
+	Slug          string `json:"slug" validate:"omitempty,min=2,max=300"` // Defaults to <drama-slug>-s<season>-e<episode>
 	VideoURL      string `json:"video_url" validate:"required,url"`
 	Duration      int    `json:"duration" validate:"min=1"`
 	ThumbnailURL  string `json:"thumbnail_url" validate:"omitempty,url"`
 	SourceURL     string `json:"source_url" validate:"omitempty,url"`
 }
+
+// EpisodeTemplate generates Count numbered episodes. Title and URLs may use
+// {n} for the episode number, or {nn} / {nnn} for it zero-padded.
+type EpisodeTemplate struct {
+	Count        int    `json:"count" validate:"required,min=1,max=200"`
+	StartNumber  int    `json:"start_number" validate:"omitempty,min=1"` // Defaults to the number after the season's last episode
+	Title        string `json:"title" validate:"required"`               // e.g. "Episode {n}"
+	VideoURL     string `json:"video_url" validate:"required"`           // e.g. "https://cdn.example.com/show/s1/e{nn}.m3u8"
+	Duration     int    `json:"duration" validate:"min=1"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	SourceURL    string `json:"source_url"`
+}
+
+// BulkCreateRequest lists episodes one by one, or describes them with a template
+type BulkCreateRequest struct {
+	Episodes    []BulkEpisode    `json:"episodes" validate:"max=200"`
+	Template    *EpisodeTemplate `json:"template"`
+	Publication string           `json:"publication_status" validate:"omitempty,oneof=draft scheduled published"` // Applies to every episode; defaults to draft
+	PublishAt   *time.Time       `json:"publish_at"`                                                              // Required when scheduled
+}
+
+// ItemError points at the episode of a bulk request that failed; Index is its
+// position in the supplied or generated list
+type ItemError struct {
+	Index         int    `json:"index"`
+	EpisodeNumber int    `json:"episode_number,omitempty"`
+	Field         string `json:"field,omitempty"`
+	Message       string `json:"message"`
+}
+
+// BulkReport is returned for both dry runs and real requests; nothing is written unless Valid
+type BulkReport struct {
+	DryRun   bool        `json:"dry_run"`
+	Valid    bool        `json:"valid"`
+	Errors   []ItemError `json:"errors"`
+	Episodes []Episode   `json:"episodes"`
+}
+
+// ReorderRequest lists every episode of a season in its new order; they are
+// renumbered from 1 in that order
+type ReorderRequest struct {
+	EpisodeIDs []string `json:"episode_ids" validate:"required,min=1,dive,uuid"`
+}
+
+// MoveRequest takes an episode to another season of the same drama
+type MoveRequest struct {
+	SeasonID      string `json:"season_id" validate:"required,uuid"`
+	EpisodeNumber int    `json:"episode_number" validate:"omitempty,min=1"` // Position in the new season; defaults to the end
+}
@@ -2,19 +2,45 @@ package episode
 
 import (
 	"context"
+	"drakor-backend/internal/advisory"
+	"drakor-backend/internal/drama"
 	"drakor-backend/pkg/database"
+	"drakor-backend/pkg/patch"
 	"errors"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type Repository interface {
-	FindBySeasonID(ctx context.Context, seasonID string) ([]Episode, error)
+	FindBySeasonID(ctx context.Context, seasonID string, includeUnpublished bool) ([]Episode, error)
 	FindByID(ctx context.Context, id string) (*Episode, error)
+	FindBySlug(ctx context.Context, slug string) (*Episode, error)
+	SlugExists(ctx context.Context, slug, excludeID string) (bool, error)
+	SlugPrefix(ctx context.Context, seasonID string) (string, error)
 	Create(ctx context.Context, episode *Episode) error
+	// EpisodeNumbers maps every episode number used in the season, trashed episodes
+	// included, to whether that episode is in the trash
+	EpisodeNumbers(ctx context.Context, seasonID string) (map[int]bool, error)
+	// CreateMany inserts every episode in one transaction. Rows rejected by a unique
+	// constraint are reported per item and nothing is saved unless all succeed.
+	CreateMany(ctx context.Context, seasonID string, episodes []*Episode) ([]ItemError, error)
 	Update(ctx context.Context, episode *Episode) error
+	Patch(ctx context.Context, id string, fields map[string]interface{}) error
 	Delete(ctx context.Context, id string) error
+	SetPublication(ctx context.Context, id, publication string, publishAt *time.Time) error
+	// SeasonDramaID returns the drama a season belongs to; "season not found" when
+	// the season or its drama is in the trash
+	SeasonDramaID(ctx context.Context, seasonID string) (string, error)
+	// Reorder renumbers the season's episodes from 1 in the order of ids, which must
+	// list every episode not in the trash
+	Reorder(ctx context.Context, seasonID string, ids []string) error
+	// Move puts the episode at number in another season (at the end when number is 0
+	// or past it). Episodes from number on make room, and the gap left in the old
+	// season is closed.
+	Move(ctx context.Context, id, seasonID string, number int) error
+	PublishDue(ctx context.Context, now time.Time) (int64, error)
 }
 
 type repository struct{}
@@ -23,19 +49,25 @@ func NewRepository() Repository {
 	return &repository{}
 }
 
-func (r *repository) FindBySeasonID(ctx context.Context, seasonID string) ([]Episode, error) {
+// FindBySeasonID lists the episodes of a season; unless includeUnpublished is set
+// only published episodes of a published drama are returned
+func (r *repository) FindBySeasonID(ctx context.Context, seasonID string, includeUnpublished bool) ([]Episode, error) {
 	db := database.GetDB()
 	if db == nil {
 		return nil, errors.New("database not connected")
 	}
 
 	query := `
-		SELECT id, season_id, episode_number, title, video_url, duration, thumbnail_url, view_count, source_url, created_at 
-		FROM episodes 
-		WHERE season_id = $1 
-		ORDER BY episode_number ASC
+		SELECT e.id, e.season_id, e.episode_number, e.title, e.slug, e.video_url, e.duration, e.thumbnail_url, e.view_count, e.source_url,
+		       e.publication_status, e.publish_at, e.created_at
+		FROM episodes e
+		JOIN seasons s ON s.id = e.season_id
+		JOIN dramas d ON d.id = s.drama_id
+		WHERE e.season_id = $1 AND e.deleted_at IS NULL AND s.deleted_at IS NULL AND d.deleted_at IS NULL
+		  AND ($2 OR (e.publication_status = 'published' AND d.publication_status = 'published'))
+		ORDER BY e.episode_number ASC
 	`
-	rows, err := db.Query(ctx, query, seasonID)
+	rows, err := db.Query(ctx, query, seasonID, includeUnpublished)
 	if err != nil {
 		return nil, err
 	}
@@ -45,7 +77,7 @@ func (r *repository) FindBySeasonID(ctx context.Context, seasonID string) ([]Epi
 	for rows.Next() {
 		var e Episode
 		var thumbnail, source *string
-		if err := rows.Scan(&e.ID, &e.SeasonID, &e.EpisodeNumber, &e.Title, &e.VideoURL, &e.Duration, &thumbnail, &e.ViewCount, &source, &e.CreatedAt); err != nil {
+		if err := rows.Scan(&e.ID, &e.SeasonID, &e.EpisodeNumber, &e.Title, &e.Slug, &e.VideoURL, &e.Duration, &thumbnail, &e.ViewCount, &source, &e.Publication, &e.PublishAt, &e.CreatedAt); err != nil {
 			return nil, err
 		}
 		if thumbnail != nil {
@@ -67,14 +99,18 @@ func (r *repository) FindByID(ctx context.Context, id string) (*Episode, error)
 	}
 
 	query := `
-		SELECT id, season_id, episode_number, title, video_url, duration, thumbnail_url, view_count, source_url, added_by, created_at
-		FROM episodes WHERE id = $1
+		SELECT e.id, e.season_id, e.episode_number, e.title, e.slug, e.video_url, e.duration, e.thumbnail_url, e.view_count, e.source_url,
+		       e.publication_status, e.publish_at, d.publication_status = 'published', e.added_by, e.created_at
+		FROM episodes e
+		JOIN seasons s ON s.id = e.season_id
+		JOIN dramas d ON d.id = s.drama_id
+		WHERE e.id = $1 AND e.deleted_at IS NULL AND s.deleted_at IS NULL AND d.deleted_at IS NULL
 	`
 	var e Episode
 	var thumbnail, source, addedBy *string
 	err := db.QueryRow(ctx, query, id).Scan(
-		&e.ID, &e.SeasonID, &e.EpisodeNumber, &e.Title, &e.VideoURL, &e.Duration,
-		&thumbnail, &e.ViewCount, &source, &addedBy, &e.CreatedAt,
+		&e.ID, &e.SeasonID, &e.EpisodeNumber, &e.Title, &e.Slug, &e.VideoURL, &e.Duration,
+		&thumbnail, &e.ViewCount, &source, &e.Publication, &e.PublishAt, &e.dramaPublished, &addedBy, &e.CreatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -92,9 +128,96 @@ func (r *repository) FindByID(ctx context.Context, id string) (*Episode, error)
 		e.AddedBy = *addedBy
 	}
 
+	advisoryQuery := `
+		SELECT a.id, a.name, a.slug, a.description
+		FROM advisories a
+		JOIN episode_advisories ea ON a.id = ea.advisory_id
+		WHERE ea.episode_id = $1
+		ORDER BY a.display_order, a.name
+	`
+	rows, err := db.Query(ctx, advisoryQuery, e.ID)
+	if err == nil {
+		defer rows.Close()
+		for rows.Next() {
+			var a advisory.Advisory
+			var description *string
+			if err := rows.Scan(&a.ID, &a.Name, &a.Slug, &description); err == nil {
+				if description != nil {
+					a.Description = *description
+				}
+				e.Advisories = append(e.Advisories, a)
+			}
+		}
+	}
+
 	return &e, nil
 }
 
+func (r *repository) FindBySlug(ctx context.Context, slug string) (*Episode, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	// Current slug wins over a redirect left behind by a rename
+	query := `
+		SELECT id FROM (
+			SELECT id, 0 AS priority FROM episodes WHERE slug = $1
+			UNION ALL
+			SELECT entity_id, 1 FROM slug_redirects WHERE entity_type = 'episode' AND slug = $1
+		) s
+		ORDER BY priority
+		LIMIT 1
+	`
+	var id string
+	if err := db.QueryRow(ctx, query, slug).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.FindByID(ctx, id)
+}
+
+func (r *repository) SlugExists(ctx context.Context, slug, excludeID string) (bool, error) {
+	db := database.GetDB()
+	if db == nil {
+		return false, errors.New("database not connected")
+	}
+
+	query := `
+		SELECT EXISTS(SELECT 1 FROM episodes WHERE slug = $1 AND id::text <> $2)
+		    OR EXISTS(SELECT 1 FROM slug_redirects WHERE entity_type = 'episode' AND slug = $1 AND entity_id::text <> $2)
+	`
+	var exists bool
+	err := db.QueryRow(ctx, query, slug, excludeID).Scan(&exists)
+	return exists, err
+}
+
+// SlugPrefix returns "<drama-slug>-s<season-number>" for episodes of the given season
+func (r *repository) SlugPrefix(ctx context.Context, seasonID string) (string, error) {
+	db := database.GetDB()
+	if db == nil {
+		return "", errors.New("database not connected")
+	}
+
+	query := `
+		SELECT d.slug || '-s' || s.season_number
+		FROM seasons s
+		JOIN dramas d ON d.id = s.drama_id
+		WHERE s.id = $1 AND s.deleted_at IS NULL AND d.deleted_at IS NULL
+	`
+	var prefix string
+	err := db.QueryRow(ctx, query, seasonID).Scan(&prefix)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", errors.New("season not found")
+		}
+		return "", err
+	}
+	return prefix, nil
+}
+
 func (r *repository) Create(ctx context.Context, episode *Episode) error {
 	db := database.GetDB()
 	if db == nil {
@@ -102,14 +225,135 @@ func (r *repository) Create(ctx context.Context, episode *Episode) error {
 	}
 
 	query := `
-		INSERT INTO episodes (season_id, episode_number, title, video_url, duration, thumbnail_url, source_url, added_by, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO episodes (season_id, episode_number, title, slug, video_url, duration, thumbnail_url, source_url,
+		                      publication_status, publish_at, added_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		RETURNING id
 	`
-	return db.QueryRow(ctx, query,
-		episode.SeasonID, episode.EpisodeNumber, episode.Title, episode.VideoURL,
-		episode.Duration, episode.ThumbnailURL, episode.SourceURL, episode.AddedBy, time.Now(),
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, query,
+		episode.SeasonID, episode.EpisodeNumber, episode.Title, episode.Slug, episode.VideoURL,
+		episode.Duration, episode.ThumbnailURL, episode.SourceURL, episode.Publication, episode.PublishAt,
+		episode.AddedBy, time.Now(),
 	).Scan(&episode.ID)
+	if err != nil {
+		return err
+	}
+
+	var dramaID string
+	if err := tx.QueryRow(ctx, "SELECT drama_id FROM seasons WHERE id = $1", episode.SeasonID).Scan(&dramaID); err != nil {
+		return err
+	}
+	if err := drama.SyncCounts(ctx, tx, dramaID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *repository) EpisodeNumbers(ctx context.Context, seasonID string) (map[int]bool, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	rows, err := db.Query(ctx, "SELECT episode_number, deleted_at IS NOT NULL FROM episodes WHERE season_id = $1", seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	numbers := map[int]bool{}
+	for rows.Next() {
+		var number int
+		var trashed bool
+		if err := rows.Scan(&number, &trashed); err != nil {
+			return nil, err
+		}
+		numbers[number] = trashed
+	}
+	return numbers, rows.Err()
+}
+
+// uniqueViolation is the SQLSTATE Postgres raises when a unique constraint is broken
+const uniqueViolation = "23505"
+
+// uniqueViolations maps the unique constraints on episodes to the request field they guard
+var uniqueViolations = map[string]ItemError{
+	"episodes_season_id_episode_number_key": {Field: "episode_number", Message: "episode_number already exists in this season"},
+	"idx_episodes_slug":                     {Field: "slug", Message: "slug already exists"},
+}
+
+func (r *repository) CreateMany(ctx context.Context, seasonID string, episodes []*Episode) ([]ItemError, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	query := `
+		INSERT INTO episodes (season_id, episode_number, title, slug, video_url, duration, thumbnail_url, source_url,
+		                      publication_status, publish_at, added_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id
+	`
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Each insert runs in a savepoint so one conflict does not hide the rest
+	itemErrors := []ItemError{}
+	now := time.Now()
+	for i, episode := range episodes {
+		sp, err := tx.Begin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		err = sp.QueryRow(ctx, query,
+			seasonID, episode.EpisodeNumber, episode.Title, episode.Slug, episode.VideoURL,
+			episode.Duration, episode.ThumbnailURL, episode.SourceURL, episode.Publication, episode.PublishAt,
+			episode.AddedBy, now,
+		).Scan(&episode.ID)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if !errors.As(err, &pgErr) || pgErr.Code != uniqueViolation {
+				return nil, err
+			}
+			itemErr, ok := uniqueViolations[pgErr.ConstraintName]
+			if !ok {
+				itemErr = ItemError{Message: pgErr.Message}
+			}
+			itemErr.Index = i
+			itemErr.EpisodeNumber = episode.EpisodeNumber
+			itemErrors = append(itemErrors, itemErr)
+			if err := sp.Rollback(ctx); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := sp.Commit(ctx); err != nil {
+			return nil, err
+		}
+		episode.SeasonID = seasonID
+		episode.CreatedAt = now
+	}
+	if len(itemErrors) > 0 {
+		return itemErrors, nil
+	}
+
+	var dramaID string
+	if err := tx.QueryRow(ctx, "SELECT drama_id FROM seasons WHERE id = $1", seasonID).Scan(&dramaID); err != nil {
+		return nil, err
+	}
+	if err := drama.SyncCounts(ctx, tx, dramaID); err != nil {
+		return nil, err
+	}
+	return nil, tx.Commit(ctx)
 }
 
 func (r *repository) Update(ctx context.Context, episode *Episode) error {
@@ -118,16 +362,108 @@ func (r *repository) Update(ctx context.Context, episode *Episode) error {
 		return errors.New("database not connected")
 	}
 
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Keep the old slug reachable if it is being renamed
+	var oldSlug string
+	if err := tx.QueryRow(ctx, "SELECT slug FROM episodes WHERE id = $1", episode.ID).Scan(&oldSlug); err != nil {
+		return err
+	}
+	if err := database.RedirectSlug(ctx, tx, "episode", oldSlug, episode.Slug, episode.ID); err != nil {
+		return err
+	}
+	auto, err := renumberedSlug(ctx, tx, episode.ID, oldSlug != episode.Slug)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE episodes 
-		SET episode_number=$1, title=$2, video_url=$3, duration=$4, thumbnail_url=$5, source_url=$6
-		WHERE id=$7
+		SET episode_number=$1, title=$2, slug=$3, video_url=$4, duration=$5, thumbnail_url=$6, source_url=$7, updated_at=$8
+		WHERE id=$9
 	`
-	_, err := db.Exec(ctx, query,
-		episode.EpisodeNumber, episode.Title, episode.VideoURL,
-		episode.Duration, episode.ThumbnailURL, episode.SourceURL, episode.ID,
+	_, err = tx.Exec(ctx, query,
+		episode.EpisodeNumber, episode.Title, episode.Slug, episode.VideoURL,
+		episode.Duration, episode.ThumbnailURL, episode.SourceURL, time.Now(), episode.ID,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	if err := RenumberSlugs(ctx, tx, auto); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// renumberedSlug returns the episode's generated slug, as AutoSlugs does, so that
+// RenumberSlugs can follow a change of its number. A slug set by the same write wins.
+func renumberedSlug(ctx context.Context, tx pgx.Tx, id string, slugSet bool) (map[string]string, error) {
+	if slugSet {
+		return nil, nil
+	}
+	var seasonID string
+	if err := tx.QueryRow(ctx, "SELECT season_id::text FROM episodes WHERE id = $1", id).Scan(&seasonID); err != nil {
+		return nil, err
+	}
+	auto, err := AutoSlugs(ctx, tx, []string{seasonID})
+	if err != nil {
+		return nil, err
+	}
+	if slug, ok := auto[id]; ok {
+		return map[string]string{id: slug}, nil
+	}
+	return nil, nil
+}
+
+func (r *repository) Patch(ctx context.Context, id string, fields map[string]interface{}) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Keep the old slug reachable if it is being renamed
+	if newSlug, ok := fields["slug"].(string); ok {
+		var oldSlug string
+		if err := tx.QueryRow(ctx, "SELECT slug FROM episodes WHERE id = $1", id).Scan(&oldSlug); err != nil {
+			return err
+		}
+		if err := database.RedirectSlug(ctx, tx, "episode", oldSlug, newSlug, id); err != nil {
+			return err
+		}
+	}
+
+	var auto map[string]string
+	if _, ok := fields["episode_number"]; ok {
+		_, slugSet := fields["slug"]
+		if auto, err = renumberedSlug(ctx, tx, id, slugSet); err != nil {
+			return err
+		}
+	}
+
+	fields["updated_at"] = time.Now()
+	columns := []string{"episode_number", "title", "slug", "video_url", "duration", "thumbnail_url", "source_url", "updated_at"}
+	if err := patch.Update(ctx, tx, "episodes", columns, fields, id); err != nil {
+		return err
+	}
+	if err := RenumberSlugs(ctx, tx, auto); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 func (r *repository) Delete(ctx context.Context, id string) error {
@@ -135,6 +471,208 @@ func (r *repository) Delete(ctx context.Context, id string) error {
 	if db == nil {
 		return errors.New("database not connected")
 	}
-	_, err := db.Exec(ctx, "DELETE FROM episodes WHERE id = $1", id)
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var dramaID string
+	query := `
+		UPDATE episodes e SET deleted_at = $1
+		FROM seasons s
+		WHERE e.id = $2 AND e.deleted_at IS NULL AND s.id = e.season_id
+		RETURNING s.drama_id
+	`
+	if err := tx.QueryRow(ctx, query, time.Now(), id).Scan(&dramaID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	if err := drama.SyncCounts(ctx, tx, dramaID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *repository) SetPublication(ctx context.Context, id, publication string, publishAt *time.Time) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	query := `UPDATE episodes SET publication_status = $1, publish_at = $2, updated_at = $3 WHERE id = $4`
+	_, err := db.Exec(ctx, query, publication, publishAt, time.Now(), id)
 	return err
 }
+
+// PublishDue flips scheduled episodes whose publish time has passed to published
+func (r *repository) PublishDue(ctx context.Context, now time.Time) (int64, error) {
+	db := database.GetDB()
+	if db == nil {
+		return 0, errors.New("database not connected")
+	}
+
+	query := `
+		UPDATE episodes SET publication_status = 'published', updated_at = $1
+		WHERE publication_status = 'scheduled' AND publish_at <= $1 AND deleted_at IS NULL
+	`
+	tag, err := db.Exec(ctx, query, now)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *repository) SeasonDramaID(ctx context.Context, seasonID string) (string, error) {
+	db := database.GetDB()
+	if db == nil {
+		return "", errors.New("database not connected")
+	}
+
+	var dramaID string
+	query := `
+		SELECT s.drama_id
+		FROM seasons s
+		JOIN dramas d ON d.id = s.drama_id
+		WHERE s.id = $1 AND s.deleted_at IS NULL AND d.deleted_at IS NULL
+	`
+	err := db.QueryRow(ctx, query, seasonID).Scan(&dramaID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", errors.New("season not found")
+		}
+		return "", err
+	}
+	return dramaID, nil
+}
+
+func (r *repository) Reorder(ctx context.Context, seasonID string, ids []string) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Numbers may collide halfway through; uniqueness is checked at commit
+	if _, err := tx.Exec(ctx, "SET CONSTRAINTS episodes_season_id_episode_number_key DEFERRED"); err != nil {
+		return err
+	}
+
+	var listed, live int
+	err = tx.QueryRow(ctx, `
+		SELECT COUNT(*) FILTER (WHERE id = ANY($2::uuid[])), COUNT(*)
+		FROM episodes
+		WHERE season_id = $1 AND deleted_at IS NULL
+	`, seasonID, ids).Scan(&listed, &live)
+	if err != nil {
+		return err
+	}
+	if listed != len(ids) {
+		return errors.New("episode not found")
+	}
+	if live != len(ids) {
+		return errors.New("incomplete episode list")
+	}
+	auto, err := AutoSlugs(ctx, tx, []string{seasonID})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(ctx, `
+		UPDATE episodes e SET episode_number = o.n, updated_at = $3
+		FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, n)
+		WHERE e.id = o.id AND e.season_id = $1 AND e.episode_number <> o.n
+	`, seasonID, ids, now)
+	if err != nil {
+		return err
+	}
+
+	// Trashed episodes still hold their numbers, so they move after the live ones
+	_, err = tx.Exec(ctx, `
+		UPDATE episodes e SET episode_number = cardinality($2::uuid[]) + o.n, updated_at = $3
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY episode_number) AS n
+			FROM episodes
+			WHERE season_id = $1 AND deleted_at IS NOT NULL
+		) o
+		WHERE e.id = o.id AND e.episode_number <> cardinality($2::uuid[]) + o.n
+	`, seasonID, ids, now)
+	if err != nil {
+		return err
+	}
+
+	// Generated slugs follow the new numbers
+	if err := RenumberSlugs(ctx, tx, auto); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *repository) Move(ctx context.Context, id, seasonID string, number int) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SET CONSTRAINTS episodes_season_id_episode_number_key DEFERRED"); err != nil {
+		return err
+	}
+
+	var oldSeasonID string
+	var oldNumber int
+	err = tx.QueryRow(ctx, "SELECT season_id, episode_number FROM episodes WHERE id = $1 FOR UPDATE", id).Scan(&oldSeasonID, &oldNumber)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errors.New("episode not found")
+		}
+		return err
+	}
+
+	var last int
+	if err := tx.QueryRow(ctx, "SELECT COALESCE(MAX(episode_number), 0) FROM episodes WHERE season_id = $1", seasonID).Scan(&last); err != nil {
+		return err
+	}
+	if number == 0 || number > last+1 {
+		number = last + 1
+	}
+	auto, err := AutoSlugs(ctx, tx, []string{oldSeasonID, seasonID})
+	if err != nil {
+		return err
+	}
+
+	// Make room in the new season, move the episode, then close the gap it left
+	now := time.Now()
+	_, err = tx.Exec(ctx, "UPDATE episodes SET episode_number = episode_number + 1, updated_at = $1 WHERE season_id = $2 AND episode_number >= $3", now, seasonID, number)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, "UPDATE episodes SET season_id = $1, episode_number = $2, updated_at = $3 WHERE id = $4", seasonID, number, now, id)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, "UPDATE episodes SET episode_number = episode_number - 1, updated_at = $1 WHERE season_id = $2 AND episode_number > $3", now, oldSeasonID, oldNumber)
+	if err != nil {
+		return err
+	}
+	if err := RenumberSlugs(ctx, tx, auto); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -2,15 +2,32 @@ package episode
 
 import (
 	"context"
+	"drakor-backend/pkg/publish"
+	"drakor-backend/pkg/validator"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Service interface {
-	GetBySeasonID(ctx context.Context, seasonID string) ([]Episode, error)
-	GetByID(ctx context.Context, id string) (*Episode, error)
+	GetBySeasonID(ctx context.Context, seasonID string, preview bool) ([]Episode, error)
+	GetByID(ctx context.Context, id string, preview bool) (*Episode, error)
 	Create(ctx context.Context, userID string, req CreateEpisodeRequest) (*Episode, error)
+	// BulkCreate adds many episodes to a season in one transaction; with dryRun
+	// the episodes are checked and returned but not saved
+	BulkCreate(ctx context.Context, userID, seasonID string, req BulkCreateRequest, dryRun bool) (*BulkReport, error)
 	Update(ctx context.Context, id string, req UpdateEpisodeRequest) (*Episode, error)
+	Patch(ctx context.Context, id string, req PatchEpisodeRequest) (*Episode, error)
 	Delete(ctx context.Context, id string) error
+	// Reorder renumbers every episode of a season and returns them in their new order
+	Reorder(ctx context.Context, seasonID string, req ReorderRequest) ([]Episode, error)
+	// Move takes an episode to another season of the same drama
+	Move(ctx context.Context, id string, req MoveRequest) (*Episode, error)
+	// Publication
+	SetPublication(ctx context.Context, id, publication string, publishAt *time.Time) (*Episode, error)
+	StartScheduler(ctx context.Context, interval time.Duration)
 }
 
 type service struct {
@@ -21,23 +38,62 @@ func NewService(repo Repository) Service {
 	return &service{repo: repo}
 }
 
-func (s *service) GetBySeasonID(ctx context.Context, seasonID string) ([]Episode, error) {
-	return s.repo.FindBySeasonID(ctx, seasonID)
+func (s *service) GetBySeasonID(ctx context.Context, seasonID string, preview bool) ([]Episode, error) {
+	return s.repo.FindBySeasonID(ctx, seasonID, preview)
 }
 
-func (s *service) GetByID(ctx context.Context, id string) (*Episode, error) {
-	return s.repo.FindByID(ctx, id)
+// GetByID accepts either the episode UUID or its (current or former) slug.
+// Episodes that are not public are only returned when preview is set.
+func (s *service) GetByID(ctx context.Context, id string, preview bool) (*Episode, error) {
+	var episode *Episode
+	var err error
+	if validator.IsUUID(id) {
+		episode, err = s.repo.FindByID(ctx, id)
+	} else {
+		episode, err = s.repo.FindBySlug(ctx, id)
+	}
+	if err != nil || episode == nil {
+		return nil, err
+	}
+	if !preview && (episode.Publication != "published" || !episode.dramaPublished) {
+		return nil, nil
+	}
+	return episode, nil
 }
 
 func (s *service) Create(ctx context.Context, userID string, req CreateEpisodeRequest) (*Episode, error) {
+	if _, err := s.repo.SeasonDramaID(ctx, req.SeasonID); err != nil {
+		return nil, err
+	}
+	if err := s.checkNumber(ctx, req.SeasonID, req.EpisodeNumber); err != nil {
+		return nil, err
+	}
+
+	slug, err := s.resolveSlug(ctx, req.Slug, req.SeasonID, req.EpisodeNumber, "")
+	if err != nil {
+		return nil, err
+	}
+
+	publication := req.Publication
+	if publication == "" {
+		publication = "draft"
+	}
+	publishAt, err := publish.ResolveAt(publication, req.PublishAt)
+	if err != nil {
+		return nil, err
+	}
+
 	episode := &Episode{
 		SeasonID:      req.SeasonID,
 		EpisodeNumber: req.EpisodeNumber,
 		Title:         req.Title,
+		Slug:          slug,
 		VideoURL:      req.VideoURL,
 		Duration:      req.Duration,
 		ThumbnailURL:  req.ThumbnailURL,
 		SourceURL:     req.SourceURL,
+		Publication:   publication,
+		PublishAt:     publishAt,
 		AddedBy:       userID,
 	}
 
@@ -48,6 +104,164 @@ func (s *service) Create(ctx context.Context, userID string, req CreateEpisodeRe
 	return episode, nil
 }
 
+func (s *service) BulkCreate(ctx context.Context, userID, seasonID string, req BulkCreateRequest, dryRun bool) (*BulkReport, error) {
+	if req.Template != nil && len(req.Episodes) > 0 {
+		return nil, errors.New("episodes and template are mutually exclusive")
+	}
+	if req.Template == nil && len(req.Episodes) == 0 {
+		return nil, errors.New("no episodes to create")
+	}
+
+	publication := req.Publication
+	if publication == "" {
+		publication = "draft"
+	}
+	publishAt, err := publish.ResolveAt(publication, req.PublishAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nothing may be added below a season or drama in the trash
+	if _, err := s.repo.SeasonDramaID(ctx, seasonID); err != nil {
+		return nil, err
+	}
+	prefix, err := s.repo.SlugPrefix(ctx, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	numbers, err := s.repo.EpisodeNumbers(ctx, seasonID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := req.Episodes
+	if req.Template != nil {
+		items = expandTemplate(*req.Template, numbers)
+	}
+
+	report := &BulkReport{DryRun: dryRun, Errors: []ItemError{}, Episodes: []Episode{}}
+	fail := func(i int, item BulkEpisode, field, message string) {
+		report.Errors = append(report.Errors, ItemError{Index: i, EpisodeNumber: item.EpisodeNumber, Field: field, Message: message})
+	}
+
+	// Slugs picked earlier in the batch count as taken
+	batchSlugs := map[string]bool{}
+	taken := func(slug string) (bool, error) {
+		if batchSlugs[slug] {
+			return true, nil
+		}
+		return s.repo.SlugExists(ctx, slug, "")
+	}
+
+	batchNumbers := map[int]bool{}
+	episodes := []*Episode{}
+	for i, item := range items {
+		if errs := validator.ValidateStruct(item); len(errs) > 0 {
+			for _, e := range errs {
+				fail(i, item, e.Field, e.Message)
+			}
+			continue
+		}
+		if batchNumbers[item.EpisodeNumber] {
+			fail(i, item, "episode_number", "episode_number is repeated")
+			continue
+		}
+		batchNumbers[item.EpisodeNumber] = true
+		if trashed, exists := numbers[item.EpisodeNumber]; exists {
+			if trashed {
+				fail(i, item, "episode_number", "episode_number belongs to an episode in the trash")
+			} else {
+				fail(i, item, "episode_number", "episode_number already exists in this season")
+			}
+			continue
+		}
+
+		slug, err := pickSlug(item.Slug, prefix, item.EpisodeNumber, taken)
+		if err != nil {
+			if err.Error() == "invalid slug" || err.Error() == "slug already exists" {
+				fail(i, item, "slug", err.Error())
+				continue
+			}
+			return nil, err
+		}
+		batchSlugs[slug] = true
+
+		episodes = append(episodes, &Episode{
+			SeasonID:      seasonID,
+			EpisodeNumber: item.EpisodeNumber,
+			Title:         item.Title,
+			Slug:          slug,
+			VideoURL:      item.VideoURL,
+			Duration:      item.Duration,
+			ThumbnailURL:  item.ThumbnailURL,
+			SourceURL:     item.SourceURL,
+			Publication:   publication,
+			PublishAt:     publishAt,
+			AddedBy:       userID,
+		})
+	}
+
+	if len(report.Errors) == 0 && !dryRun {
+		itemErrors, err := s.repo.CreateMany(ctx, seasonID, episodes)
+		if err != nil {
+			return nil, err
+		}
+		report.Errors = append(report.Errors, itemErrors...)
+	}
+
+	report.Valid = len(report.Errors) == 0
+	if report.Valid {
+		for _, e := range episodes {
+			report.Episodes = append(report.Episodes, *e)
+		}
+	}
+	return report, nil
+}
+
+// expandTemplate generates the numbered episodes a template describes. Without a
+// start number they follow the highest number used in the season.
+func expandTemplate(t EpisodeTemplate, used map[int]bool) []BulkEpisode {
+	start := t.StartNumber
+	if start == 0 {
+		start = 1
+		for number := range used {
+			if number >= start {
+				start = number + 1
+			}
+		}
+	}
+
+	items := make([]BulkEpisode, 0, t.Count)
+	for n := start; n < start+t.Count; n++ {
+		r := strings.NewReplacer("{nnn}", fmt.Sprintf("%03d", n), "{nn}", fmt.Sprintf("%02d", n), "{n}", strconv.Itoa(n))
+		items = append(items, BulkEpisode{
+			EpisodeNumber: n,
+			Title:         r.Replace(t.Title),
+			VideoURL:      r.Replace(t.VideoURL),
+			Duration:      t.Duration,
+			ThumbnailURL:  r.Replace(t.ThumbnailURL),
+			SourceURL:     r.Replace(t.SourceURL),
+		})
+	}
+	return items
+}
+
+// checkNumber rejects an episode number already used in the season. Trashed
+// episodes still hold their number, so the error points at the trash then.
+func (s *service) checkNumber(ctx context.Context, seasonID string, number int) error {
+	numbers, err := s.repo.EpisodeNumbers(ctx, seasonID)
+	if err != nil {
+		return err
+	}
+	if trashed, exists := numbers[number]; exists {
+		if trashed {
+			return errors.New("episode number belongs to an episode in the trash")
+		}
+		return errors.New("episode number already exists in this season")
+	}
+	return nil
+}
+
 func (s *service) Update(ctx context.Context, id string, req UpdateEpisodeRequest) (*Episode, error) {
 	episode, err := s.repo.FindByID(ctx, id)
 	if err != nil {
@@ -57,6 +271,20 @@ func (s *service) Update(ctx context.Context, id string, req UpdateEpisodeReques
 		return nil, errors.New("episode not found")
 	}
 
+	if req.EpisodeNumber != episode.EpisodeNumber {
+		if err := s.checkNumber(ctx, episode.SeasonID, req.EpisodeNumber); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Slug != "" {
+		slug, err := s.resolveSlug(ctx, req.Slug, episode.SeasonID, req.EpisodeNumber, episode.ID)
+		if err != nil {
+			return nil, err
+		}
+		episode.Slug = slug
+	}
+
 	episode.EpisodeNumber = req.EpisodeNumber
 	episode.Title = req.Title
 	episode.VideoURL = req.VideoURL
@@ -68,9 +296,159 @@ func (s *service) Update(ctx context.Context, id string, req UpdateEpisodeReques
 		return nil, err
 	}
 
-	return episode, nil
+	// A generated slug follows a new episode number
+	return s.repo.FindByID(ctx, episode.ID)
+}
+
+func (s *service) Patch(ctx context.Context, id string, req PatchEpisodeRequest) (*Episode, error) {
+	episode, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if episode == nil {
+		return nil, errors.New("episode not found")
+	}
+
+	fields := map[string]interface{}{}
+	number := episode.EpisodeNumber
+	if req.EpisodeNumber.Set {
+		number = req.EpisodeNumber.Value
+		if req.EpisodeNumber.Value != episode.EpisodeNumber {
+			if err := s.checkNumber(ctx, episode.SeasonID, req.EpisodeNumber.Value); err != nil {
+				return nil, err
+			}
+		}
+		fields["episode_number"] = req.EpisodeNumber.Value
+	}
+	if req.Title.Set {
+		fields["title"] = req.Title.Value
+	}
+	if req.Slug.Set {
+		slug, err := s.resolveSlug(ctx, req.Slug.Value, episode.SeasonID, number, episode.ID)
+		if err != nil {
+			return nil, err
+		}
+		fields["slug"] = slug
+	}
+	if req.VideoURL.Set {
+		fields["video_url"] = req.VideoURL.Value
+	}
+	if req.Duration.Set {
+		fields["duration"] = req.Duration.Value
+	}
+	if req.ThumbnailURL.Set {
+		fields["thumbnail_url"] = req.ThumbnailURL.Column()
+	}
+	if req.SourceURL.Set {
+		fields["source_url"] = req.SourceURL.Column()
+	}
+
+	if err := s.repo.Patch(ctx, episode.ID, fields); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(ctx, episode.ID)
 }
 
 func (s *service) Delete(ctx context.Context, id string) error {
 	return s.repo.Delete(ctx, id)
 }
+
+// Reorder renumbers the season's episodes from 1 in the order given
+func (s *service) Reorder(ctx context.Context, seasonID string, req ReorderRequest) ([]Episode, error) {
+	seen := map[string]bool{}
+	for _, id := range req.EpisodeIDs {
+		if seen[id] {
+			return nil, errors.New("duplicate episode")
+		}
+		seen[id] = true
+	}
+
+	if err := s.repo.Reorder(ctx, seasonID, req.EpisodeIDs); err != nil {
+		return nil, err
+	}
+	return s.repo.FindBySeasonID(ctx, seasonID, true)
+}
+
+// Move takes an episode to another season of the same drama under a new number
+func (s *service) Move(ctx context.Context, id string, req MoveRequest) (*Episode, error) {
+	episode, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if episode == nil {
+		return nil, errors.New("episode not found")
+	}
+	if episode.SeasonID == req.SeasonID {
+		return nil, errors.New("episode is already in this season")
+	}
+
+	targetDrama, err := s.repo.SeasonDramaID(ctx, req.SeasonID)
+	if err != nil {
+		return nil, err
+	}
+	currentDrama, err := s.repo.SeasonDramaID(ctx, episode.SeasonID)
+	if err != nil {
+		return nil, err
+	}
+	if targetDrama != currentDrama {
+		return nil, errors.New("season belongs to another drama")
+	}
+
+	if err := s.repo.Move(ctx, episode.ID, req.SeasonID, req.EpisodeNumber); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, episode.ID)
+}
+
+// SetPublication moves an episode to draft, scheduled, published or unpublished
+func (s *service) SetPublication(ctx context.Context, id, publication string, publishAt *time.Time) (*Episode, error) {
+	episode, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if episode == nil {
+		return nil, errors.New("episode not found")
+	}
+
+	// Keep the original release time when re-publishing
+	if publication == "published" && publishAt == nil {
+		publishAt = episode.PublishAt
+	}
+	publishAt, err = publish.ResolveAt(publication, publishAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetPublication(ctx, episode.ID, publication, publishAt); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, episode.ID)
+}
+
+// StartScheduler publishes scheduled episodes every interval until ctx is cancelled
+func (s *service) StartScheduler(ctx context.Context, interval time.Duration) {
+	publish.StartScheduler(ctx, interval, "episodes", s.repo.PublishDue)
+}
+
+// resolveSlug validates a requested slug or generates one from the drama slug,
+// season and episode number
+func (s *service) resolveSlug(ctx context.Context, requested, seasonID string, episodeNumber int, excludeID string) (string, error) {
+	taken := func(slug string) (bool, error) {
+		return s.repo.SlugExists(ctx, slug, excludeID)
+	}
+
+	prefix := ""
+	if requested == "" {
+		var err error
+		if prefix, err = s.repo.SlugPrefix(ctx, seasonID); err != nil {
+			return "", err
+		}
+	}
+	return pickSlug(requested, prefix, episodeNumber, taken)
+}
+
+// pickSlug validates a requested slug, or numbers one after the season's slug prefix
+func pickSlug(requested, prefix string, episodeNumber int, taken func(string) (bool, error)) (string, error) {
+	return validator.ResolveSlug(requested, prefix+"-e"+strconv.Itoa(episodeNumber), "", nil, taken)
+}
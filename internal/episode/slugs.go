@@ -0,0 +1,162 @@
+package episode
+
+import (
+	"context"
+	"drakor-backend/pkg/validator"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AutoSlugs finds the episodes of the given seasons whose slug is still the
+// generated "<drama>-s<N>-e<M>" (optionally numbered, e.g. "-2") for their
+// current numbers, mapped to that slug. Renumbering code reads them before it
+// changes any number and passes them to RenumberSlugs afterwards.
+func AutoSlugs(ctx context.Context, tx pgx.Tx, seasonIDs []string) (map[string]string, error) {
+	// Slugs only hold [a-z0-9-], so they are safe to use inside the pattern
+	rows, err := tx.Query(ctx, `
+		SELECT e.id, e.slug
+		FROM episodes e
+		JOIN seasons s ON s.id = e.season_id
+		JOIN dramas d ON d.id = s.drama_id
+		WHERE e.season_id = ANY($1::uuid[])
+		  AND e.slug ~ ('^' || d.slug || '-s' || s.season_number || '-e' || e.episode_number || '(-[0-9]+)?$')
+	`, seasonIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	slugs := map[string]string{}
+	for rows.Next() {
+		var id, slug string
+		if err := rows.Scan(&id, &slug); err != nil {
+			return nil, err
+		}
+		slugs[id] = slug
+	}
+	return slugs, rows.Err()
+}
+
+// RenumberSlugs regenerates the auto slugs found by AutoSlugs for the episodes'
+// new season and episode numbers. Old slugs keep redirecting to their episode
+// unless another episode of the batch takes them over.
+func RenumberSlugs(ctx context.Context, tx pgx.Tx, auto map[string]string) error {
+	if len(auto) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(auto))
+	for id := range auto {
+		ids = append(ids, id)
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT e.id, d.slug || '-s' || s.season_number || '-e' || e.episode_number
+		FROM episodes e
+		JOIN seasons s ON s.id = e.season_id
+		JOIN dramas d ON d.id = s.drama_id
+		WHERE e.id = ANY($1::uuid[])
+		ORDER BY s.season_number, e.episode_number
+	`, ids)
+	if err != nil {
+		return err
+	}
+	type rename struct{ id, base, slug string }
+	var renames []rename
+	for rows.Next() {
+		var r rename
+		if err := rows.Scan(&r.id, &r.base); err != nil {
+			rows.Close()
+			return err
+		}
+		// Episodes whose numbers did not change keep their slug
+		if isAutoSlug(auto[r.id], r.base) {
+			continue
+		}
+		renames = append(renames, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(renames) == 0 {
+		return nil
+	}
+
+	changed := make([]string, len(renames))
+	for i, r := range renames {
+		changed[i] = r.id
+	}
+
+	// The slug index is not deferrable: park the batch on its ids (never a valid
+	// slug) so the episodes can swap slugs among themselves
+	if _, err := tx.Exec(ctx, "UPDATE episodes SET slug = id::text WHERE id = ANY($1::uuid[])", changed); err != nil {
+		return err
+	}
+
+	picked := map[string]bool{}
+	taken := func(slug string) (bool, error) {
+		if picked[slug] {
+			return true, nil
+		}
+		var exists bool
+		err := tx.QueryRow(ctx, `
+			SELECT EXISTS(SELECT 1 FROM episodes WHERE slug = $1)
+			    OR EXISTS(SELECT 1 FROM slug_redirects WHERE entity_type = 'episode' AND slug = $1 AND NOT (entity_id = ANY($2::uuid[])))
+		`, slug, changed).Scan(&exists)
+		return exists, err
+	}
+	for i := range renames {
+		slug, err := validator.UniqueSlug(renames[i].base, nil, taken)
+		if err != nil {
+			return err
+		}
+		picked[slug] = true
+		renames[i].slug = slug
+	}
+
+	now := time.Now()
+	newSlugs := make([]string, len(renames))
+	for i, r := range renames {
+		newSlugs[i] = r.slug
+		if _, err := tx.Exec(ctx, "UPDATE episodes SET slug = $1, updated_at = $2 WHERE id = $3", r.slug, now, r.id); err != nil {
+			return err
+		}
+	}
+	for _, r := range renames {
+		if old := auto[r.id]; !picked[old] {
+			_, err := tx.Exec(ctx, `
+				INSERT INTO slug_redirects (entity_type, slug, entity_id, created_at)
+				VALUES ('episode', $1, $2, $3)
+				ON CONFLICT (entity_type, slug) DO UPDATE SET entity_id = EXCLUDED.entity_id, created_at = EXCLUDED.created_at
+			`, old, r.id, now)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	_, err = tx.Exec(ctx, "DELETE FROM slug_redirects WHERE entity_type = 'episode' AND slug = ANY($1)", newSlugs)
+	return err
+}
+
+// isAutoSlug reports whether slug is base, or base numbered by UniqueSlug
+func isAutoSlug(slug, base string) bool {
+	rest, ok := strings.CutPrefix(slug, base)
+	if !ok {
+		return false
+	}
+	if rest == "" {
+		return true
+	}
+	digits, ok := strings.CutPrefix(rest, "-")
+	if !ok || digits == "" {
+		return false
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
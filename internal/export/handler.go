@@ -0,0 +1,191 @@
+package export
+
+import (
+	"drakor-backend/pkg/response"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// csvHeader uses the import CSV columns, followed by the drama id, its latest change
+// time and, for a trashed drama, when it was trashed
+var csvHeader = []string{
+	"drama_slug", "drama_title", "year", "status", "synopsis", "poster_url", "source_url", "publication_status",
+	"country", "original_language", "genres", "actors", "season_number", "season_title",
+	"episode_number", "episode_title", "video_url", "duration", "thumbnail_url", "episode_source_url",
+	"drama_id", "updated_at", "deleted_at",
+}
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Export streams the catalog as JSON Lines (default) or CSV (?format=csv).
+// updated_since / updated_until (RFC 3339) select an incremental delta, which
+// also carries a tombstone (id, slug, deleted_at) for every drama trashed in it.
+func (h *Handler) Export(c *gin.Context) {
+	format := c.DefaultQuery("format", "jsonl")
+	if format != "jsonl" && format != "csv" {
+		response.BadRequest(c, "Format must be jsonl or csv", "invalid_format")
+		return
+	}
+
+	filter := Filter{Publication: c.Query("publication")}
+	for param, dst := range map[string]**time.Time{"updated_since": &filter.UpdatedSince, "updated_until": &filter.UpdatedUntil} {
+		if v := c.Query(param); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				response.BadRequest(c, "Invalid "+param+", expected RFC 3339", err.Error())
+				return
+			}
+			*dst = &t
+		}
+	}
+
+	filename := fmt.Sprintf("catalog-%s.%s", time.Now().UTC().Format("20060102T150405Z"), format)
+	started := false
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+		if format == "csv" {
+			c.Header("Content-Type", "text/csv; charset=utf-8")
+		} else {
+			c.Header("Content-Type", "application/x-ndjson")
+		}
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+		c.Status(http.StatusOK)
+	}
+
+	var emit func(Record) error
+	var flush func() error
+	if format == "csv" {
+		w := csv.NewWriter(c.Writer)
+		begin := func() error {
+			if started {
+				return nil
+			}
+			start()
+			return w.Write(csvHeader)
+		}
+		emit = func(rec Record) error {
+			if err := begin(); err != nil {
+				return err
+			}
+			if err := w.WriteAll(csvRows(rec)); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+			return nil
+		}
+		flush = func() error {
+			if err := begin(); err != nil {
+				return err
+			}
+			w.Flush()
+			return w.Error()
+		}
+	} else {
+		enc := json.NewEncoder(c.Writer)
+		emit = func(rec Record) error {
+			start()
+			var v interface{} = rec
+			if rec.DeletedAt != nil {
+				v = Tombstone{ID: rec.ID, Slug: rec.Slug, DeletedAt: *rec.DeletedAt, UpdatedAt: rec.UpdatedAt}
+			}
+			if err := enc.Encode(v); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+			return nil
+		}
+		flush = func() error {
+			start()
+			return nil
+		}
+	}
+
+	err := h.service.Export(c.Request.Context(), filter, emit)
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		if !started {
+			response.InternalError(c, "Failed to export catalog", err.Error())
+			return
+		}
+		// Headers are already sent; cut the stream short so the client sees a broken download
+		log.Printf("Catalog export aborted: %v", err)
+		c.Abort()
+	}
+}
+
+// csvRows flattens a record into one row per episode, with a row for every
+// season without episodes and a single row for a drama without seasons. A
+// tombstone is a single row with only the slug, id and times filled in.
+func csvRows(rec Record) [][]string {
+	if rec.DeletedAt != nil {
+		r := make([]string, len(csvHeader))
+		r[0] = rec.Slug
+		copy(r[len(r)-3:], []string{rec.ID, rec.UpdatedAt.UTC().Format(time.RFC3339Nano), rec.DeletedAt.UTC().Format(time.RFC3339Nano)})
+		return [][]string{r}
+	}
+
+	genres := make([]string, 0, len(rec.Genres))
+	for _, g := range rec.Genres {
+		genres = append(genres, g.Slug)
+	}
+	actors := make([]string, 0, len(rec.Actors))
+	for _, a := range rec.Actors {
+		item := a.Actor.Name + ":" + a.Role
+		if a.Character != "" {
+			item += ":" + a.Character
+		}
+		actors = append(actors, item)
+	}
+
+	dramaCols := []string{
+		rec.Slug, rec.Title, strconv.Itoa(rec.Year), rec.Status, rec.Synopsis, rec.PosterURL, rec.SourceURL, rec.Publication,
+		rec.Country, rec.Language, strings.Join(genres, "|"), strings.Join(actors, "|"),
+	}
+	tail := []string{rec.ID, rec.UpdatedAt.UTC().Format(time.RFC3339Nano), ""}
+	row := func(season, episode []string) []string {
+		r := append([]string{}, dramaCols...)
+		r = append(r, season...)
+		r = append(r, episode...)
+		return append(r, tail...)
+	}
+
+	emptySeason := []string{"", ""}
+	emptyEpisode := []string{"", "", "", "", "", ""}
+	if len(rec.Seasons) == 0 {
+		return [][]string{row(emptySeason, emptyEpisode)}
+	}
+
+	var rows [][]string
+	for _, s := range rec.Seasons {
+		seasonCols := []string{strconv.Itoa(s.SeasonNumber), s.Title}
+		if len(s.Episodes) == 0 {
+			rows = append(rows, row(seasonCols, emptyEpisode))
+			continue
+		}
+		for _, e := range s.Episodes {
+			rows = append(rows, row(seasonCols, []string{
+				strconv.Itoa(e.EpisodeNumber), e.Title, e.VideoURL, strconv.Itoa(e.Duration), e.ThumbnailURL, e.SourceURL,
+			}))
+		}
+	}
+	return rows
+}
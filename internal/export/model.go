@@ -0,0 +1,49 @@
+package export
+
+import (
+	"drakor-backend/internal/drama"
+	"drakor-backend/internal/episode"
+	"drakor-backend/internal/season"
+	"time"
+)
+
+// Filter limits the export to dramas whose content changed in [UpdatedSince, UpdatedUntil).
+// An incremental export (UpdatedSince set) also lists dramas trashed in that window.
+type Filter struct {
+	UpdatedSince *time.Time
+	UpdatedUntil *time.Time
+	Publication  string // Empty means any publication state
+}
+
+// Record is one exported drama with its genres, cast, seasons and episodes.
+// UpdatedAt is the latest change to the drama or any of its seasons and episodes,
+// so the highest value seen can be passed back as updated_since for the next delta.
+// A record with DeletedAt set is a tombstone for a trashed drama and only
+// carries its id and slug.
+type Record struct {
+	drama.Drama
+	Seasons   []SeasonRecord `json:"seasons"`
+	DeletedAt *time.Time     `json:"deleted_at,omitempty"`
+}
+
+// Tombstone is how a trashed drama appears in a JSON Lines export
+type Tombstone struct {
+	ID        string    `json:"id"`
+	Slug      string    `json:"slug"`
+	DeletedAt time.Time `json:"deleted_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type SeasonRecord struct {
+	season.Season
+	Episodes []episode.Episode `json:"episodes"`
+}
+
+// ChangedDrama is a drama matched by the filter and the time of its latest change.
+// DeletedAt is set for a drama in the trash.
+type ChangedDrama struct {
+	ID        string
+	Slug      string
+	ChangedAt time.Time
+	DeletedAt *time.Time
+}
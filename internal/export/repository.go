@@ -0,0 +1,289 @@
+package export
+
+import (
+	"context"
+	"drakor-backend/internal/drama"
+	"drakor-backend/internal/episode"
+	"drakor-backend/internal/genre"
+	"drakor-backend/internal/season"
+	"drakor-backend/pkg/database"
+	"errors"
+	"fmt"
+)
+
+type Repository interface {
+	// FindChanged lists matching dramas, oldest change first. Incremental
+	// filters also list the dramas trashed since UpdatedSince.
+	FindChanged(ctx context.Context, filter Filter) ([]ChangedDrama, error)
+	// LoadBatch fetches full records for the given dramas, in the order of ids
+	LoadBatch(ctx context.Context, ids []string) ([]Record, error)
+}
+
+type repository struct{}
+
+func NewRepository() Repository {
+	return &repository{}
+}
+
+func (r *repository) FindChanged(ctx context.Context, filter Filter) ([]ChangedDrama, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	// Deleting a season or episode counts as a change to its drama, and trashing
+	// the drama itself is its latest change
+	sql := `
+		SELECT id, slug, changed_at, deleted_at FROM (
+			SELECT d.id, d.slug, d.deleted_at, GREATEST(
+				d.updated_at, d.deleted_at,
+				(SELECT MAX(GREATEST(s.updated_at, s.deleted_at)) FROM seasons s WHERE s.drama_id = d.id),
+				(SELECT MAX(GREATEST(e.updated_at, e.deleted_at)) FROM episodes e JOIN seasons s ON s.id = e.season_id WHERE s.drama_id = d.id)
+			) AS changed_at
+			FROM dramas d
+	`
+	args := []interface{}{}
+	argId := 1
+
+	// A full export has nothing to delete on the consumer side, so it skips the trash
+	if filter.UpdatedSince != nil {
+		sql += " WHERE TRUE"
+	} else {
+		sql += " WHERE d.deleted_at IS NULL"
+	}
+
+	if filter.Publication != "" {
+		sql += fmt.Sprintf(" AND d.publication_status = $%d", argId)
+		args = append(args, filter.Publication)
+		argId++
+	}
+	sql += ") c WHERE TRUE"
+
+	if filter.UpdatedSince != nil {
+		sql += fmt.Sprintf(" AND changed_at >= $%d", argId)
+		args = append(args, *filter.UpdatedSince)
+		argId++
+	}
+	if filter.UpdatedUntil != nil {
+		sql += fmt.Sprintf(" AND changed_at < $%d", argId)
+		args = append(args, *filter.UpdatedUntil)
+		argId++
+	}
+	sql += " ORDER BY changed_at ASC, id ASC"
+
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changed []ChangedDrama
+	for rows.Next() {
+		var c ChangedDrama
+		if err := rows.Scan(&c.ID, &c.Slug, &c.ChangedAt, &c.DeletedAt); err != nil {
+			return nil, err
+		}
+		changed = append(changed, c)
+	}
+	return changed, rows.Err()
+}
+
+func (r *repository) LoadBatch(ctx context.Context, ids []string) ([]Record, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	records := make(map[string]*Record, len(ids))
+	seasons := map[string]*SeasonRecord{}
+
+	// 1. Dramas
+	rows, err := db.Query(ctx, `
+		SELECT id, title, slug, synopsis, poster_url, year, rating, total_seasons, status, country, original_language,
+		       view_count, source_url, publication_status, publish_at, added_by, created_at, updated_at
+		FROM dramas WHERE id = ANY($1) AND deleted_at IS NULL
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var d drama.Drama
+		var synopsis, poster, source, addedBy *string
+		err := rows.Scan(
+			&d.ID, &d.Title, &d.Slug, &synopsis, &poster, &d.Year, &d.Rating, &d.TotalSeasons,
+			&d.Status, &d.Country, &d.Language, &d.ViewCount, &source, &d.Publication, &d.PublishAt, &addedBy,
+			&d.CreatedAt, &d.UpdatedAt,
+		)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if synopsis != nil {
+			d.Synopsis = *synopsis
+		}
+		if poster != nil {
+			d.PosterURL = *poster
+		}
+		if source != nil {
+			d.SourceURL = *source
+		}
+		if addedBy != nil {
+			d.AddedBy = *addedBy
+		}
+		records[d.ID] = &Record{Drama: d, Seasons: []SeasonRecord{}}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 2. Genres
+	rows, err = db.Query(ctx, `
+		SELECT dg.drama_id, g.id, g.name, g.slug
+		FROM drama_genres dg
+		JOIN genres g ON g.id = dg.genre_id
+		WHERE dg.drama_id = ANY($1) AND g.deleted_at IS NULL
+		ORDER BY g.name
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var dramaID string
+		var g genre.Genre
+		if err := rows.Scan(&dramaID, &g.ID, &g.Name, &g.Slug); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if rec, ok := records[dramaID]; ok {
+			rec.Genres = append(rec.Genres, g)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 3. Actors
+	rows, err = db.Query(ctx, `
+		SELECT da.drama_id, a.id, a.name, a.slug, a.photo_url, da.role, da.character_name, da.billing_order, da.is_cameo, da.is_guest
+		FROM drama_actors da
+		JOIN actors a ON a.id = da.actor_id
+		WHERE da.drama_id = ANY($1) AND a.deleted_at IS NULL
+		ORDER BY da.billing_order, a.name, da.character_name
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var dramaID string
+		var da drama.DramaActor
+		var photo *string
+		if err := rows.Scan(&dramaID, &da.Actor.ID, &da.Actor.Name, &da.Actor.Slug, &photo, &da.Role, &da.Character, &da.Billing, &da.IsCameo, &da.IsGuest); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if photo != nil {
+			da.Actor.PhotoURL = *photo
+		}
+		if rec, ok := records[dramaID]; ok {
+			rec.Actors = append(rec.Actors, da)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 4. Seasons
+	rows, err = db.Query(ctx, `
+		SELECT id, drama_id, season_number, title, created_at
+		FROM seasons
+		WHERE drama_id = ANY($1) AND deleted_at IS NULL
+		ORDER BY season_number
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	var seasonOrder []string
+	for rows.Next() {
+		var s season.Season
+		var title *string
+		if err := rows.Scan(&s.ID, &s.DramaID, &s.SeasonNumber, &title, &s.CreatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if title != nil {
+			s.Title = *title
+		}
+		seasons[s.ID] = &SeasonRecord{Season: s, Episodes: []episode.Episode{}}
+		seasonOrder = append(seasonOrder, s.ID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 5. Episodes
+	rows, err = db.Query(ctx, `
+		SELECT e.id, e.season_id, e.episode_number, e.title, e.slug, e.video_url, e.duration, e.thumbnail_url, e.view_count, e.source_url,
+		       e.publication_status, e.publish_at, e.added_by, e.created_at
+		FROM episodes e
+		JOIN seasons s ON s.id = e.season_id
+		WHERE s.drama_id = ANY($1) AND e.deleted_at IS NULL AND s.deleted_at IS NULL
+		ORDER BY e.episode_number
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var e episode.Episode
+		var title, thumbnail, source, addedBy *string
+		var duration *int
+		err := rows.Scan(
+			&e.ID, &e.SeasonID, &e.EpisodeNumber, &title, &e.Slug, &e.VideoURL, &duration, &thumbnail,
+			&e.ViewCount, &source, &e.Publication, &e.PublishAt, &addedBy, &e.CreatedAt,
+		)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if title != nil {
+			e.Title = *title
+		}
+		if duration != nil {
+			e.Duration = *duration
+		}
+		if thumbnail != nil {
+			e.ThumbnailURL = *thumbnail
+		}
+		if source != nil {
+			e.SourceURL = *source
+		}
+		if addedBy != nil {
+			e.AddedBy = *addedBy
+		}
+		if s, ok := seasons[e.SeasonID]; ok {
+			s.Episodes = append(s.Episodes, e)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, id := range seasonOrder {
+		s := seasons[id]
+		if rec, ok := records[s.DramaID]; ok {
+			rec.Seasons = append(rec.Seasons, *s)
+		}
+	}
+
+	result := make([]Record, 0, len(ids))
+	for _, id := range ids {
+		if rec, ok := records[id]; ok {
+			result = append(result, *rec)
+		}
+	}
+	return result, nil
+}
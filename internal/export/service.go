@@ -0,0 +1,72 @@
+package export
+
+import (
+	"context"
+	"drakor-backend/internal/drama"
+)
+
+// batchSize is the number of dramas loaded per round trip while streaming
+const batchSize = 100
+
+type Service interface {
+	// Export streams matching dramas to emit, oldest change first; trashed dramas
+	// of an incremental export come as tombstones
+	Export(ctx context.Context, filter Filter, emit func(Record) error) error
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) Export(ctx context.Context, filter Filter, emit func(Record) error) error {
+	changed, err := s.repo.FindChanged(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(changed); start += batchSize {
+		end := start + batchSize
+		if end > len(changed) {
+			end = len(changed)
+		}
+
+		ids := make([]string, 0, end-start)
+		for _, c := range changed[start:end] {
+			if c.DeletedAt == nil {
+				ids = append(ids, c.ID)
+			}
+		}
+
+		records, err := s.repo.LoadBatch(ctx, ids)
+		if err != nil {
+			return err
+		}
+		loaded := make(map[string]Record, len(records))
+		for _, rec := range records {
+			loaded[rec.ID] = rec
+		}
+
+		for _, c := range changed[start:end] {
+			var rec Record
+			if c.DeletedAt != nil {
+				rec = Record{Drama: drama.Drama{ID: c.ID, Slug: c.Slug}, DeletedAt: c.DeletedAt}
+			} else {
+				var ok bool
+				// A drama trashed after it was listed is left to the next delta
+				if rec, ok = loaded[c.ID]; !ok {
+					continue
+				}
+			}
+			rec.UpdatedAt = c.ChangedAt
+			if err := emit(rec); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
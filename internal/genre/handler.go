@@ -25,6 +25,19 @@ func (h *Handler) GetAll(c *gin.Context) {
 	response.Success(c, "Genres retrieved successfully", genres)
 }
 
+func (h *Handler) GetByID(c *gin.Context) {
+	genre, err := h.service.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.InternalError(c, "Failed to fetch genre", err.Error())
+		return
+	}
+	if genre == nil {
+		response.NotFound(c, "Genre not found")
+		return
+	}
+	response.Success(c, "Genre detail", genre)
+}
+
 func (h *Handler) Create(c *gin.Context) {
 	var req CreateGenreRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -43,6 +56,10 @@ func (h *Handler) Create(c *gin.Context) {
 			response.Error(c, http.StatusConflict, err.Error(), "slug_exists")
 			return
 		}
+		if err.Error() == "parent genre not found" || err.Error() == "invalid parent genre" {
+			response.BadRequest(c, "Invalid parent genre", "invalid_parent")
+			return
+		}
 		response.InternalError(c, "Failed to create genre", err.Error())
 		return
 	}
@@ -72,15 +89,94 @@ func (h *Handler) Update(c *gin.Context) {
 			response.Error(c, http.StatusConflict, err.Error(), "slug_exists")
 			return
 		}
+		if err.Error() == "parent genre not found" || err.Error() == "invalid parent genre" {
+			response.BadRequest(c, "Invalid parent genre", "invalid_parent")
+			return
+		}
+		response.InternalError(c, "Failed to update genre", err.Error())
+		return
+	}
+	response.Success(c, "Genre updated successfully", genre)
+}
+
+func (h *Handler) Patch(c *gin.Context) {
+	id := c.Param("id")
+	var req PatchGenreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	genre, err := h.service.Patch(c.Request.Context(), id, req)
+	if err != nil {
+		if err.Error() == "genre not found" {
+			response.NotFound(c, "Genre not found")
+			return
+		}
+		if err.Error() == "slug already exists" {
+			response.Error(c, http.StatusConflict, err.Error(), "slug_exists")
+			return
+		}
+		if err.Error() == "parent genre not found" || err.Error() == "invalid parent genre" {
+			response.BadRequest(c, "Invalid parent genre", "invalid_parent")
+			return
+		}
 		response.InternalError(c, "Failed to update genre", err.Error())
 		return
 	}
 	response.Success(c, "Genre updated successfully", genre)
 }
 
+// Reorder sets the display order from the listed genre ids
+func (h *Handler) Reorder(c *gin.Context) {
+	var req ReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	genres, err := h.service.Reorder(c.Request.Context(), req)
+	if err != nil {
+		if err.Error() == "genre not found" {
+			response.NotFound(c, "Genre not found")
+			return
+		}
+		if err.Error() == "duplicate genre" {
+			response.BadRequest(c, "Each genre may be listed once", "validation_error")
+			return
+		}
+		response.InternalError(c, "Failed to reorder genres", err.Error())
+		return
+	}
+	response.Success(c, "Genres reordered successfully", genres)
+}
+
+// Delete trashes a genre; ?replacement_id= moves its dramas to another genre first
 func (h *Handler) Delete(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+	if err := h.service.Delete(c.Request.Context(), id, c.Query("replacement_id")); err != nil {
+		if err.Error() == "genre not found" {
+			response.NotFound(c, "Genre not found")
+			return
+		}
+		if err.Error() == "genre in use" {
+			response.Error(c, http.StatusConflict, "Genre is used by dramas; pass replacement_id to move them", "genre_in_use")
+			return
+		}
+		if err.Error() == "invalid replacement genre" {
+			response.BadRequest(c, "Invalid replacement genre", "validation_error")
+			return
+		}
 		response.InternalError(c, "Failed to delete genre", err.Error())
 		return
 	}
@@ -1,17 +1,48 @@
 package genre
 
+import "drakor-backend/pkg/patch"
+
 type Genre struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-	Slug string `json:"slug"`
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	ParentID     string `json:"parent_id,omitempty"`
+	DisplayOrder int    `json:"display_order,omitempty"` // Admin-defined, 1 first; not loaded on drama detail
+	DramaCount   int    `json:"drama_count,omitempty"`   // Published dramas tagged with the genre; not loaded on drama detail
+}
+
+// GenreDetail is a genre with its place in the hierarchy
+type GenreDetail struct {
+	Genre
+	Parent   *Genre  `json:"parent,omitempty"`
+	Children []Genre `json:"children"`
 }
 
 type CreateGenreRequest struct {
-	Name string `json:"name" validate:"required,min=2,max=100"`
-	Slug string `json:"slug" validate:"required,min=2,max=100"` // Manual slug or auto-generated
+	Name         string `json:"name" validate:"required,min=2,max=100"`
+	Slug         string `json:"slug" validate:"required,min=2,max=100"` // Manual slug or auto-generated
+	ParentID     string `json:"parent_id" validate:"omitempty,uuid"`
+	DisplayOrder int    `json:"display_order" validate:"min=0"` // 0 appends the genre at the end
 }
 
 type UpdateGenreRequest struct {
-	Name string `json:"name" validate:"required,min=2,max=100"`
-	Slug string `json:"slug" validate:"required,min=2,max=100"`
+	Name         string `json:"name" validate:"required,min=2,max=100"`
+	Slug         string `json:"slug" validate:"required,min=2,max=100"`
+	ParentID     string `json:"parent_id" validate:"omitempty,uuid"`
+	DisplayOrder int    `json:"display_order" validate:"min=0"` // 0 keeps the current position
+}
+
+// PatchGenreRequest follows JSON Merge Patch: absent members are left untouched,
+// null clears the parent
+type PatchGenreRequest struct {
+	Name         patch.Field[string] `json:"name" validate:"omitnil,min=2,max=100"`
+	Slug         patch.Field[string] `json:"slug" validate:"omitnil,min=2,max=100"`
+	ParentID     patch.Field[string] `json:"parent_id" validate:"omitnil,omitempty,uuid"`
+	DisplayOrder patch.Field[int]    `json:"display_order" validate:"omitnil,min=1"`
+}
+
+// ReorderRequest lists genre ids in their new display order; genres left out
+// keep their relative order after the listed ones
+type ReorderRequest struct {
+	GenreIDs []string `json:"genre_ids" validate:"required,min=1,dive,uuid"`
 }
@@ -3,18 +3,31 @@ package genre
 import (
 	"context"
 	"drakor-backend/pkg/database"
+	"drakor-backend/pkg/patch"
 	"errors"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 )
 
 type Repository interface {
+	// FindAll lists genres in display order with their published drama counts
 	FindAll(ctx context.Context) ([]Genre, error)
 	FindByID(ctx context.Context, id string) (*Genre, error)
 	FindBySlug(ctx context.Context, slug string) (*Genre, error)
+	FindChildren(ctx context.Context, id string) ([]Genre, error)
+	// CountDramas counts the published dramas tagged with a genre
+	CountDramas(ctx context.Context, id string) (int, error)
+	// CreatesCycle reports whether making parentID the parent of id would loop the hierarchy
+	CreatesCycle(ctx context.Context, id, parentID string) (bool, error)
 	Create(ctx context.Context, genre *Genre) error
 	Update(ctx context.Context, genre *Genre) error
-	Delete(ctx context.Context, id string) error
+	Patch(ctx context.Context, id string, fields map[string]interface{}) error
+	Reorder(ctx context.Context, ids []string) error
+	// Delete trashes a genre, first moving its dramas to replacementID when given;
+	// without one it fails with "genre in use" while any drama (drafts and trashed
+	// dramas included) is tagged with it. Child genres move up to the deleted genre's parent.
+	Delete(ctx context.Context, id, replacementID string) error
 }
 
 type repository struct{}
@@ -23,13 +36,41 @@ func NewRepository() Repository {
 	return &repository{}
 }
 
+const genreColumns = `g.id, g.name, g.slug, g.parent_id, g.display_order`
+
+// scanGenre reads a row selected with the genres columns in genreColumns order,
+// followed by any extra destinations
+func scanGenre(row pgx.Row, extra ...interface{}) (*Genre, error) {
+	var g Genre
+	var parentID *string
+	dest := append([]interface{}{&g.ID, &g.Name, &g.Slug, &parentID, &g.DisplayOrder}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	if parentID != nil {
+		g.ParentID = *parentID
+	}
+	return &g, nil
+}
+
 func (r *repository) FindAll(ctx context.Context) ([]Genre, error) {
 	db := database.GetDB()
 	if db == nil {
 		return nil, errors.New("database not connected")
 	}
 
-	query := `SELECT id, name, slug FROM genres ORDER BY name ASC`
+	query := `
+		SELECT ` + genreColumns + `, COALESCE(c.n, 0)
+		FROM genres g
+		LEFT JOIN (
+			SELECT dg.genre_id, COUNT(*) AS n
+			FROM drama_genres dg
+			JOIN dramas d ON d.id = dg.drama_id AND d.deleted_at IS NULL AND d.publication_status = 'published'
+			GROUP BY dg.genre_id
+		) c ON c.genre_id = g.id
+		WHERE g.deleted_at IS NULL
+		ORDER BY g.display_order ASC, g.name ASC
+	`
 	rows, err := db.Query(ctx, query)
 	if err != nil {
 		return nil, err
@@ -38,11 +79,13 @@ func (r *repository) FindAll(ctx context.Context) ([]Genre, error) {
 
 	var genres []Genre
 	for rows.Next() {
-		var g Genre
-		if err := rows.Scan(&g.ID, &g.Name, &g.Slug); err != nil {
+		var count int
+		g, err := scanGenre(rows, &count)
+		if err != nil {
 			return nil, err
 		}
-		genres = append(genres, g)
+		g.DramaCount = count
+		genres = append(genres, *g)
 	}
 
 	return genres, nil
@@ -54,16 +97,15 @@ func (r *repository) FindByID(ctx context.Context, id string) (*Genre, error) {
 		return nil, errors.New("database not connected")
 	}
 
-	query := `SELECT id, name, slug FROM genres WHERE id = $1`
-	var g Genre
-	err := db.QueryRow(ctx, query, id).Scan(&g.ID, &g.Name, &g.Slug)
+	query := `SELECT ` + genreColumns + ` FROM genres g WHERE g.id = $1 AND g.deleted_at IS NULL`
+	g, err := scanGenre(db.QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
 	}
-	return &g, nil
+	return g, nil
 }
 
 func (r *repository) FindBySlug(ctx context.Context, slug string) (*Genre, error) {
@@ -72,16 +114,82 @@ func (r *repository) FindBySlug(ctx context.Context, slug string) (*Genre, error
 		return nil, errors.New("database not connected")
 	}
 
-	query := `SELECT id, name, slug FROM genres WHERE slug = $1`
-	var g Genre
-	err := db.QueryRow(ctx, query, slug).Scan(&g.ID, &g.Name, &g.Slug)
+	// Includes trashed genres: the slug stays reserved until the genre is purged
+	query := `SELECT ` + genreColumns + ` FROM genres g WHERE g.slug = $1`
+	g, err := scanGenre(db.QueryRow(ctx, query, slug))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
 	}
-	return &g, nil
+	return g, nil
+}
+
+func (r *repository) FindChildren(ctx context.Context, id string) ([]Genre, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	query := `
+		SELECT ` + genreColumns + `
+		FROM genres g
+		WHERE g.parent_id = $1 AND g.deleted_at IS NULL
+		ORDER BY g.display_order ASC, g.name ASC
+	`
+	rows, err := db.Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	children := []Genre{}
+	for rows.Next() {
+		g, err := scanGenre(rows)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, *g)
+	}
+	return children, rows.Err()
+}
+
+func (r *repository) CountDramas(ctx context.Context, id string) (int, error) {
+	db := database.GetDB()
+	if db == nil {
+		return 0, errors.New("database not connected")
+	}
+
+	query := `
+		SELECT COUNT(*)
+		FROM drama_genres dg
+		JOIN dramas d ON d.id = dg.drama_id AND d.deleted_at IS NULL AND d.publication_status = 'published'
+		WHERE dg.genre_id = $1
+	`
+	var count int
+	err := db.QueryRow(ctx, query, id).Scan(&count)
+	return count, err
+}
+
+func (r *repository) CreatesCycle(ctx context.Context, id, parentID string) (bool, error) {
+	db := database.GetDB()
+	if db == nil {
+		return false, errors.New("database not connected")
+	}
+
+	// Walk up from the proposed parent; meeting id means id would be its own ancestor
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id FROM genres WHERE id = $2
+			UNION
+			SELECT g.id, g.parent_id FROM genres g JOIN ancestors a ON g.id = a.parent_id
+		)
+		SELECT EXISTS(SELECT 1 FROM ancestors WHERE id = $1)
+	`
+	var cycle bool
+	err := db.QueryRow(ctx, query, id, parentID).Scan(&cycle)
+	return cycle, err
 }
 
 func (r *repository) Create(ctx context.Context, genre *Genre) error {
@@ -90,8 +198,13 @@ func (r *repository) Create(ctx context.Context, genre *Genre) error {
 		return errors.New("database not connected")
 	}
 
-	query := `INSERT INTO genres (name, slug) VALUES ($1, $2) RETURNING id`
-	return db.QueryRow(ctx, query, genre.Name, genre.Slug).Scan(&genre.ID)
+	query := `
+		INSERT INTO genres (name, slug, parent_id, display_order)
+		VALUES ($1, $2, NULLIF($3, '')::uuid,
+		        COALESCE(NULLIF($4, 0), (SELECT COALESCE(MAX(display_order), 0) + 1 FROM genres WHERE deleted_at IS NULL)))
+		RETURNING id, display_order
+	`
+	return db.QueryRow(ctx, query, genre.Name, genre.Slug, genre.ParentID, genre.DisplayOrder).Scan(&genre.ID, &genre.DisplayOrder)
 }
 
 func (r *repository) Update(ctx context.Context, genre *Genre) error {
@@ -100,18 +213,126 @@ func (r *repository) Update(ctx context.Context, genre *Genre) error {
 		return errors.New("database not connected")
 	}
 
-	query := `UPDATE genres SET name = $1, slug = $2 WHERE id = $3`
-	_, err := db.Exec(ctx, query, genre.Name, genre.Slug, genre.ID)
+	query := `UPDATE genres SET name = $1, slug = $2, parent_id = NULLIF($3, '')::uuid, display_order = $4 WHERE id = $5`
+	_, err := db.Exec(ctx, query, genre.Name, genre.Slug, genre.ParentID, genre.DisplayOrder, genre.ID)
 	return err
 }
 
-func (r *repository) Delete(ctx context.Context, id string) error {
+func (r *repository) Patch(ctx context.Context, id string, fields map[string]interface{}) error {
 	db := database.GetDB()
 	if db == nil {
 		return errors.New("database not connected")
 	}
+	return patch.Update(ctx, db, "genres", []string{"name", "slug", "parent_id", "display_order"}, fields, id)
+}
 
-	query := `DELETE FROM genres WHERE id = $1`
-	_, err := db.Exec(ctx, query, id)
-	return err
+func (r *repository) Reorder(ctx context.Context, ids []string) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE genres g SET display_order = o.n
+		FROM unnest($1::uuid[]) WITH ORDINALITY AS o(id, n)
+		WHERE g.id = o.id AND g.deleted_at IS NULL
+	`, ids)
+	if err != nil {
+		return err
+	}
+	if int(tag.RowsAffected()) != len(ids) {
+		return errors.New("genre not found")
+	}
+
+	// Genres left out follow the listed ones, keeping their relative order
+	_, err = tx.Exec(ctx, `
+		UPDATE genres g SET display_order = cardinality($1::uuid[]) + o.n
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY display_order, name) AS n
+			FROM genres
+			WHERE deleted_at IS NULL AND NOT (id = ANY($1::uuid[]))
+		) o
+		WHERE g.id = o.id
+	`, ids)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *repository) Delete(ctx context.Context, id, replacementID string) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Locking the genre holds off dramas being tagged with it until it is trashed,
+	// so the usage count below stays true
+	var locked int
+	err = tx.QueryRow(ctx, "SELECT 1 FROM genres WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errors.New("genre not found")
+		}
+		return err
+	}
+
+	now := time.Now()
+	if replacementID == "" {
+		var count int
+		if err := tx.QueryRow(ctx, "SELECT COUNT(*) FROM drama_genres WHERE genre_id = $1", id).Scan(&count); err != nil {
+			return err
+		}
+		if count > 0 {
+			return errors.New("genre in use")
+		}
+	} else {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO drama_genres (drama_id, genre_id)
+			SELECT drama_id, $2 FROM drama_genres WHERE genre_id = $1
+			ON CONFLICT DO NOTHING
+		`, id, replacementID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `
+			UPDATE dramas SET updated_at = $2
+			WHERE id IN (SELECT drama_id FROM drama_genres WHERE genre_id = $1)
+		`, id, now)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, "DELETE FROM drama_genres WHERE genre_id = $1", id); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE genres c SET parent_id = g.parent_id
+		FROM genres g
+		WHERE g.id = $1 AND c.parent_id = g.id
+	`, id)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE genres SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	if _, err := tx.Exec(ctx, query, now, id); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
@@ -8,9 +8,15 @@ import (
 
 type Service interface {
 	GetAll(ctx context.Context) ([]Genre, error)
+	// GetByID accepts the genre UUID or slug and includes its parent, children and drama count
+	GetByID(ctx context.Context, id string) (*GenreDetail, error)
 	Create(ctx context.Context, req CreateGenreRequest) (*Genre, error)
 	Update(ctx context.Context, id string, req UpdateGenreRequest) (*Genre, error)
-	Delete(ctx context.Context, id string) error
+	Patch(ctx context.Context, id string, req PatchGenreRequest) (*Genre, error)
+	Reorder(ctx context.Context, req ReorderRequest) ([]Genre, error)
+	// Delete refuses a genre still used by dramas unless replacementID names
+	// another genre to move those dramas to
+	Delete(ctx context.Context, id, replacementID string) error
 }
 
 type service struct {
@@ -25,6 +31,34 @@ func (s *service) GetAll(ctx context.Context) ([]Genre, error) {
 	return s.repo.FindAll(ctx)
 }
 
+func (s *service) GetByID(ctx context.Context, id string) (*GenreDetail, error) {
+	var g *Genre
+	var err error
+	if validator.IsUUID(id) {
+		g, err = s.repo.FindByID(ctx, id)
+	} else if g, err = s.repo.FindBySlug(ctx, id); g != nil {
+		// FindBySlug also sees trashed genres
+		g, err = s.repo.FindByID(ctx, g.ID)
+	}
+	if err != nil || g == nil {
+		return nil, err
+	}
+
+	detail := &GenreDetail{Genre: *g}
+	if detail.DramaCount, err = s.repo.CountDramas(ctx, g.ID); err != nil {
+		return nil, err
+	}
+	if g.ParentID != "" {
+		if detail.Parent, err = s.repo.FindByID(ctx, g.ParentID); err != nil {
+			return nil, err
+		}
+	}
+	if detail.Children, err = s.repo.FindChildren(ctx, g.ID); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
 func (s *service) Create(ctx context.Context, req CreateGenreRequest) (*Genre, error) {
 	// Generate slug if empty
 	if req.Slug == "" {
@@ -42,9 +76,15 @@ func (s *service) Create(ctx context.Context, req CreateGenreRequest) (*Genre, e
 		return nil, errors.New("slug already exists")
 	}
 
+	if err := s.checkParent(ctx, "", req.ParentID); err != nil {
+		return nil, err
+	}
+
 	genre := &Genre{
-		Name: req.Name,
-		Slug: req.Slug,
+		Name:         req.Name,
+		Slug:         req.Slug,
+		ParentID:     req.ParentID,
+		DisplayOrder: req.DisplayOrder,
 	}
 
 	if err := s.repo.Create(ctx, genre); err != nil {
@@ -63,8 +103,16 @@ func (s *service) Update(ctx context.Context, id string, req UpdateGenreRequest)
 		return nil, errors.New("genre not found")
 	}
 
+	if err := s.checkParent(ctx, genre.ID, req.ParentID); err != nil {
+		return nil, err
+	}
+
 	// Update fields
 	genre.Name = req.Name
+	genre.ParentID = req.ParentID
+	if req.DisplayOrder > 0 {
+		genre.DisplayOrder = req.DisplayOrder
+	}
 	newSlug := validator.GenerateSlug(req.Slug)
 
 	// Check if new slug conflicts with OTHER genre
@@ -86,7 +134,117 @@ func (s *service) Update(ctx context.Context, id string, req UpdateGenreRequest)
 	return genre, nil
 }
 
-func (s *service) Delete(ctx context.Context, id string) error {
-	// Check/validasi referensi drama_genres nanti bisa ditambahkan disini
-	return s.repo.Delete(ctx, id)
+func (s *service) Patch(ctx context.Context, id string, req PatchGenreRequest) (*Genre, error) {
+	genre, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if genre == nil {
+		return nil, errors.New("genre not found")
+	}
+
+	fields := map[string]interface{}{}
+	if req.Name.Set {
+		fields["name"] = req.Name.Value
+	}
+	if req.Slug.Set {
+		newSlug := validator.GenerateSlug(req.Slug.Value)
+		if newSlug != genre.Slug {
+			existing, err := s.repo.FindBySlug(ctx, newSlug)
+			if err != nil {
+				return nil, err
+			}
+			if existing != nil {
+				return nil, errors.New("slug already exists")
+			}
+			fields["slug"] = newSlug
+		}
+	}
+	if req.ParentID.Set {
+		if err := s.checkParent(ctx, genre.ID, req.ParentID.Value); err != nil {
+			return nil, err
+		}
+		if req.ParentID.Value == "" {
+			fields["parent_id"] = nil
+		} else {
+			fields["parent_id"] = req.ParentID.Value
+		}
+	}
+	if req.DisplayOrder.Set && !req.DisplayOrder.Null {
+		fields["display_order"] = req.DisplayOrder.Value
+	}
+
+	if err := s.repo.Patch(ctx, genre.ID, fields); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(ctx, genre.ID)
+}
+
+func (s *service) Reorder(ctx context.Context, req ReorderRequest) ([]Genre, error) {
+	seen := map[string]bool{}
+	for _, id := range req.GenreIDs {
+		if seen[id] {
+			return nil, errors.New("duplicate genre")
+		}
+		seen[id] = true
+	}
+
+	if err := s.repo.Reorder(ctx, req.GenreIDs); err != nil {
+		return nil, err
+	}
+	return s.repo.FindAll(ctx)
+}
+
+func (s *service) Delete(ctx context.Context, id, replacementID string) error {
+	genre, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if genre == nil {
+		return errors.New("genre not found")
+	}
+
+	if replacementID != "" {
+		if !validator.IsUUID(replacementID) || replacementID == genre.ID {
+			return errors.New("invalid replacement genre")
+		}
+		replacement, err := s.repo.FindByID(ctx, replacementID)
+		if err != nil {
+			return err
+		}
+		if replacement == nil {
+			return errors.New("invalid replacement genre")
+		}
+	}
+
+	return s.repo.Delete(ctx, genre.ID, replacementID)
+}
+
+// checkParent rejects a parent that does not exist or that sits below the genre
+func (s *service) checkParent(ctx context.Context, id, parentID string) error {
+	if parentID == "" {
+		return nil
+	}
+	if parentID == id {
+		return errors.New("invalid parent genre")
+	}
+	parent, err := s.repo.FindByID(ctx, parentID)
+	if err != nil {
+		return err
+	}
+	if parent == nil {
+		return errors.New("parent genre not found")
+	}
+	if id == "" {
+		return nil
+	}
+	cycle, err := s.repo.CreatesCycle(ctx, id, parentID)
+	if err != nil {
+		return err
+	}
+	if cycle {
+		return errors.New("invalid parent genre")
+	}
+	return nil
 }
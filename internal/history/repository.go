@@ -50,7 +50,15 @@ func (r *repository) GetByUser(ctx context.Context, userID string, limit, offset
 	}
 
 	var total int64
-	err := db.QueryRow(ctx, "SELECT COUNT(*) FROM watch_history WHERE user_id = $1", userID).Scan(&total)
+	countQuery := `
+		SELECT COUNT(*) FROM watch_history wh
+		JOIN episodes e ON wh.episode_id = e.id
+		JOIN seasons s ON e.season_id = s.id
+		JOIN dramas d ON s.drama_id = d.id
+		WHERE wh.user_id = $1 AND e.deleted_at IS NULL AND s.deleted_at IS NULL AND d.deleted_at IS NULL
+		  AND e.publication_status = 'published' AND d.publication_status = 'published'
+	`
+	err := db.QueryRow(ctx, countQuery, userID).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -61,7 +69,10 @@ func (r *repository) GetByUser(ctx context.Context, userID string, limit, offset
 		       e.id, e.season_id, e.episode_number, e.title, e.thumbnail_url, e.duration
 		FROM watch_history wh
 		JOIN episodes e ON wh.episode_id = e.id
-		WHERE wh.user_id = $1
+		JOIN seasons s ON e.season_id = s.id
+		JOIN dramas d ON s.drama_id = d.id
+		WHERE wh.user_id = $1 AND e.deleted_at IS NULL AND s.deleted_at IS NULL AND d.deleted_at IS NULL
+		  AND e.publication_status = 'published' AND d.publication_status = 'published'
 		ORDER BY wh.last_watched_at DESC
 		LIMIT $2 OFFSET $3
 	`
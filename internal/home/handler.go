@@ -0,0 +1,46 @@
+package home
+
+import (
+	"drakor-backend/pkg/response"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Get returns the home screen rows, personalized when a token is sent.
+// ?rows=trending,genre:romance overrides the configured layout.
+func (h *Handler) Get(c *gin.Context) {
+	userID := ""
+	if v, exists := c.Get("userID"); exists {
+		userID = v.(string)
+	}
+	var rows []string
+	if v := c.Query("rows"); v != "" {
+		for _, key := range strings.Split(v, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				rows = append(rows, key)
+			}
+		}
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	home, err := h.service.Get(c.Request.Context(), userID, rows, limit)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "unknown row") {
+			response.BadRequest(c, "Invalid rows", err.Error())
+			return
+		}
+		response.InternalError(c, "Failed to build home", err.Error())
+		return
+	}
+	response.Success(c, "Home retrieved successfully", home)
+}
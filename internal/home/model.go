@@ -0,0 +1,40 @@
+package home
+
+import (
+	"drakor-backend/internal/episode"
+	"time"
+)
+
+type Home struct {
+	Rows    []Row    `json:"rows"`
+	Skipped []string `json:"skipped"` // Rows that failed or timed out
+}
+
+// Row is one shelf of the home screen. Items holds dramas, episodes,
+// continue-watching entries or watchlist items depending on Type.
+type Row struct {
+	Key   string      `json:"key"`  // e.g. "trending", "genre:romance"
+	Type  string      `json:"type"` // 'dramas', 'episodes', 'continue_watching', 'watchlist'
+	Title string      `json:"title"`
+	Items interface{} `json:"items"`
+}
+
+// EpisodeItem is an episode with enough of its drama to render a card
+type EpisodeItem struct {
+	episode.Episode
+	SeasonNumber int       `json:"season_number"`
+	Drama        DramaCard `json:"drama"`
+}
+
+type DramaCard struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Slug      string `json:"slug"`
+	PosterURL string `json:"poster_url"`
+}
+
+type ContinueItem struct {
+	ProgressSeconds int         `json:"progress_seconds"`
+	LastWatchedAt   time.Time   `json:"last_watched_at"`
+	Episode         EpisodeItem `json:"episode"`
+}
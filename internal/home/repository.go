@@ -0,0 +1,194 @@
+package home
+
+import (
+	"context"
+	"drakor-backend/internal/drama"
+	"drakor-backend/internal/genre"
+	"drakor-backend/pkg/database"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type Repository interface {
+	// NewEpisodes lists the most recently released public episodes, leaving out
+	// dramas with an advisory userID (empty for guests) excluded
+	NewEpisodes(ctx context.Context, userID string, limit int) ([]EpisodeItem, error)
+	// ContinueWatching lists the user's latest unfinished episode per drama
+	ContinueWatching(ctx context.Context, userID string, limit int) ([]ContinueItem, error)
+	FindGenre(ctx context.Context, slug string) (*genre.Genre, error)
+	// TopGenres lists the genres with the most published dramas
+	TopGenres(ctx context.Context, limit int) ([]genre.Genre, error)
+}
+
+type repository struct{}
+
+func NewRepository() Repository {
+	return &repository{}
+}
+
+// episodeColumns selects an EpisodeItem from episodes e, seasons s and dramas d
+const episodeColumns = `
+	e.id, e.season_id, e.episode_number, e.title, e.slug, e.video_url, e.duration, e.thumbnail_url, e.view_count,
+	e.publication_status, e.publish_at, e.created_at,
+	s.season_number, d.id, d.title, d.slug, d.poster_url
+`
+
+// publicEpisode limits e, s and d to content visitors can see
+const publicEpisode = `
+	e.deleted_at IS NULL AND s.deleted_at IS NULL AND d.deleted_at IS NULL
+	AND e.publication_status = 'published' AND d.publication_status = 'published'
+`
+
+func scanEpisodeItem(rows pgx.Rows, extra ...interface{}) (EpisodeItem, error) {
+	var item EpisodeItem
+	var title, thumbnail, poster *string
+	var duration *int
+	dest := []interface{}{
+		&item.ID, &item.SeasonID, &item.EpisodeNumber, &title, &item.Slug, &item.VideoURL, &duration, &thumbnail, &item.ViewCount,
+		&item.Publication, &item.PublishAt, &item.CreatedAt,
+		&item.SeasonNumber, &item.Drama.ID, &item.Drama.Title, &item.Drama.Slug, &poster,
+	}
+	if err := rows.Scan(append(dest, extra...)...); err != nil {
+		return item, err
+	}
+	if title != nil {
+		item.Title = *title
+	}
+	if duration != nil {
+		item.Duration = *duration
+	}
+	if thumbnail != nil {
+		item.ThumbnailURL = *thumbnail
+	}
+	if poster != nil {
+		item.Drama.PosterURL = *poster
+	}
+	return item, nil
+}
+
+func (r *repository) NewEpisodes(ctx context.Context, userID string, limit int) ([]EpisodeItem, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	args := []interface{}{limit}
+	excluded := ""
+	if userID != "" {
+		excluded = " AND d.id NOT IN (" + drama.ExcludedDramas(drama.ViewerExclusions("$2")) + ")"
+		args = append(args, userID)
+	}
+
+	query := `
+		SELECT ` + episodeColumns + `
+		FROM episodes e
+		JOIN seasons s ON s.id = e.season_id
+		JOIN dramas d ON d.id = s.drama_id
+		WHERE ` + publicEpisode + excluded + `
+		ORDER BY COALESCE(e.publish_at, e.created_at) DESC, e.id
+		LIMIT $1
+	`
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []EpisodeItem{}
+	for rows.Next() {
+		item, err := scanEpisodeItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *repository) ContinueWatching(ctx context.Context, userID string, limit int) ([]ContinueItem, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	query := `
+		SELECT * FROM (
+			SELECT DISTINCT ON (d.id) ` + episodeColumns + `, wh.progress_seconds, wh.last_watched_at
+			FROM watch_history wh
+			JOIN episodes e ON e.id = wh.episode_id
+			JOIN seasons s ON s.id = e.season_id
+			JOIN dramas d ON d.id = s.drama_id
+			WHERE wh.user_id = $1 AND NOT wh.completed AND ` + publicEpisode + `
+			ORDER BY d.id, wh.last_watched_at DESC
+		) latest
+		ORDER BY last_watched_at DESC
+		LIMIT $2
+	`
+	rows, err := db.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []ContinueItem{}
+	for rows.Next() {
+		var item ContinueItem
+		item.Episode, err = scanEpisodeItem(rows, &item.ProgressSeconds, &item.LastWatchedAt)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *repository) FindGenre(ctx context.Context, slug string) (*genre.Genre, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	var g genre.Genre
+	err := db.QueryRow(ctx, `SELECT id, name, slug FROM genres WHERE slug = $1 AND deleted_at IS NULL`, slug).Scan(&g.ID, &g.Name, &g.Slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (r *repository) TopGenres(ctx context.Context, limit int) ([]genre.Genre, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	query := `
+		SELECT g.id, g.name, g.slug
+		FROM genres g
+		JOIN drama_genres dg ON dg.genre_id = g.id
+		JOIN dramas d ON d.id = dg.drama_id AND d.deleted_at IS NULL AND d.publication_status = 'published'
+		WHERE g.deleted_at IS NULL
+		GROUP BY g.id, g.name, g.slug
+		ORDER BY COUNT(*) DESC, g.name
+		LIMIT $1
+	`
+	rows, err := db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	genres := []genre.Genre{}
+	for rows.Next() {
+		var g genre.Genre
+		if err := rows.Scan(&g.ID, &g.Name, &g.Slug); err != nil {
+			return nil, err
+		}
+		genres = append(genres, g)
+	}
+	return genres, rows.Err()
+}
@@ -0,0 +1,283 @@
+package home
+
+import (
+	"context"
+	"drakor-backend/internal/collection"
+	"drakor-backend/internal/drama"
+	"drakor-backend/internal/watchlist"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRows is the home layout used when none is configured. Besides the
+// fixed rows, "genre:<slug>" and "collection:<slug>" add a single row, while
+// "genres" and "collections" expand to the top genres and every visible collection.
+var DefaultRows = []string{"continue_watching", "trending", "new_episodes", "watchlist", "collections", "genres"}
+
+// topGenreRows is how many genre rows "genres" expands to
+const topGenreRows = 3
+
+type Config struct {
+	Rows       []string      // Layout, in display order
+	RowTimeout time.Duration // How long a single row may take before it is skipped
+}
+
+type Service interface {
+	// Get builds the home rows for userID (empty for guests); rows overrides the configured layout
+	Get(ctx context.Context, userID string, rows []string, limit int) (*Home, error)
+}
+
+type service struct {
+	repo        Repository
+	dramas      drama.Service
+	watchlist   watchlist.Service
+	collections collection.Service
+	config      Config
+}
+
+func NewService(repo Repository, dramas drama.Service, watchlist watchlist.Service, collections collection.Service, config Config) Service {
+	rows := []string{}
+	for _, key := range config.Rows {
+		if key = strings.TrimSpace(key); key != "" {
+			rows = append(rows, key)
+		}
+	}
+	config.Rows = rows
+	if len(config.Rows) == 0 {
+		config.Rows = DefaultRows
+	}
+	if config.RowTimeout <= 0 {
+		config.RowTimeout = 2 * time.Second
+	}
+	return &service{repo: repo, dramas: dramas, watchlist: watchlist, collections: collections, config: config}
+}
+
+// rowBuilder loads the rows for one layout entry; it may return none
+type rowBuilder func(ctx context.Context) ([]Row, error)
+
+// layoutRow is a builder of the expanded layout and the key it is reported under
+type layoutRow struct {
+	key   string
+	build rowBuilder
+}
+
+type rowResult struct {
+	rows []Row
+	err  error
+}
+
+func (s *service) Get(ctx context.Context, userID string, rows []string, limit int) (*Home, error) {
+	if limit < 1 || limit > 30 {
+		limit = 10
+	}
+	if len(rows) == 0 {
+		rows = s.config.Rows
+	}
+
+	var layout []layoutRow
+	for _, key := range rows {
+		expanded, err := s.expand(ctx, key, userID, limit)
+		if err != nil {
+			return nil, err
+		}
+		layout = append(layout, expanded...)
+	}
+
+	// Every row runs concurrently under its own deadline
+	results := make([][]Row, len(layout))
+	failed := make([]bool, len(layout))
+	var wg sync.WaitGroup
+	for i, l := range layout {
+		b := l.build
+		if b == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, b rowBuilder) {
+			defer wg.Done()
+			rowCtx, cancel := context.WithTimeout(ctx, s.config.RowTimeout)
+			defer cancel()
+
+			// Buffered so an abandoned builder can still finish and exit
+			done := make(chan rowResult, 1)
+			go func() {
+				built, err := b(rowCtx)
+				done <- rowResult{built, err}
+			}()
+
+			var res rowResult
+			select {
+			case res = <-done:
+			case <-rowCtx.Done():
+				res.err = rowCtx.Err()
+			}
+			if res.err != nil {
+				log.Printf("Home row %s skipped: %v", layout[i].key, res.err)
+				failed[i] = true
+				return
+			}
+			results[i] = res.rows
+		}(i, b)
+	}
+	wg.Wait()
+
+	home := &Home{Rows: []Row{}, Skipped: []string{}}
+	for i, built := range results {
+		if failed[i] {
+			home.Skipped = append(home.Skipped, layout[i].key)
+			continue
+		}
+		home.Rows = append(home.Rows, built...)
+	}
+	return home, nil
+}
+
+// expand resolves a layout entry into its row builders. "genres" and "collections"
+// list their rows up front, under the row timeout, so each expanded row gets its
+// own builder and deadline; if the listing fails the entry is skipped as a whole.
+func (s *service) expand(ctx context.Context, key, userID string, limit int) ([]layoutRow, error) {
+	var list func(ctx context.Context) ([]layoutRow, error)
+	switch key {
+	case "genres":
+		list = func(ctx context.Context) ([]layoutRow, error) {
+			genres, err := s.repo.TopGenres(ctx, topGenreRows)
+			if err != nil {
+				return nil, err
+			}
+			expanded := make([]layoutRow, 0, len(genres))
+			for _, g := range genres {
+				rowKey := "genre:" + g.Slug
+				filter := drama.Filter{GenreID: g.ID, Sort: "rating", Viewer: userID}
+				expanded = append(expanded, layoutRow{rowKey, s.dramaRow(rowKey, g.Name, filter, limit)})
+			}
+			return expanded, nil
+		}
+	case "collections":
+		list = func(ctx context.Context) ([]layoutRow, error) {
+			collections, err := s.collections.GetAll(ctx, false)
+			if err != nil {
+				return nil, err
+			}
+			expanded := make([]layoutRow, 0, len(collections))
+			for _, c := range collections {
+				slug := c.Slug
+				expanded = append(expanded, layoutRow{"collection:" + slug, func(ctx context.Context) ([]Row, error) {
+					return s.collectionRow(ctx, slug, userID, limit)
+				}})
+			}
+			return expanded, nil
+		}
+	default:
+		b, err := s.builder(key, userID, limit)
+		if err != nil {
+			return nil, err
+		}
+		return []layoutRow{{key, b}}, nil
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, s.config.RowTimeout)
+	defer cancel()
+	expanded, err := list(listCtx)
+	if err != nil {
+		// Reported as skipped, like a row whose builder fails
+		return []layoutRow{{key, func(context.Context) ([]Row, error) { return nil, err }}}, nil
+	}
+	return expanded, nil
+}
+
+// builder resolves a single-row layout entry; personal rows are nil for guests
+func (s *service) builder(key, userID string, limit int) (rowBuilder, error) {
+	kind, arg, _ := strings.Cut(key, ":")
+	switch {
+	case key == "trending":
+		return s.dramaRow(key, "Trending Now", drama.Filter{Sort: "popular", Viewer: userID}, limit), nil
+	case key == "new_episodes":
+		return func(ctx context.Context) ([]Row, error) {
+			items, err := s.repo.NewEpisodes(ctx, userID, limit)
+			if err != nil || len(items) == 0 {
+				return nil, err
+			}
+			return []Row{{Key: key, Type: "episodes", Title: "New Episodes", Items: items}}, nil
+		}, nil
+	case key == "continue_watching":
+		if userID == "" {
+			return nil, nil
+		}
+		return func(ctx context.Context) ([]Row, error) {
+			items, err := s.repo.ContinueWatching(ctx, userID, limit)
+			if err != nil || len(items) == 0 {
+				return nil, err
+			}
+			return []Row{{Key: key, Type: "continue_watching", Title: "Continue Watching", Items: items}}, nil
+		}, nil
+	case key == "watchlist":
+		if userID == "" {
+			return nil, nil
+		}
+		return func(ctx context.Context) ([]Row, error) {
+			items, _, err := s.watchlist.GetMyWatchlist(ctx, userID, 1, limit)
+			if err != nil || len(items) == 0 {
+				return nil, err
+			}
+			return []Row{{Key: key, Type: "watchlist", Title: "My Watchlist", Items: items}}, nil
+		}, nil
+	case kind == "genre" && arg != "":
+		return func(ctx context.Context) ([]Row, error) {
+			g, err := s.repo.FindGenre(ctx, arg)
+			if err != nil || g == nil {
+				return nil, err
+			}
+			return s.dramaRow(key, g.Name, drama.Filter{GenreID: g.ID, Sort: "rating", Viewer: userID}, limit)(ctx)
+		}, nil
+	case kind == "collection" && arg != "":
+		return func(ctx context.Context) ([]Row, error) {
+			return s.collectionRow(ctx, arg, userID, limit)
+		}, nil
+	}
+	return nil, errors.New("unknown row: " + key)
+}
+
+// dramaRow lists published dramas matching filter
+func (s *service) dramaRow(key, title string, filter drama.Filter, limit int) rowBuilder {
+	return func(ctx context.Context) ([]Row, error) {
+		filter.Publication = "published"
+		dramas, _, err := s.dramas.GetAll(ctx, 1, limit, filter)
+		if err != nil || len(dramas) == 0 {
+			return nil, err
+		}
+		return []Row{{Key: key, Type: "dramas", Title: title, Items: dramas}}, nil
+	}
+}
+
+// collectionRow lists a collection's dramas, leaving out those with an advisory
+// userID excluded
+func (s *service) collectionRow(ctx context.Context, slug, userID string, limit int) ([]Row, error) {
+	c, err := s.collections.GetByID(ctx, slug, false)
+	if err != nil || c == nil || len(c.Dramas) == 0 {
+		return nil, err
+	}
+	ids := make([]string, 0, len(c.Dramas))
+	for _, d := range c.Dramas {
+		ids = append(ids, d.ID)
+	}
+	excluded, err := s.dramas.GetExcluded(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	dramas := []drama.Drama{}
+	for _, d := range c.Dramas {
+		if !excluded[d.ID] {
+			dramas = append(dramas, d)
+		}
+	}
+	if len(dramas) == 0 {
+		return nil, nil
+	}
+	if len(dramas) > limit {
+		dramas = dramas[:limit]
+	}
+	return []Row{{Key: "collection:" + c.Slug, Type: "dramas", Title: c.Title, Items: dramas}}, nil
+}
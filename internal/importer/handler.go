@@ -0,0 +1,69 @@
+package importer
+
+import (
+	"drakor-backend/pkg/response"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBundleSize caps the request body accepted by the import endpoint
+const maxBundleSize = 10 << 20
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Import accepts a JSON bundle, or CSV when Content-Type is text/csv or ?format=csv.
+// With ?dry_run=true the bundle is fully checked but nothing is saved.
+func (h *Handler) Import(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	format := c.Query("format")
+	if format == "" {
+		format = "json"
+		if strings.HasPrefix(c.ContentType(), "text/csv") {
+			format = "csv"
+		}
+	}
+	dryRun := c.Query("dry_run") == "true"
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxBundleSize)
+	report, err := h.service.Import(c.Request.Context(), userID.(string), format, body, dryRun)
+	if err != nil {
+		if err.Error() == "unsupported format" {
+			response.BadRequest(c, "Format must be json or csv", "invalid_format")
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid bundle") {
+			response.BadRequest(c, "Invalid input", err.Error())
+			return
+		}
+		response.InternalError(c, "Failed to import catalog", err.Error())
+		return
+	}
+
+	if !report.Valid {
+		c.JSON(http.StatusBadRequest, response.Response{
+			Success: false,
+			Message: "Validation failed",
+			Data:    report,
+			Error:   "validation_error",
+		})
+		return
+	}
+	if dryRun {
+		response.Success(c, "Dry run completed, nothing was saved", report)
+		return
+	}
+	response.Success(c, "Catalog imported successfully", report)
+}
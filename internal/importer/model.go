@@ -0,0 +1,81 @@
+package importer
+
+// Bundle is a nested catalog import: dramas with their genres, cast, seasons and episodes
+type Bundle struct {
+	Dramas []DramaRow `json:"dramas"`
+}
+
+// DramaRow is matched to an existing drama by slug (current or former),
+// or by title and year when no slug is given
+type DramaRow struct {
+	Title       string      `json:"title" validate:"required,min=2,max=255"`
+	Slug        string      `json:"slug" validate:"omitempty,min=2,max=255"` // Auto-generated from title if empty
+	Synopsis    string      `json:"synopsis"`
+	PosterURL   string      `json:"poster_url" validate:"omitempty,url"`
+	Year        int         `json:"year" validate:"required,min=1900,max=2100"`
+	Status      string      `json:"status" validate:"required,oneof=upcoming ongoing hiatus completed cancelled"`
+	Country     string      `json:"country" validate:"omitempty,iso3166_1_alpha2"`   // New dramas default to KR
+	Language    string      `json:"original_language" validate:"omitempty,iso639_1"` // New dramas default to ko
+	SourceURL   string      `json:"source_url" validate:"omitempty,url"`
+	Publication string      `json:"publication_status" validate:"omitempty,oneof=draft published"` // New content defaults to draft
+	Genres      []string    `json:"genres"`                                                        // Genre slugs, must already exist
+	Actors      []ActorRow  `json:"actors"`
+	Seasons     []SeasonRow `json:"seasons"`
+
+	row int // CSV record the drama was first seen on
+}
+
+// ActorRow is matched by name (case-insensitive); unknown actors are created
+type ActorRow struct {
+	Name      string `json:"name" validate:"required,min=2,max=100"`
+	Role      string `json:"role" validate:"omitempty,oneof=main support"` // Defaults to main
+	Character string `json:"character_name" validate:"omitempty,max=150"`
+}
+
+// SeasonRow is matched by drama and season number
+type SeasonRow struct {
+	SeasonNumber int          `json:"season_number" validate:"required,min=1"`
+	Title        string       `json:"title"`
+	Episodes     []EpisodeRow `json:"episodes"`
+
+	row int
+}
+
+// EpisodeRow is matched by season and episode number
+type EpisodeRow struct {
+	EpisodeNumber int    `json:"episode_number" validate:"required,min=1"`
+	Title         string `json:"title" validate:"required"`
+	VideoURL      string `json:"video_url" validate:"required,url"`
+	Duration      int    `json:"duration" validate:"min=1"`
+	ThumbnailURL  string `json:"thumbnail_url" validate:"omitempty,url"`
+	SourceURL     string `json:"source_url" validate:"omitempty,url"`
+
+	row int
+}
+
+// RowError points at the part of the bundle that failed.
+// Row is the CSV record number (header is row 1) and is omitted for JSON input.
+type RowError struct {
+	Row     int    `json:"row,omitempty"`
+	Path    string `json:"path"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type Summary struct {
+	DramasCreated   int `json:"dramas_created"`
+	DramasUpdated   int `json:"dramas_updated"`
+	SeasonsCreated  int `json:"seasons_created"`
+	SeasonsUpdated  int `json:"seasons_updated"`
+	EpisodesCreated int `json:"episodes_created"`
+	EpisodesUpdated int `json:"episodes_updated"`
+	ActorsCreated   int `json:"actors_created"`
+}
+
+// Report is returned for both dry runs and real imports; nothing is written unless Valid
+type Report struct {
+	DryRun  bool       `json:"dry_run"`
+	Valid   bool       `json:"valid"`
+	Errors  []RowError `json:"errors"`
+	Summary Summary    `json:"summary"`
+}
@@ -0,0 +1,419 @@
+package importer
+
+import (
+	"context"
+	"drakor-backend/internal/drama"
+	"drakor-backend/pkg/database"
+	"drakor-backend/pkg/validator"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type Repository interface {
+	// Apply writes the bundle in a single transaction. Rows that reference missing
+	// or trashed data are reported instead of written; the transaction is rolled
+	// back when any are found or when dryRun is set.
+	Apply(ctx context.Context, userID string, bundle *Bundle, dryRun bool) (*Summary, []RowError, error)
+}
+
+type repository struct{}
+
+func NewRepository() Repository {
+	return &repository{}
+}
+
+// importTx carries the state of one import run
+type importTx struct {
+	tx      pgx.Tx
+	userID  *string
+	now     time.Time
+	summary Summary
+	errors  []RowError
+	actors  map[string]string // lower(name) -> id, so repeated names resolve to one actor
+}
+
+func (r *repository) Apply(ctx context.Context, userID string, bundle *Bundle, dryRun bool) (*Summary, []RowError, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, nil, errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	it := &importTx{tx: tx, now: time.Now(), actors: map[string]string{}}
+	if userID != "" {
+		it.userID = &userID
+	}
+
+	for i := range bundle.Dramas {
+		if err := it.importDrama(ctx, &bundle.Dramas[i], fmt.Sprintf("dramas[%d]", i)); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(it.errors) > 0 || dryRun {
+		return &it.summary, it.errors, nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, err
+	}
+	return &it.summary, nil, nil
+}
+
+func (it *importTx) reject(row int, path, message string) {
+	it.errors = append(it.errors, RowError{Row: row, Path: path, Message: message})
+}
+
+// editor is the importing user for status history and revisions, empty when unknown
+func (it *importTx) editor() string {
+	if it.userID == nil {
+		return ""
+	}
+	return *it.userID
+}
+
+func (it *importTx) importDrama(ctx context.Context, d *DramaRow, path string) error {
+	genreIDs, ok, err := it.resolveGenres(ctx, d, path)
+	if err != nil {
+		return err
+	}
+
+	id, slug, trashed, err := it.findDrama(ctx, d)
+	if err != nil {
+		return err
+	}
+	if trashed {
+		it.reject(d.row, path, "drama is in trash, restore it before importing")
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+
+	action := "update"
+	if id == "" {
+		action = "create"
+		slug, err = it.newDramaSlug(ctx, d)
+		if err != nil {
+			if err.Error() != "invalid slug" && err.Error() != "slug already exists" {
+				return err
+			}
+			it.reject(d.row, path+".slug", err.Error())
+			return nil
+		}
+
+		publication := d.Publication
+		if publication == "" {
+			publication = "draft"
+		}
+		var publishAt *time.Time
+		if publication == "published" {
+			publishAt = &it.now
+		}
+		err = it.tx.QueryRow(ctx, `
+			INSERT INTO dramas (title, slug, synopsis, year, status, source_url,
+			                    publication_status, publish_at, added_by, created_at, updated_at,
+			                    country, original_language)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10,
+			        COALESCE(NULLIF($11, ''), $13), COALESCE(NULLIF($12, ''), $14))
+			RETURNING id
+		`, d.Title, slug, d.Synopsis, d.Year, d.Status, d.SourceURL,
+			publication, publishAt, it.userID, it.now,
+			d.Country, d.Language, drama.DefaultCountry, drama.DefaultLanguage,
+		).Scan(&id)
+		if err != nil {
+			return err
+		}
+		if err := drama.RecordInitialStatus(ctx, it.tx, id, d.Status, it.editor(), it.now); err != nil {
+			return err
+		}
+		it.summary.DramasCreated++
+	} else {
+		// Status follows the same lifecycle as an editor's change
+		err = drama.ChangeStatus(ctx, it.tx, id, d.Status, it.editor(), it.now)
+		if err != nil {
+			if !strings.HasPrefix(err.Error(), "invalid status transition") {
+				return err
+			}
+			it.reject(d.row, path+".status", err.Error())
+			return nil
+		}
+
+		// Slug is the natural key and stays as it is; publication, country and
+		// language only change when given
+		_, err = it.tx.Exec(ctx, `
+			UPDATE dramas
+			SET title = $1, synopsis = $2, year = $3,
+			    source_url = $4, updated_at = $5,
+			    publication_status = COALESCE(NULLIF($6, ''), publication_status),
+			    publish_at = CASE WHEN $6 = 'published' AND publication_status <> 'published' THEN $5 ELSE publish_at END,
+			    country = COALESCE(NULLIF($8, ''), country),
+			    original_language = COALESCE(NULLIF($9, ''), original_language)
+			WHERE id = $7
+		`, d.Title, d.Synopsis, d.Year, d.SourceURL, it.now, d.Publication, id, d.Country, d.Language)
+		if err != nil {
+			return err
+		}
+		it.summary.DramasUpdated++
+	}
+	if err := drama.SetPoster(ctx, it.tx, id, d.PosterURL, it.now); err != nil {
+		return err
+	}
+
+	if len(genreIDs) > 0 {
+		if err := drama.SyncGenres(ctx, it.tx, id, genreIDs); err != nil {
+			return err
+		}
+	}
+	if len(d.Actors) > 0 {
+		if err := it.syncActors(ctx, id, d.Actors); err != nil {
+			return err
+		}
+	}
+
+	for i := range d.Seasons {
+		if err := it.importSeason(ctx, id, slug, d, &d.Seasons[i], fmt.Sprintf("%s.seasons[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+	if err := drama.SyncCounts(ctx, it.tx, id); err != nil {
+		return err
+	}
+
+	return drama.RecordRevision(ctx, it.tx, id, action, it.editor(), nil, it.now)
+}
+
+// resolveGenres maps genre slugs to ids, reporting the ones that do not exist
+func (it *importTx) resolveGenres(ctx context.Context, d *DramaRow, path string) ([]string, bool, error) {
+	if len(d.Genres) == 0 {
+		return nil, true, nil
+	}
+
+	rows, err := it.tx.Query(ctx, "SELECT id, slug FROM genres WHERE slug = ANY($1) AND deleted_at IS NULL", d.Genres)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	found := map[string]string{}
+	for rows.Next() {
+		var id, slug string
+		if err := rows.Scan(&id, &slug); err != nil {
+			return nil, false, err
+		}
+		found[slug] = id
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	ok := true
+	ids := make([]string, 0, len(d.Genres))
+	for _, slug := range d.Genres {
+		id, exists := found[slug]
+		if !exists {
+			it.reject(d.row, path+".genres", fmt.Sprintf("genre %q not found", slug))
+			ok = false
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, ok, nil
+}
+
+// findDrama matches by current or former slug, or by title and year without a slug
+func (it *importTx) findDrama(ctx context.Context, d *DramaRow) (string, string, bool, error) {
+	var query string
+	var args []interface{}
+	if d.Slug != "" {
+		query = `
+			SELECT d.id, d.slug, d.deleted_at IS NOT NULL FROM dramas d
+			JOIN (
+				SELECT id, 0 AS priority FROM dramas WHERE slug = $1
+				UNION ALL
+				SELECT entity_id, 1 FROM slug_redirects WHERE entity_type = 'drama' AND slug = $1
+			) s ON s.id = d.id
+			ORDER BY s.priority
+			LIMIT 1
+		`
+		args = []interface{}{validator.GenerateSlug(d.Slug)}
+	} else {
+		query = `
+			SELECT id, slug, deleted_at IS NOT NULL FROM dramas
+			WHERE lower(title) = lower($1) AND year = $2
+			ORDER BY deleted_at DESC NULLS FIRST, created_at
+			LIMIT 1
+		`
+		args = []interface{}{d.Title, d.Year}
+	}
+
+	var id, slug string
+	var trashed bool
+	err := it.tx.QueryRow(ctx, query, args...).Scan(&id, &slug, &trashed)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", "", false, nil
+		}
+		return "", "", false, err
+	}
+	return id, slug, trashed, nil
+}
+
+// newDramaSlug validates the bundle's slug or generates one from the title and
+// year; findDrama already ruled out an existing owner of a given slug
+func (it *importTx) newDramaSlug(ctx context.Context, d *DramaRow) (string, error) {
+	return validator.ResolveSlug(d.Slug, d.Title, "drama", []string{strconv.Itoa(d.Year)}, func(slug string) (bool, error) {
+		return drama.SlugTaken(ctx, it.tx, "drama", "dramas", slug)
+	})
+}
+
+// syncActors resolves the cast by name and syncs it in the order given, which
+// is also the billing order
+func (it *importTx) syncActors(ctx context.Context, dramaID string, actors []ActorRow) error {
+	cast := make([]drama.DramaActorReq, 0, len(actors))
+	for _, a := range actors {
+		id, err := it.findOrCreateActor(ctx, a.Name)
+		if err != nil {
+			return err
+		}
+		role := a.Role
+		if role == "" {
+			role = "main"
+		}
+		cast = append(cast, drama.DramaActorReq{ActorID: id, Role: role, Character: a.Character})
+	}
+	return drama.SyncCast(ctx, it.tx, dramaID, cast)
+}
+
+func (it *importTx) findOrCreateActor(ctx context.Context, name string) (string, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if id, ok := it.actors[key]; ok {
+		return id, nil
+	}
+
+	var id string
+	err := it.tx.QueryRow(ctx, `
+		SELECT id FROM actors WHERE lower(name) = $1 AND deleted_at IS NULL
+		ORDER BY created_at
+		LIMIT 1
+	`, key).Scan(&id)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return "", err
+	}
+
+	if id == "" {
+		base := validator.GenerateSlug(name)
+		if base == "" {
+			base = "actor"
+		}
+		slug, err := validator.UniqueSlug(base, nil, func(slug string) (bool, error) {
+			return drama.SlugTaken(ctx, it.tx, "actor", "actors", slug)
+		})
+		if err != nil {
+			return "", err
+		}
+		err = it.tx.QueryRow(ctx,
+			"INSERT INTO actors (name, slug, created_at) VALUES ($1, $2, $3) RETURNING id",
+			strings.TrimSpace(name), slug, it.now,
+		).Scan(&id)
+		if err != nil {
+			return "", err
+		}
+		it.summary.ActorsCreated++
+	}
+
+	it.actors[key] = id
+	return id, nil
+}
+
+func (it *importTx) importSeason(ctx context.Context, dramaID, dramaSlug string, d *DramaRow, s *SeasonRow, path string) error {
+	var id string
+	err := it.tx.QueryRow(ctx, "SELECT id FROM seasons WHERE drama_id = $1 AND season_number = $2", dramaID, s.SeasonNumber).Scan(&id)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	if id == "" {
+		err = it.tx.QueryRow(ctx,
+			"INSERT INTO seasons (drama_id, season_number, title, created_at) VALUES ($1, $2, $3, $4) RETURNING id",
+			dramaID, s.SeasonNumber, s.Title, it.now,
+		).Scan(&id)
+		if err != nil {
+			return err
+		}
+		it.summary.SeasonsCreated++
+	} else {
+		// Re-importing a trashed season brings it back
+		_, err = it.tx.Exec(ctx, "UPDATE seasons SET title = $1, deleted_at = NULL, updated_at = $2 WHERE id = $3", s.Title, it.now, id)
+		if err != nil {
+			return err
+		}
+		it.summary.SeasonsUpdated++
+	}
+
+	prefix := dramaSlug + "-s" + strconv.Itoa(s.SeasonNumber)
+	for i := range s.Episodes {
+		if err := it.importEpisode(ctx, id, prefix, d.Publication, &s.Episodes[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (it *importTx) importEpisode(ctx context.Context, seasonID, slugPrefix, publication string, e *EpisodeRow) error {
+	var id string
+	err := it.tx.QueryRow(ctx, "SELECT id FROM episodes WHERE season_id = $1 AND episode_number = $2", seasonID, e.EpisodeNumber).Scan(&id)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	if id != "" {
+		// Re-importing a trashed episode brings it back; slug and publication are left alone
+		_, err = it.tx.Exec(ctx, `
+			UPDATE episodes
+			SET title = $1, video_url = $2, duration = $3, thumbnail_url = $4, source_url = $5, deleted_at = NULL, updated_at = $6
+			WHERE id = $7
+		`, e.Title, e.VideoURL, e.Duration, e.ThumbnailURL, e.SourceURL, it.now, id)
+		if err != nil {
+			return err
+		}
+		it.summary.EpisodesUpdated++
+		return nil
+	}
+
+	slug, err := validator.UniqueSlug(slugPrefix+"-e"+strconv.Itoa(e.EpisodeNumber), nil, func(slug string) (bool, error) {
+		return drama.SlugTaken(ctx, it.tx, "episode", "episodes", slug)
+	})
+	if err != nil {
+		return err
+	}
+	if publication == "" {
+		publication = "draft"
+	}
+	var publishAt *time.Time
+	if publication == "published" {
+		publishAt = &it.now
+	}
+
+	_, err = it.tx.Exec(ctx, `
+		INSERT INTO episodes (season_id, episode_number, title, slug, video_url, duration, thumbnail_url, source_url,
+		                      publication_status, publish_at, added_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`, seasonID, e.EpisodeNumber, e.Title, slug, e.VideoURL, e.Duration, e.ThumbnailURL, e.SourceURL,
+		publication, publishAt, it.userID, it.now,
+	)
+	if err != nil {
+		return err
+	}
+	it.summary.EpisodesCreated++
+	return nil
+}
@@ -0,0 +1,287 @@
+package importer
+
+import (
+	"context"
+	"drakor-backend/pkg/validator"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type Service interface {
+	// Import parses a JSON or CSV bundle, validates it and applies it unless dryRun is set
+	Import(ctx context.Context, userID, format string, r io.Reader, dryRun bool) (*Report, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) Import(ctx context.Context, userID, format string, r io.Reader, dryRun bool) (*Report, error) {
+	var bundle *Bundle
+	var rowErrors []RowError
+	var err error
+
+	switch format {
+	case "json":
+		bundle, err = parseJSON(r)
+	case "csv":
+		bundle, rowErrors, err = parseCSV(r)
+	default:
+		return nil, errors.New("unsupported format")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{DryRun: dryRun, Errors: []RowError{}}
+	report.Errors = append(report.Errors, rowErrors...)
+	report.Errors = append(report.Errors, validateBundle(bundle)...)
+	if len(report.Errors) > 0 {
+		return report, nil
+	}
+
+	summary, refErrors, err := s.repo.Apply(ctx, userID, bundle, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	report.Summary = *summary
+	report.Errors = append(report.Errors, refErrors...)
+	report.Valid = len(report.Errors) == 0
+
+	return report, nil
+}
+
+func parseJSON(r io.Reader) (*Bundle, error) {
+	var bundle Bundle
+	if err := json.NewDecoder(r).Decode(&bundle); err != nil {
+		return nil, errors.New("invalid bundle: " + err.Error())
+	}
+	return &bundle, nil
+}
+
+// parseCSV groups flat episode records into a bundle. Recognised columns:
+//
+//	drama_slug, drama_title, year, status, synopsis, poster_url, source_url, publication_status,
+//	country, original_language, genres, actors, season_number, season_title,
+//	episode_number, episode_title, video_url, duration, thumbnail_url, episode_source_url
+//
+// Records are grouped by drama slug, or by title and year when the slug column is blank.
+// Drama and season columns may be left blank after the first record that sets them,
+// and a record without drama_slug or drama_title continues the previous drama.
+// genres is a "|" separated list of slugs, actors a "|" separated list of
+// "name:role:character" where role and character are optional.
+func parseCSV(r io.Reader) (*Bundle, []RowError, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, errors.New("invalid bundle: missing CSV header")
+	}
+	index := map[string]int{}
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := index["drama_title"]; !ok {
+		if _, ok := index["drama_slug"]; !ok {
+			return nil, nil, errors.New("invalid bundle: CSV needs a drama_title or drama_slug column")
+		}
+	}
+
+	bundle := &Bundle{}
+	dramas := map[string]*DramaRow{}
+	var order []string
+	var rowErrors []RowError
+	lastKey := ""
+
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, errors.New("invalid bundle: " + err.Error())
+		}
+
+		get := func(col string) string {
+			if i, ok := index[col]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		getInt := func(col string) int {
+			v := get(col)
+			if v == "" {
+				return 0
+			}
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				rowErrors = append(rowErrors, RowError{Row: row, Path: col, Field: col, Message: col + " must be a number"})
+			}
+			return n
+		}
+
+		slug, title, year := get("drama_slug"), get("drama_title"), getInt("year")
+		key := "slug:" + validator.GenerateSlug(slug)
+		if slug == "" {
+			key = "title:" + strings.ToLower(title) + "|" + strconv.Itoa(year)
+		}
+		if slug == "" && title == "" && lastKey != "" {
+			key = lastKey
+		}
+		lastKey = key
+
+		d, ok := dramas[key]
+		if !ok {
+			d = &DramaRow{Slug: slug, row: row}
+			dramas[key] = d
+			order = append(order, key)
+		}
+		fill := func(dst *string, col string) {
+			if v := get(col); v != "" && *dst == "" {
+				*dst = v
+			}
+		}
+		fill(&d.Title, "drama_title")
+		fill(&d.Status, "status")
+		fill(&d.Synopsis, "synopsis")
+		fill(&d.PosterURL, "poster_url")
+		fill(&d.SourceURL, "source_url")
+		fill(&d.Country, "country")
+		fill(&d.Language, "original_language")
+		fill(&d.Publication, "publication_status")
+		if d.Year == 0 {
+			d.Year = year
+		}
+		if v := get("genres"); v != "" && d.Genres == nil {
+			d.Genres = splitList(v)
+		}
+		if v := get("actors"); v != "" && d.Actors == nil {
+			for _, item := range splitList(v) {
+				// name:role:character, where role and character are optional
+				parts := strings.SplitN(item, ":", 3)
+				for len(parts) < 3 {
+					parts = append(parts, "")
+				}
+				d.Actors = append(d.Actors, ActorRow{
+					Name:      strings.TrimSpace(parts[0]),
+					Role:      strings.TrimSpace(parts[1]),
+					Character: strings.TrimSpace(parts[2]),
+				})
+			}
+		}
+
+		seasonNumber := getInt("season_number")
+		if seasonNumber == 0 {
+			continue
+		}
+		var season *SeasonRow
+		for i := range d.Seasons {
+			if d.Seasons[i].SeasonNumber == seasonNumber {
+				season = &d.Seasons[i]
+			}
+		}
+		if season == nil {
+			d.Seasons = append(d.Seasons, SeasonRow{SeasonNumber: seasonNumber, row: row})
+			season = &d.Seasons[len(d.Seasons)-1]
+		}
+		fill(&season.Title, "season_title")
+
+		if get("episode_number") == "" {
+			continue
+		}
+		season.Episodes = append(season.Episodes, EpisodeRow{
+			EpisodeNumber: getInt("episode_number"),
+			Title:         get("episode_title"),
+			VideoURL:      get("video_url"),
+			Duration:      getInt("duration"),
+			ThumbnailURL:  get("thumbnail_url"),
+			SourceURL:     get("episode_source_url"),
+			row:           row,
+		})
+	}
+
+	for _, key := range order {
+		bundle.Dramas = append(bundle.Dramas, *dramas[key])
+	}
+	return bundle, rowErrors, nil
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, "|") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// validateBundle runs the struct rules on every level and checks natural keys
+// are not repeated inside the bundle itself
+func validateBundle(bundle *Bundle) []RowError {
+	rowErrors := []RowError{}
+	check := func(row int, path string, data interface{}) {
+		for _, e := range validator.ValidateStruct(data) {
+			rowErrors = append(rowErrors, RowError{Row: row, Path: path, Field: e.Field, Message: e.Message})
+		}
+	}
+
+	if len(bundle.Dramas) == 0 {
+		return append(rowErrors, RowError{Path: "dramas", Message: "bundle has no dramas"})
+	}
+
+	dramaKeys := map[string]bool{}
+	for i, d := range bundle.Dramas {
+		path := fmt.Sprintf("dramas[%d]", i)
+		check(d.row, path, d)
+		if slug := validator.GenerateSlug(d.Slug); d.Slug != "" && (slug == "" || validator.IsUUID(slug)) {
+			rowErrors = append(rowErrors, RowError{Row: d.row, Path: path, Field: "slug", Message: "invalid slug"})
+		}
+
+		key := "slug:" + validator.GenerateSlug(d.Slug)
+		if d.Slug == "" {
+			key = "title:" + strings.ToLower(d.Title) + "|" + strconv.Itoa(d.Year)
+		}
+		if dramaKeys[key] {
+			rowErrors = append(rowErrors, RowError{Row: d.row, Path: path, Message: "drama appears more than once in the bundle"})
+		}
+		dramaKeys[key] = true
+
+		for j, a := range d.Actors {
+			check(d.row, fmt.Sprintf("%s.actors[%d]", path, j), a)
+		}
+
+		seasons := map[int]bool{}
+		for j, s := range d.Seasons {
+			seasonPath := fmt.Sprintf("%s.seasons[%d]", path, j)
+			check(s.row, seasonPath, s)
+			if seasons[s.SeasonNumber] {
+				rowErrors = append(rowErrors, RowError{Row: s.row, Path: seasonPath, Field: "season_number", Message: "season_number is repeated"})
+			}
+			seasons[s.SeasonNumber] = true
+
+			episodes := map[int]bool{}
+			for k, e := range s.Episodes {
+				episodePath := fmt.Sprintf("%s.episodes[%d]", seasonPath, k)
+				check(e.row, episodePath, e)
+				if episodes[e.EpisodeNumber] {
+					rowErrors = append(rowErrors, RowError{Row: e.row, Path: episodePath, Field: "episode_number", Message: "episode_number is repeated"})
+				}
+				episodes[e.EpisodeNumber] = true
+			}
+		}
+	}
+
+	return rowErrors
+}
@@ -0,0 +1,102 @@
+package media
+
+import (
+	"drakor-backend/internal/auth"
+	"drakor-backend/pkg/response"
+	"drakor-backend/pkg/validator"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetByDrama returns a drama's gallery grouped by type; ?type= narrows it to one type
+func (h *Handler) GetByDrama(c *gin.Context) {
+	mediaType := c.Query("type")
+	if mediaType != "" && !validType(mediaType) {
+		response.BadRequest(c, "Invalid media type", "validation_error")
+		return
+	}
+
+	items, err := h.service.GetByDrama(c.Request.Context(), c.Param("id"), mediaType, auth.CanPreview(c))
+	if err != nil {
+		h.writeError(c, err, "Failed to fetch media")
+		return
+	}
+	response.Success(c, "Media retrieved successfully", Group(items))
+}
+
+func (h *Handler) Create(c *gin.Context) {
+	var req MediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	m, err := h.service.Create(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		h.writeError(c, err, "Failed to add media")
+		return
+	}
+	response.Created(c, "Media added successfully", m)
+}
+
+func (h *Handler) Update(c *gin.Context) {
+	var req MediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	m, err := h.service.Update(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		h.writeError(c, err, "Failed to update media")
+		return
+	}
+	response.Success(c, "Media updated successfully", m)
+}
+
+func (h *Handler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		response.InternalError(c, "Failed to delete media", err.Error())
+		return
+	}
+	response.Success(c, "Media deleted successfully", nil)
+}
+
+func (h *Handler) writeError(c *gin.Context, err error, message string) {
+	switch err.Error() {
+	case "drama not found":
+		response.NotFound(c, "Drama not found")
+	case "media not found":
+		response.NotFound(c, "Media not found")
+	default:
+		response.InternalError(c, message, err.Error())
+	}
+}
+
+func validType(mediaType string) bool {
+	for _, t := range Types {
+		if t == mediaType {
+			return true
+		}
+	}
+	return false
+}
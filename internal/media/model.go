@@ -0,0 +1,38 @@
+package media
+
+import "time"
+
+// Types lists the media types in the order galleries show them
+var Types = []string{"poster", "backdrop", "still", "trailer", "teaser"}
+
+// Media is an image or video in a drama's gallery
+type Media struct {
+	ID          string    `json:"id"`
+	DramaID     string    `json:"drama_id"`
+	Type        string    `json:"type"` // 'poster', 'backdrop', 'still', 'trailer', 'teaser'
+	URL         string    `json:"url"`
+	Title       string    `json:"title,omitempty"`
+	Language    string    `json:"language,omitempty"`     // Language of any text in the image or video
+	AspectRatio string    `json:"aspect_ratio,omitempty"` // e.g. 2:3
+	Width       int       `json:"width,omitempty"`
+	Height      int       `json:"height,omitempty"`
+	Position    int       `json:"position"`
+	IsPrimary   bool      `json:"is_primary"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Gallery groups a drama's media by type, each in display order
+type Gallery map[string][]Media
+
+type MediaRequest struct {
+	Type        string `json:"type" validate:"required,oneof=poster backdrop still trailer teaser"`
+	URL         string `json:"url" validate:"required,url"`
+	Title       string `json:"title" validate:"omitempty,max=255"`
+	Language    string `json:"language" validate:"omitempty,min=2,max=10"`
+	AspectRatio string `json:"aspect_ratio" validate:"omitempty,max=10"`
+	Width       int    `json:"width" validate:"omitempty,min=1"`
+	Height      int    `json:"height" validate:"omitempty,min=1"`
+	Position    int    `json:"position" validate:"min=0"`
+	IsPrimary   bool   `json:"is_primary"` // Replaces the current primary of the same type
+}
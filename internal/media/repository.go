@@ -0,0 +1,268 @@
+package media
+
+import (
+	"context"
+	"drakor-backend/pkg/database"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type Repository interface {
+	// FindDrama resolves a drama UUID or slug, returning its id and publication status
+	FindDrama(ctx context.Context, id string) (dramaID, publication string, err error)
+	// FindByDrama lists a drama's media in display order, optionally of one type
+	FindByDrama(ctx context.Context, dramaID, mediaType string) ([]Media, error)
+	FindByID(ctx context.Context, id string) (*Media, error)
+	Create(ctx context.Context, m *Media) error
+	Update(ctx context.Context, m *Media) error
+	Delete(ctx context.Context, id string) error
+}
+
+type repository struct{}
+
+func NewRepository() Repository {
+	return &repository{}
+}
+
+const mediaColumns = `id, drama_id, type, url, title, language, aspect_ratio, width, height, position, is_primary, created_at, updated_at`
+
+// scanMedia reads a row selected with the drama_media columns in mediaColumns order
+func scanMedia(row pgx.Row) (*Media, error) {
+	var m Media
+	var title, language, aspect *string
+	var width, height *int
+	err := row.Scan(&m.ID, &m.DramaID, &m.Type, &m.URL, &title, &language, &aspect, &width, &height,
+		&m.Position, &m.IsPrimary, &m.CreatedAt, &m.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if title != nil {
+		m.Title = *title
+	}
+	if language != nil {
+		m.Language = *language
+	}
+	if aspect != nil {
+		m.AspectRatio = *aspect
+	}
+	if width != nil {
+		m.Width = *width
+	}
+	if height != nil {
+		m.Height = *height
+	}
+	return &m, nil
+}
+
+func (r *repository) FindDrama(ctx context.Context, id string) (string, string, error) {
+	db := database.GetDB()
+	if db == nil {
+		return "", "", errors.New("database not connected")
+	}
+
+	var dramaID, publication string
+	query := `SELECT id, publication_status FROM dramas WHERE (id::text = $1 OR slug = $1) AND deleted_at IS NULL`
+	if err := db.QueryRow(ctx, query, id).Scan(&dramaID, &publication); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", "", nil
+		}
+		return "", "", err
+	}
+	return dramaID, publication, nil
+}
+
+func (r *repository) FindByDrama(ctx context.Context, dramaID, mediaType string) ([]Media, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	query := "SELECT " + mediaColumns + " FROM drama_media WHERE drama_id = $1"
+	args := []interface{}{dramaID}
+	if mediaType != "" {
+		query += " AND type = $2"
+		args = append(args, mediaType)
+	}
+	query += " ORDER BY type, is_primary DESC, position, created_at"
+
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []Media{}
+	for rows.Next() {
+		m, err := scanMedia(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *m)
+	}
+	return items, rows.Err()
+}
+
+func (r *repository) FindByID(ctx context.Context, id string) (*Media, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	m, err := scanMedia(db.QueryRow(ctx, "SELECT "+mediaColumns+" FROM drama_media WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+func (r *repository) Create(ctx context.Context, m *Media) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if m.IsPrimary {
+		if err := clearPrimary(ctx, tx, m.DramaID, m.Type); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	query := `
+		INSERT INTO drama_media (drama_id, type, url, title, language, aspect_ratio, width, height, position, is_primary, created_at, updated_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, 0), NULLIF($8, 0), $9, $10, $11, $11)
+		RETURNING id, created_at, updated_at
+	`
+	err = tx.QueryRow(ctx, query, m.DramaID, m.Type, m.URL, m.Title, m.Language, m.AspectRatio, m.Width, m.Height,
+		m.Position, m.IsPrimary, now).Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := settle(ctx, tx, m.DramaID, m.Type); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *repository) Update(ctx context.Context, m *Media) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// The type may change, so both the old and the new type need settling
+	var oldType string
+	if err := tx.QueryRow(ctx, "SELECT type FROM drama_media WHERE id = $1 FOR UPDATE", m.ID).Scan(&oldType); err != nil {
+		return err
+	}
+
+	if m.IsPrimary {
+		if err := clearPrimary(ctx, tx, m.DramaID, m.Type); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		UPDATE drama_media
+		SET type = $1, url = $2, title = NULLIF($3, ''), language = NULLIF($4, ''), aspect_ratio = NULLIF($5, ''),
+		    width = NULLIF($6, 0), height = NULLIF($7, 0), position = $8, is_primary = $9, updated_at = $10
+		WHERE id = $11
+	`
+	_, err = tx.Exec(ctx, query, m.Type, m.URL, m.Title, m.Language, m.AspectRatio, m.Width, m.Height,
+		m.Position, m.IsPrimary, time.Now(), m.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := settle(ctx, tx, m.DramaID, oldType); err != nil {
+		return err
+	}
+	if oldType != m.Type {
+		if err := settle(ctx, tx, m.DramaID, m.Type); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *repository) Delete(ctx context.Context, id string) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var dramaID, mediaType string
+	err = tx.QueryRow(ctx, "DELETE FROM drama_media WHERE id = $1 RETURNING drama_id, type", id).Scan(&dramaID, &mediaType)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	if err := settle(ctx, tx, dramaID, mediaType); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func clearPrimary(ctx context.Context, tx pgx.Tx, dramaID, mediaType string) error {
+	_, err := tx.Exec(ctx, "UPDATE drama_media SET is_primary = FALSE WHERE drama_id = $1 AND type = $2 AND is_primary", dramaID, mediaType)
+	return err
+}
+
+// settle makes sure a type with media keeps a primary item, promoting the first one
+// when needed, and mirrors the primary poster into dramas.poster_url
+func settle(ctx context.Context, tx pgx.Tx, dramaID, mediaType string) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE drama_media SET is_primary = TRUE
+		WHERE id = (
+			SELECT id FROM drama_media WHERE drama_id = $1 AND type = $2
+			ORDER BY position, created_at LIMIT 1
+		)
+		AND NOT EXISTS (SELECT 1 FROM drama_media WHERE drama_id = $1 AND type = $2 AND is_primary)
+	`, dramaID, mediaType)
+	if err != nil {
+		return err
+	}
+
+	if mediaType == "poster" {
+		// Without any poster left the drama keeps its last poster_url
+		_, err = tx.Exec(ctx, `
+			UPDATE dramas d SET poster_url = m.url
+			FROM drama_media m
+			WHERE d.id = $1 AND m.drama_id = d.id AND m.type = 'poster' AND m.is_primary
+			  AND d.poster_url IS DISTINCT FROM m.url
+		`, dramaID)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE dramas SET updated_at = $1 WHERE id = $2", time.Now(), dramaID)
+	return err
+}
@@ -0,0 +1,96 @@
+package media
+
+import (
+	"context"
+	"errors"
+)
+
+type Service interface {
+	// GetByDrama lists a drama's media, optionally of one type; unless preview is set
+	// the drama must be published. It accepts the drama UUID or slug.
+	GetByDrama(ctx context.Context, dramaID, mediaType string, preview bool) ([]Media, error)
+	Create(ctx context.Context, dramaID string, req MediaRequest) (*Media, error)
+	Update(ctx context.Context, id string, req MediaRequest) (*Media, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// Group builds a gallery from media listed in display order
+func Group(items []Media) Gallery {
+	gallery := Gallery{}
+	for _, m := range items {
+		gallery[m.Type] = append(gallery[m.Type], m)
+	}
+	return gallery
+}
+
+func (s *service) GetByDrama(ctx context.Context, dramaID, mediaType string, preview bool) ([]Media, error) {
+	id, publication, err := s.repo.FindDrama(ctx, dramaID)
+	if err != nil {
+		return nil, err
+	}
+	if id == "" || (!preview && publication != "published") {
+		return nil, errors.New("drama not found")
+	}
+	return s.repo.FindByDrama(ctx, id, mediaType)
+}
+
+func (s *service) Create(ctx context.Context, dramaID string, req MediaRequest) (*Media, error) {
+	id, _, err := s.repo.FindDrama(ctx, dramaID)
+	if err != nil {
+		return nil, err
+	}
+	if id == "" {
+		return nil, errors.New("drama not found")
+	}
+
+	m := fromRequest(req)
+	m.DramaID = id
+	if err := s.repo.Create(ctx, m); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, m.ID)
+}
+
+func (s *service) Update(ctx context.Context, id string, req MediaRequest) (*Media, error) {
+	existing, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, errors.New("media not found")
+	}
+
+	m := fromRequest(req)
+	m.ID = existing.ID
+	m.DramaID = existing.DramaID
+	if err := s.repo.Update(ctx, m); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, m.ID)
+}
+
+func (s *service) Delete(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func fromRequest(req MediaRequest) *Media {
+	return &Media{
+		Type:        req.Type,
+		URL:         req.URL,
+		Title:       req.Title,
+		Language:    req.Language,
+		AspectRatio: req.AspectRatio,
+		Width:       req.Width,
+		Height:      req.Height,
+		Position:    req.Position,
+		IsPrimary:   req.IsPrimary,
+	}
+}
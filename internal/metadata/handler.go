@@ -0,0 +1,173 @@
+package metadata
+
+import (
+	"drakor-backend/pkg/response"
+	"drakor-backend/pkg/validator"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) Search(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		response.BadRequest(c, "Query is required", "missing_query")
+		return
+	}
+	year, _ := strconv.Atoi(c.Query("year"))
+
+	results, err := h.service.Search(c.Request.Context(), c.Param("provider"), query, year)
+	if err != nil {
+		h.providerError(c, err, "Failed to search titles")
+		return
+	}
+
+	response.Success(c, "Titles retrieved successfully", results)
+}
+
+func (h *Handler) Preview(c *gin.Context) {
+	preview, err := h.service.Preview(c.Request.Context(), c.Param("provider"), c.Param("externalId"))
+	if err != nil {
+		h.providerError(c, err, "Failed to preview title")
+		return
+	}
+
+	response.Success(c, "Title preview retrieved successfully", preview)
+}
+
+func (h *Handler) Import(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	drama, err := h.service.Import(c.Request.Context(), userID.(string), c.Param("provider"), c.Param("externalId"))
+	if err != nil {
+		if err.Error() == "title already imported" {
+			response.Error(c, http.StatusConflict, "Title has already been imported", "already_imported")
+			return
+		}
+		h.providerError(c, err, "Failed to import title")
+		return
+	}
+
+	response.Created(c, "Title imported as draft", drama)
+}
+
+func (h *Handler) Link(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	var req LinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	drama, err := h.service.Link(c.Request.Context(), userID.(string), c.Param("id"), req.Provider, req.ExternalID)
+	if err != nil {
+		if err.Error() == "title already imported" {
+			response.Error(c, http.StatusConflict, "Title is already linked to another drama", "already_imported")
+			return
+		}
+		h.providerError(c, err, "Failed to link drama")
+		return
+	}
+
+	response.Success(c, "Drama linked successfully", drama)
+}
+
+func (h *Handler) Refresh(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	drama, err := h.service.Refresh(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		h.providerError(c, err, "Failed to refresh drama")
+		return
+	}
+
+	response.Success(c, "Drama refreshed successfully", drama)
+}
+
+func (h *Handler) GetLink(c *gin.Context) {
+	link, err := h.service.GetLink(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err.Error() == "drama is not linked" {
+			response.NotFound(c, "Drama is not linked to a metadata provider")
+			return
+		}
+		response.InternalError(c, "Failed to retrieve metadata link", err.Error())
+		return
+	}
+
+	response.Success(c, "Metadata link retrieved successfully", link)
+}
+
+func (h *Handler) SetLocks(c *gin.Context) {
+	var req LocksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	link, err := h.service.SetLocks(c.Request.Context(), c.Param("id"), req.Fields)
+	if err != nil {
+		if err.Error() == "drama is not linked" {
+			response.NotFound(c, "Drama is not linked to a metadata provider")
+			return
+		}
+		response.InternalError(c, "Failed to update locked fields", err.Error())
+		return
+	}
+
+	response.Success(c, "Locked fields updated successfully", link)
+}
+
+// providerError maps lookup failures to 404 and upstream failures to 502
+func (h *Handler) providerError(c *gin.Context, err error, message string) {
+	if strings.HasPrefix(err.Error(), "provider error") {
+		response.Error(c, http.StatusBadGateway, message, err.Error())
+		return
+	}
+
+	switch err.Error() {
+	case "provider not found":
+		response.NotFound(c, "Metadata provider not found")
+	case "title not found":
+		response.NotFound(c, "Title not found")
+	case "drama not found":
+		response.NotFound(c, "Drama not found")
+	case "drama is not linked":
+		response.NotFound(c, "Drama is not linked to a metadata provider")
+	default:
+		response.InternalError(c, message, err.Error())
+	}
+}
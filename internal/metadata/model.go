@@ -0,0 +1,59 @@
+package metadata
+
+import (
+	"drakor-backend/internal/drama"
+	"time"
+)
+
+// Preview is an external title mapped onto the catalog. Drama is ready to be sent
+// to POST /api/dramas; Actors also lists cast that import would create.
+type Preview struct {
+	Provider        string                   `json:"provider"`
+	ExternalID      string                   `json:"external_id"`
+	Drama           drama.CreateDramaRequest `json:"drama"`
+	Actors          []MappedActor            `json:"actors"`
+	UnmatchedGenres []string                 `json:"unmatched_genres"` // Provider genres with no catalog genre
+	Seasons         []MappedSeason           `json:"seasons"`
+}
+
+type MappedActor struct {
+	Name      string `json:"name"`
+	Role      string `json:"role"` // 'main', 'support'
+	Character string `json:"character"`
+	PhotoURL  string `json:"photo_url"`
+	ActorID   string `json:"actor_id,omitempty"` // Empty when the actor will be created
+}
+
+type MappedSeason struct {
+	SeasonNumber int             `json:"season_number"`
+	Title        string          `json:"title"`
+	Episodes     []MappedEpisode `json:"episodes"`
+}
+
+// MappedEpisode has no video; imported episodes stay drafts until one is added
+type MappedEpisode struct {
+	EpisodeNumber int    `json:"episode_number"`
+	Title         string `json:"title"`
+	Duration      int    `json:"duration"` // in seconds
+	ThumbnailURL  string `json:"thumbnail_url"`
+}
+
+// Link records where a drama was imported from and which fields are locked
+type Link struct {
+	DramaID      string    `json:"drama_id"`
+	Provider     string    `json:"provider"`
+	ExternalID   string    `json:"external_id"`
+	LockedFields []string  `json:"locked_fields"`
+	SyncedAt     time.Time `json:"synced_at"`
+}
+
+// LinkRequest names the external title an existing drama is linked to
+type LinkRequest struct {
+	Provider   string `json:"provider" validate:"required"`
+	ExternalID string `json:"external_id" validate:"required"`
+}
+
+// LocksRequest lists the drama fields an editor protects from refreshes
+type LocksRequest struct {
+	Fields []string `json:"fields" validate:"dive,oneof=title synopsis poster_url year status country original_language genres actors episodes"`
+}
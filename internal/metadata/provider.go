@@ -0,0 +1,55 @@
+package metadata
+
+import "context"
+
+// Provider is an external metadata source such as TMDB
+type Provider interface {
+	// Search finds titles by name, optionally narrowed to a first-air year (0 for any)
+	Search(ctx context.Context, query string, year int) ([]SearchResult, error)
+	// Fetch loads a title with its cast, seasons and episodes.
+	// It returns errors.New("title not found") when the provider has no such title.
+	Fetch(ctx context.Context, externalID string) (*Title, error)
+}
+
+type SearchResult struct {
+	ExternalID    string `json:"external_id"`
+	Title         string `json:"title"`
+	OriginalTitle string `json:"original_title"`
+	Year          int    `json:"year"`
+	Synopsis      string `json:"synopsis"`
+	PosterURL     string `json:"poster_url"`
+}
+
+// Title is a provider-neutral view of an external drama
+type Title struct {
+	ExternalID string        `json:"external_id"`
+	Title      string        `json:"title"`
+	Synopsis   string        `json:"synopsis"`
+	PosterURL  string        `json:"poster_url"`
+	Year       int           `json:"year"`
+	Ended      bool          `json:"ended"`
+	Country    string        `json:"country"`  // ISO 3166-1 alpha-2 country of origin, when known
+	Language   string        `json:"language"` // ISO 639-1 original language, when known
+	Genres     []string      `json:"genres"`   // Genre names
+	Cast       []CastMember  `json:"cast"`     // In billing order
+	Seasons    []TitleSeason `json:"seasons"`
+}
+
+type CastMember struct {
+	Name      string `json:"name"`
+	Character string `json:"character"`
+	PhotoURL  string `json:"photo_url"`
+}
+
+type TitleSeason struct {
+	SeasonNumber int            `json:"season_number"`
+	Title        string         `json:"title"`
+	Episodes     []TitleEpisode `json:"episodes"`
+}
+
+type TitleEpisode struct {
+	EpisodeNumber int    `json:"episode_number"`
+	Title         string `json:"title"`
+	Duration      int    `json:"duration"` // in seconds
+	ThumbnailURL  string `json:"thumbnail_url"`
+}
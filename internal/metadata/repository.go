@@ -0,0 +1,402 @@
+package metadata
+
+import (
+	"context"
+	"drakor-backend/internal/drama"
+	"drakor-backend/pkg/database"
+	"drakor-backend/pkg/patch"
+	"drakor-backend/pkg/validator"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type Repository interface {
+	FindLink(ctx context.Context, dramaID string) (*Link, error)
+	FindLinkByExternal(ctx context.Context, provider, externalID string) (*Link, error)
+	SetLocks(ctx context.Context, dramaID string, fields []string) error
+	// MatchGenres maps provider genre names to catalog genre ids by slug or name
+	MatchGenres(ctx context.Context, names []string) (map[string]string, error)
+	// MatchActors maps lower-cased actor names to catalog actor ids
+	MatchActors(ctx context.Context, names []string) (map[string]string, error)
+	// Apply creates (dramaID empty) or refreshes a drama from a preview in one
+	// transaction, skipping locked fields, and links it to the external title
+	Apply(ctx context.Context, userID, dramaID string, preview *Preview, locked []string) (string, error)
+}
+
+type repository struct{}
+
+func NewRepository() Repository {
+	return &repository{}
+}
+
+func (r *repository) FindLink(ctx context.Context, dramaID string) (*Link, error) {
+	return r.findLink(ctx, "drama_id = $1", dramaID)
+}
+
+func (r *repository) FindLinkByExternal(ctx context.Context, provider, externalID string) (*Link, error) {
+	return r.findLink(ctx, "provider = $1 AND external_id = $2", provider, externalID)
+}
+
+func (r *repository) findLink(ctx context.Context, where string, args ...interface{}) (*Link, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	query := "SELECT drama_id, provider, external_id, locked_fields, synced_at FROM drama_metadata_links WHERE " + where
+	var l Link
+	err := db.QueryRow(ctx, query, args...).Scan(&l.DramaID, &l.Provider, &l.ExternalID, &l.LockedFields, &l.SyncedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (r *repository) SetLocks(ctx context.Context, dramaID string, fields []string) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+	if fields == nil {
+		fields = []string{}
+	}
+
+	tag, err := db.Exec(ctx, "UPDATE drama_metadata_links SET locked_fields = $1 WHERE drama_id = $2", fields, dramaID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("drama is not linked")
+	}
+	return nil
+}
+
+func (r *repository) MatchGenres(ctx context.Context, names []string) (map[string]string, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	slugs := make([]string, 0, len(names))
+	lowered := make([]string, 0, len(names))
+	for _, n := range names {
+		slugs = append(slugs, validator.GenerateSlug(n))
+		lowered = append(lowered, strings.ToLower(n))
+	}
+
+	rows, err := db.Query(ctx, `
+		SELECT id, name, slug FROM genres
+		WHERE deleted_at IS NULL AND (slug = ANY($1) OR lower(name) = ANY($2))
+	`, slugs, lowered)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bySlug := map[string]string{}
+	for rows.Next() {
+		var id, name, slug string
+		if err := rows.Scan(&id, &name, &slug); err != nil {
+			return nil, err
+		}
+		bySlug[slug] = id
+		bySlug[validator.GenerateSlug(name)] = id
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	matched := map[string]string{}
+	for _, n := range names {
+		if id, ok := bySlug[validator.GenerateSlug(n)]; ok {
+			matched[n] = id
+		}
+	}
+	return matched, nil
+}
+
+func (r *repository) MatchActors(ctx context.Context, names []string) (map[string]string, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	lowered := make([]string, 0, len(names))
+	for _, n := range names {
+		lowered = append(lowered, strings.ToLower(n))
+	}
+
+	// Oldest actor wins when names are shared
+	rows, err := db.Query(ctx, `
+		SELECT DISTINCT ON (lower(name)) lower(name), id FROM actors
+		WHERE deleted_at IS NULL AND lower(name) = ANY($1)
+		ORDER BY lower(name), created_at
+	`, lowered)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matched := map[string]string{}
+	for rows.Next() {
+		var name, id string
+		if err := rows.Scan(&name, &id); err != nil {
+			return nil, err
+		}
+		matched[name] = id
+	}
+	return matched, rows.Err()
+}
+
+func (r *repository) Apply(ctx context.Context, userID, dramaID string, p *Preview, locked []string) (string, error) {
+	db := database.GetDB()
+	if db == nil {
+		return "", errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	var editedBy *string
+	if userID != "" {
+		editedBy = &userID
+	}
+	isLocked := map[string]bool{}
+	for _, f := range locked {
+		isLocked[f] = true
+	}
+	d := p.Drama
+
+	// 1. Drama
+	action := "update"
+	var slug string
+	if dramaID == "" {
+		action = "create"
+		base := validator.GenerateSlug(d.Title)
+		if base == "" {
+			base = "drama"
+		}
+		slug, err = validator.UniqueSlug(base, []string{strconv.Itoa(d.Year)}, func(s string) (bool, error) {
+			return drama.SlugTaken(ctx, tx, "drama", "dramas", s)
+		})
+		if err != nil {
+			return "", err
+		}
+
+		// Imported dramas start as drafts so editors can review them
+		err = tx.QueryRow(ctx, `
+			INSERT INTO dramas (title, slug, synopsis, year, status, source_url,
+			                    publication_status, added_by, created_at, updated_at, country, original_language)
+			VALUES ($1, $2, $3, $4, $5, $6, 'draft', $7, $8, $8,
+			        COALESCE(NULLIF($9, ''), $11), COALESCE(NULLIF($10, ''), $12))
+			RETURNING id
+		`, d.Title, slug, d.Synopsis, d.Year, d.Status, d.SourceURL, editedBy, now,
+			d.Country, d.Language, drama.DefaultCountry, drama.DefaultLanguage,
+		).Scan(&dramaID)
+		if err != nil {
+			return "", err
+		}
+		if err := drama.RecordInitialStatus(ctx, tx, dramaID, d.Status, userID, now); err != nil {
+			return "", err
+		}
+		if err := drama.SetPoster(ctx, tx, dramaID, d.PosterURL, now); err != nil {
+			return "", err
+		}
+	} else {
+		if err := tx.QueryRow(ctx, "SELECT slug FROM dramas WHERE id = $1", dramaID).Scan(&slug); err != nil {
+			return "", err
+		}
+
+		// Status follows the lifecycle like an editor's change; a transition it
+		// does not allow keeps the current status and the rest still syncs
+		if !isLocked["status"] {
+			err := drama.ChangeStatus(ctx, tx, dramaID, d.Status, userID, now)
+			if err != nil && !strings.HasPrefix(err.Error(), "invalid status transition") {
+				return "", err
+			}
+		}
+
+		fields := map[string]interface{}{
+			"title":      d.Title,
+			"synopsis":   d.Synopsis,
+			"year":       d.Year,
+			"updated_at": now,
+		}
+		// The provider may not know the country or language; keep ours then
+		if d.Country != "" {
+			fields["country"] = d.Country
+		}
+		if d.Language != "" {
+			fields["original_language"] = d.Language
+		}
+		for name := range isLocked {
+			delete(fields, name)
+		}
+		columns := []string{"title", "synopsis", "year", "country", "original_language", "updated_at"}
+		if err := patch.Update(ctx, tx, "dramas", columns, fields, dramaID); err != nil {
+			return "", err
+		}
+		if !isLocked["poster_url"] {
+			if err := drama.SetPoster(ctx, tx, dramaID, d.PosterURL, now); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	// 2. Genres (unmatched provider genres were already dropped)
+	if !isLocked["genres"] && len(d.GenreIDs) > 0 {
+		if err := drama.SyncGenres(ctx, tx, dramaID, d.GenreIDs); err != nil {
+			return "", err
+		}
+	}
+
+	// 3. Cast, creating actors the catalog does not know yet
+	if !isLocked["actors"] && len(p.Actors) > 0 {
+		cast := []drama.DramaActorReq{}
+		for _, a := range p.Actors {
+			id := a.ActorID
+			if id == "" {
+				id, err = createActor(ctx, tx, a, now)
+				if err != nil {
+					return "", err
+				}
+			}
+			cast = append(cast, drama.DramaActorReq{ActorID: id, Role: a.Role, Character: a.Character})
+		}
+		if err := drama.SyncCast(ctx, tx, dramaID, cast); err != nil {
+			return "", err
+		}
+	}
+
+	// 4. Seasons and episodes
+	if !isLocked["episodes"] {
+		for _, s := range p.Seasons {
+			if err := upsertSeason(ctx, tx, dramaID, slug, s, now); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	// 5. Link
+	_, err = tx.Exec(ctx, `
+		INSERT INTO drama_metadata_links (drama_id, provider, external_id, synced_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (drama_id) DO UPDATE SET provider = EXCLUDED.provider, external_id = EXCLUDED.external_id, synced_at = EXCLUDED.synced_at
+	`, dramaID, p.Provider, p.ExternalID, now)
+	if err != nil {
+		return "", err
+	}
+
+	// 6. Season and episode counts
+	if err := drama.SyncCounts(ctx, tx, dramaID); err != nil {
+		return "", err
+	}
+
+	// 7. Revision, so syncs show up in the drama history
+	if err := drama.RecordRevision(ctx, tx, dramaID, action, userID, nil, now); err != nil {
+		return "", err
+	}
+
+	return dramaID, tx.Commit(ctx)
+}
+
+func upsertSeason(ctx context.Context, tx pgx.Tx, dramaID, dramaSlug string, s MappedSeason, now time.Time) error {
+	var seasonID string
+	var trashed bool
+	// Season numbering is a deferrable constraint, which ON CONFLICT cannot use
+	err := tx.QueryRow(ctx, `
+		UPDATE seasons SET title = $3, updated_at = $4
+		WHERE drama_id = $1 AND season_number = $2
+		RETURNING id, deleted_at IS NOT NULL
+	`, dramaID, s.SeasonNumber, s.Title, now).Scan(&seasonID, &trashed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = tx.QueryRow(ctx, `
+			INSERT INTO seasons (drama_id, season_number, title, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $4)
+			RETURNING id
+		`, dramaID, s.SeasonNumber, s.Title, now).Scan(&seasonID)
+	}
+	if err != nil {
+		return err
+	}
+	// A season an editor moved to the trash stays there
+	if trashed {
+		return nil
+	}
+
+	prefix := dramaSlug + "-s" + strconv.Itoa(s.SeasonNumber)
+	for _, e := range s.Episodes {
+		// Existing episodes keep their video, slug and publication state
+		tag, err := tx.Exec(ctx, `
+			UPDATE episodes SET title = $1, duration = $2, thumbnail_url = $3, updated_at = $4
+			WHERE season_id = $5 AND episode_number = $6
+		`, e.Title, e.Duration, e.ThumbnailURL, now, seasonID, e.EpisodeNumber)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() > 0 {
+			continue
+		}
+
+		slug, err := validator.UniqueSlug(prefix+"-e"+strconv.Itoa(e.EpisodeNumber), nil, func(s string) (bool, error) {
+			return drama.SlugTaken(ctx, tx, "episode", "episodes", s)
+		})
+		if err != nil {
+			return err
+		}
+		// No video yet: the episode stays a draft until an editor adds one
+		_, err = tx.Exec(ctx, `
+			INSERT INTO episodes (season_id, episode_number, title, slug, video_url, duration, thumbnail_url,
+			                      publication_status, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, '', $5, $6, 'draft', $7, $7)
+		`, seasonID, e.EpisodeNumber, e.Title, slug, e.Duration, e.ThumbnailURL, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createActor(ctx context.Context, tx pgx.Tx, a MappedActor, now time.Time) (string, error) {
+	base := validator.GenerateSlug(a.Name)
+	if base == "" {
+		base = "actor"
+	}
+	slug, err := validator.UniqueSlug(base, nil, func(s string) (bool, error) {
+		return drama.SlugTaken(ctx, tx, "actor", "actors", s)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var photo *string
+	if a.PhotoURL != "" {
+		photo = &a.PhotoURL
+	}
+	var id string
+	err = tx.QueryRow(ctx,
+		"INSERT INTO actors (name, slug, photo_url, created_at) VALUES ($1, $2, $3, $4) RETURNING id",
+		a.Name, slug, photo, now,
+	).Scan(&id)
+	if err != nil || photo == nil {
+		return id, err
+	}
+
+	// The provider photo starts the actor's gallery as its primary photo
+	_, err = tx.Exec(ctx,
+		"INSERT INTO actor_photos (actor_id, url, is_primary, created_at) VALUES ($1, $2, TRUE, $3)",
+		id, *photo, now,
+	)
+	return id, err
+}
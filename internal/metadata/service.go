@@ -0,0 +1,288 @@
+package metadata
+
+import (
+	"context"
+	"drakor-backend/internal/drama"
+	"drakor-backend/pkg/validator"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// mainCastSize is how many top-billed cast members are mapped as main roles
+const mainCastSize = 4
+
+type Service interface {
+	Search(ctx context.Context, provider, query string, year int) ([]SearchResult, error)
+	// Preview maps an external title onto the catalog without saving anything
+	Preview(ctx context.Context, provider, externalID string) (*Preview, error)
+	// Import creates a draft drama from an external title and links it
+	Import(ctx context.Context, userID, provider, externalID string) (*drama.Drama, error)
+	// Link points an existing drama at an external title and fills it in from there
+	Link(ctx context.Context, userID, dramaID, provider, externalID string) (*drama.Drama, error)
+	// Refresh re-reads the linked title and updates every field that is not locked
+	Refresh(ctx context.Context, userID, dramaID string) (*drama.Drama, error)
+	GetLink(ctx context.Context, dramaID string) (*Link, error)
+	SetLocks(ctx context.Context, dramaID string, fields []string) (*Link, error)
+}
+
+type service struct {
+	repo      Repository
+	dramaRepo drama.Repository
+	providers map[string]Provider
+}
+
+func NewService(repo Repository, dramaRepo drama.Repository, providers map[string]Provider) Service {
+	return &service{repo: repo, dramaRepo: dramaRepo, providers: providers}
+}
+
+func (s *service) provider(name string) (Provider, error) {
+	p, ok := s.providers[name]
+	if !ok {
+		return nil, errors.New("provider not found")
+	}
+	return p, nil
+}
+
+func (s *service) Search(ctx context.Context, provider, query string, year int) ([]SearchResult, error) {
+	p, err := s.provider(provider)
+	if err != nil {
+		return nil, err
+	}
+	results, err := p.Search(ctx, query, year)
+	if err != nil {
+		return nil, providerError(err)
+	}
+	return results, nil
+}
+
+func (s *service) Preview(ctx context.Context, provider, externalID string) (*Preview, error) {
+	p, err := s.provider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	title, err := p.Fetch(ctx, externalID)
+	if err != nil {
+		return nil, providerError(err)
+	}
+	return s.mapTitle(ctx, provider, title)
+}
+
+func (s *service) Import(ctx context.Context, userID, provider, externalID string) (*drama.Drama, error) {
+	existing, err := s.repo.FindLinkByExternal(ctx, provider, externalID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("title already imported")
+	}
+
+	preview, err := s.Preview(ctx, provider, externalID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.repo.Apply(ctx, userID, "", preview, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.dramaRepo.FindByID(ctx, id)
+}
+
+func (s *service) Link(ctx context.Context, userID, dramaID, provider, externalID string) (*drama.Drama, error) {
+	d, err := s.dramaRepo.FindByID(ctx, dramaID)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, errors.New("drama not found")
+	}
+
+	existing, err := s.repo.FindLinkByExternal(ctx, provider, externalID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.DramaID != dramaID {
+		return nil, errors.New("title already imported")
+	}
+
+	// Relinking keeps the fields an editor already locked
+	var locked []string
+	link, err := s.repo.FindLink(ctx, dramaID)
+	if err != nil {
+		return nil, err
+	}
+	if link != nil {
+		locked = link.LockedFields
+	}
+
+	preview, err := s.Preview(ctx, provider, externalID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.Apply(ctx, userID, dramaID, preview, locked); err != nil {
+		return nil, err
+	}
+	return s.dramaRepo.FindByID(ctx, dramaID)
+}
+
+func (s *service) Refresh(ctx context.Context, userID, dramaID string) (*drama.Drama, error) {
+	d, err := s.dramaRepo.FindByID(ctx, dramaID)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, errors.New("drama not found")
+	}
+
+	link, err := s.repo.FindLink(ctx, dramaID)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil {
+		return nil, errors.New("drama is not linked")
+	}
+
+	preview, err := s.Preview(ctx, link.Provider, link.ExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.Apply(ctx, userID, dramaID, preview, link.LockedFields); err != nil {
+		return nil, err
+	}
+	return s.dramaRepo.FindByID(ctx, dramaID)
+}
+
+func (s *service) GetLink(ctx context.Context, dramaID string) (*Link, error) {
+	link, err := s.repo.FindLink(ctx, dramaID)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil {
+		return nil, errors.New("drama is not linked")
+	}
+	return link, nil
+}
+
+func (s *service) SetLocks(ctx context.Context, dramaID string, fields []string) (*Link, error) {
+	// Drop duplicates so the stored list stays tidy
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, f := range fields {
+		if !seen[f] {
+			seen[f] = true
+			unique = append(unique, f)
+		}
+	}
+
+	if err := s.repo.SetLocks(ctx, dramaID, unique); err != nil {
+		return nil, err
+	}
+	return s.GetLink(ctx, dramaID)
+}
+
+// providerError marks upstream failures so handlers can tell them from our own
+func providerError(err error) error {
+	if err.Error() == "title not found" {
+		return err
+	}
+	return fmt.Errorf("provider error: %v", err)
+}
+
+// mapTitle turns a provider title into a create request, matching genres and
+// actors that already exist in the catalog
+func (s *service) mapTitle(ctx context.Context, provider string, t *Title) (*Preview, error) {
+	status := "ongoing"
+	if t.Ended {
+		status = "completed"
+	}
+	// Providers may use codes outside ISO 3166-1 / 639-1; those are left to the catalog default
+	country := strings.ToUpper(t.Country)
+	if validator.Validate.Var(country, "iso3166_1_alpha2") != nil {
+		country = ""
+	}
+	language := strings.ToLower(t.Language)
+	if validator.Validate.Var(language, "iso639_1") != nil {
+		language = ""
+	}
+	preview := &Preview{
+		Provider:   provider,
+		ExternalID: t.ExternalID,
+		Drama: drama.CreateDramaRequest{
+			Title:       t.Title,
+			Synopsis:    t.Synopsis,
+			PosterURL:   t.PosterURL,
+			Year:        t.Year,
+			Status:      status,
+			Country:     country,
+			Language:    language,
+			GenreIDs:    []string{},
+			Actors:      []drama.DramaActorReq{},
+			Publication: "draft",
+		},
+		Actors:          []MappedActor{},
+		UnmatchedGenres: []string{},
+		Seasons:         []MappedSeason{},
+	}
+
+	genres, err := s.repo.MatchGenres(ctx, t.Genres)
+	if err != nil {
+		return nil, err
+	}
+	seenGenres := map[string]bool{}
+	for _, name := range t.Genres {
+		id, ok := genres[name]
+		if !ok {
+			preview.UnmatchedGenres = append(preview.UnmatchedGenres, name)
+			continue
+		}
+		if !seenGenres[id] {
+			seenGenres[id] = true
+			preview.Drama.GenreIDs = append(preview.Drama.GenreIDs, id)
+		}
+	}
+
+	names := make([]string, 0, len(t.Cast))
+	for _, c := range t.Cast {
+		names = append(names, c.Name)
+	}
+	actors, err := s.repo.MatchActors(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	for i, c := range t.Cast {
+		role := "support"
+		if i < mainCastSize {
+			role = "main"
+		}
+		mapped := MappedActor{
+			Name:      c.Name,
+			Role:      role,
+			Character: c.Character,
+			PhotoURL:  c.PhotoURL,
+			ActorID:   actors[strings.ToLower(c.Name)],
+		}
+		preview.Actors = append(preview.Actors, mapped)
+		if mapped.ActorID != "" {
+			preview.Drama.Actors = append(preview.Drama.Actors, drama.DramaActorReq{ActorID: mapped.ActorID, Role: role, Character: c.Character})
+		}
+	}
+
+	for _, ts := range t.Seasons {
+		season := MappedSeason{SeasonNumber: ts.SeasonNumber, Title: ts.Title, Episodes: []MappedEpisode{}}
+		for _, e := range ts.Episodes {
+			season.Episodes = append(season.Episodes, MappedEpisode{
+				EpisodeNumber: e.EpisodeNumber,
+				Title:         e.Title,
+				Duration:      e.Duration,
+				ThumbnailURL:  e.ThumbnailURL,
+			})
+		}
+		preview.Seasons = append(preview.Seasons, season)
+	}
+
+	return preview, nil
+}
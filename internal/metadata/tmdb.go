@@ -0,0 +1,224 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxCast limits how much of the billed cast is mapped onto a drama
+const maxCast = 20
+
+// TMDB reads TV titles from a TMDB v3 compatible JSON API
+type TMDB struct {
+	baseURL      string // e.g. https://api.themoviedb.org/3
+	imageBaseURL string // e.g. https://image.tmdb.org/t/p/original
+	apiKey       string
+	client       *http.Client
+}
+
+// NewTMDB creates the adapter; apiKey may be empty for stub servers
+func NewTMDB(baseURL, imageBaseURL, apiKey string) *TMDB {
+	return &TMDB{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		imageBaseURL: strings.TrimRight(imageBaseURL, "/"),
+		apiKey:       apiKey,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type tmdbSearchResponse struct {
+	Results []struct {
+		ID           int    `json:"id"`
+		Name         string `json:"name"`
+		OriginalName string `json:"original_name"`
+		FirstAirDate string `json:"first_air_date"`
+		Overview     string `json:"overview"`
+		PosterPath   string `json:"poster_path"`
+	} `json:"results"`
+}
+
+type tmdbShow struct {
+	ID             int      `json:"id"`
+	Name           string   `json:"name"`
+	Overview       string   `json:"overview"`
+	PosterPath     string   `json:"poster_path"`
+	FirstAirDate   string   `json:"first_air_date"`
+	Status         string   `json:"status"`
+	EpisodeRunTime []int    `json:"episode_run_time"`
+	OriginCountry  []string `json:"origin_country"`
+	OriginalLang   string   `json:"original_language"`
+	Genres         []struct {
+		Name string `json:"name"`
+	} `json:"genres"`
+	Seasons []struct {
+		SeasonNumber int    `json:"season_number"`
+		Name         string `json:"name"`
+	} `json:"seasons"`
+	Credits struct {
+		Cast []struct {
+			Name        string `json:"name"`
+			Character   string `json:"character"`
+			Order       int    `json:"order"`
+			ProfilePath string `json:"profile_path"`
+		} `json:"cast"`
+	} `json:"credits"`
+}
+
+type tmdbSeason struct {
+	Episodes []struct {
+		EpisodeNumber int    `json:"episode_number"`
+		Name          string `json:"name"`
+		Runtime       *int   `json:"runtime"`
+		StillPath     string `json:"still_path"`
+	} `json:"episodes"`
+}
+
+func (t *TMDB) Search(ctx context.Context, query string, year int) ([]SearchResult, error) {
+	params := url.Values{"query": {query}}
+	if year > 0 {
+		params.Set("first_air_date_year", strconv.Itoa(year))
+	}
+
+	var resp tmdbSearchResponse
+	if err := t.get(ctx, "/search/tv", params, &resp); err != nil {
+		return nil, err
+	}
+
+	results := []SearchResult{}
+	for _, r := range resp.Results {
+		results = append(results, SearchResult{
+			ExternalID:    strconv.Itoa(r.ID),
+			Title:         r.Name,
+			OriginalTitle: r.OriginalName,
+			Year:          yearOf(r.FirstAirDate),
+			Synopsis:      r.Overview,
+			PosterURL:     t.image(r.PosterPath),
+		})
+	}
+	return results, nil
+}
+
+func (t *TMDB) Fetch(ctx context.Context, externalID string) (*Title, error) {
+	if _, err := strconv.Atoi(externalID); err != nil {
+		return nil, errors.New("title not found")
+	}
+
+	var show tmdbShow
+	params := url.Values{"append_to_response": {"credits"}}
+	if err := t.get(ctx, "/tv/"+externalID, params, &show); err != nil {
+		return nil, err
+	}
+
+	title := &Title{
+		ExternalID: strconv.Itoa(show.ID),
+		Title:      show.Name,
+		Synopsis:   show.Overview,
+		PosterURL:  t.image(show.PosterPath),
+		Year:       yearOf(show.FirstAirDate),
+		Ended:      show.Status == "Ended" || show.Status == "Canceled",
+		Language:   show.OriginalLang,
+		Genres:     []string{},
+		Cast:       []CastMember{},
+		Seasons:    []TitleSeason{},
+	}
+	if len(show.OriginCountry) > 0 {
+		title.Country = show.OriginCountry[0]
+	}
+	for _, g := range show.Genres {
+		title.Genres = append(title.Genres, g.Name)
+	}
+	// Credits come back in billing order
+	for i, c := range show.Credits.Cast {
+		if i == maxCast {
+			break
+		}
+		title.Cast = append(title.Cast, CastMember{Name: c.Name, Character: c.Character, PhotoURL: t.image(c.ProfilePath)})
+	}
+
+	defaultRuntime := 0
+	if len(show.EpisodeRunTime) > 0 {
+		defaultRuntime = show.EpisodeRunTime[0]
+	}
+	for _, s := range show.Seasons {
+		// Season 0 holds specials, which have no place in the catalog
+		if s.SeasonNumber < 1 {
+			continue
+		}
+		var detail tmdbSeason
+		if err := t.get(ctx, fmt.Sprintf("/tv/%s/season/%d", externalID, s.SeasonNumber), nil, &detail); err != nil {
+			return nil, err
+		}
+
+		season := TitleSeason{SeasonNumber: s.SeasonNumber, Title: s.Name, Episodes: []TitleEpisode{}}
+		for _, e := range detail.Episodes {
+			runtime := defaultRuntime
+			if e.Runtime != nil && *e.Runtime > 0 {
+				runtime = *e.Runtime
+			}
+			season.Episodes = append(season.Episodes, TitleEpisode{
+				EpisodeNumber: e.EpisodeNumber,
+				Title:         e.Name,
+				Duration:      runtime * 60,
+				ThumbnailURL:  t.image(e.StillPath),
+			})
+		}
+		title.Seasons = append(title.Seasons, season)
+	}
+
+	return title, nil
+}
+
+func (t *TMDB) get(ctx context.Context, path string, params url.Values, out interface{}) error {
+	if params == nil {
+		params = url.Values{}
+	}
+	if t.apiKey != "" {
+		params.Set("api_key", t.apiKey)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("tmdb request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errors.New("title not found")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("tmdb returned status %d for %s", resp.StatusCode, path)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("tmdb response could not be decoded: %v", err)
+	}
+	return nil
+}
+
+func (t *TMDB) image(path string) string {
+	if path == "" {
+		return ""
+	}
+	return t.imageBaseURL + path
+}
+
+// yearOf reads the year from a YYYY-MM-DD date, 0 if missing
+func yearOf(date string) int {
+	if len(date) < 4 {
+		return 0
+	}
+	year, _ := strconv.Atoi(date[:4])
+	return year
+}
@@ -0,0 +1,145 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testImageBase = "https://img.test/t/p/original"
+
+// newTMDBStub serves the fixtures in testdata/tmdb by request path and records
+// the query of each request
+func newTMDBStub(t *testing.T, routes map[string]string) (*TMDB, *[]string) {
+	t.Helper()
+	queries := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		fixture, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		body, err := os.ReadFile(filepath.Join("testdata", "tmdb", fixture))
+		if err != nil {
+			t.Errorf("read fixture %s: %v", fixture, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return NewTMDB(srv.URL+"/", testImageBase, "secret"), &queries
+}
+
+func TestTMDBSearch(t *testing.T) {
+	tmdb, queries := newTMDBStub(t, map[string]string{"/search/tv": "search_tv.json"})
+
+	results, err := tmdb.Search(context.Background(), "moonlit harbor", 2023)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+
+	first := results[0]
+	if first.ExternalID != "90001" || first.Title != "Moonlit Harbor" || first.OriginalTitle != "달빛 항구" || first.Year != 2023 {
+		t.Errorf("unexpected first result: %+v", first)
+	}
+	if first.PosterURL != testImageBase+"/p0st3rM00nl1tH4rb0r.jpg" {
+		t.Errorf("poster = %q", first.PosterURL)
+	}
+	if results[1].PosterURL != "" {
+		t.Errorf("missing poster should map to empty, got %q", results[1].PosterURL)
+	}
+
+	q := (*queries)[0]
+	for _, want := range []string{"query=moonlit+harbor", "first_air_date_year=2023", "api_key=secret"} {
+		if !strings.Contains(q, want) {
+			t.Errorf("query %q lacks %q", q, want)
+		}
+	}
+}
+
+func TestTMDBFetchMapsTitle(t *testing.T) {
+	tmdb, _ := newTMDBStub(t, map[string]string{
+		"/tv/90001":          "tv_90001.json",
+		"/tv/90001/season/1": "tv_90001_season_1.json",
+	})
+
+	title, err := tmdb.Fetch(context.Background(), "90001")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if title.ExternalID != "90001" || title.Title != "Moonlit Harbor" || title.Year != 2023 {
+		t.Errorf("unexpected title: %+v", title)
+	}
+	if !title.Ended {
+		t.Error("status Ended should map to Ended")
+	}
+	if title.Country != "KR" || title.Language != "ko" {
+		t.Errorf("country/language = %q/%q", title.Country, title.Language)
+	}
+	if strings.Join(title.Genres, ",") != "Drama,Romance,Crime" {
+		t.Errorf("genres = %v", title.Genres)
+	}
+
+	if len(title.Cast) != 5 {
+		t.Fatalf("got %d cast members, want 5", len(title.Cast))
+	}
+	if c := title.Cast[1]; c.Name != "Park Do-hyun" || c.Character != "Captain Kang Tae-oh" || c.PhotoURL != testImageBase+"/p4rkD0hyun.jpg" {
+		t.Errorf("unexpected cast member: %+v", c)
+	}
+	if title.Cast[2].PhotoURL != "" {
+		t.Errorf("missing profile should map to empty, got %q", title.Cast[2].PhotoURL)
+	}
+
+	// Specials (season 0) are skipped
+	if len(title.Seasons) != 1 || title.Seasons[0].SeasonNumber != 1 {
+		t.Fatalf("seasons = %+v", title.Seasons)
+	}
+	episodes := title.Seasons[0].Episodes
+	if len(episodes) != 4 {
+		t.Fatalf("got %d episodes, want 4", len(episodes))
+	}
+	if episodes[0].Duration != 72*60 || episodes[0].Title != "The Last Ferry" {
+		t.Errorf("unexpected episode: %+v", episodes[0])
+	}
+	// A missing runtime falls back to the show's episode run time
+	if episodes[2].Duration != 70*60 || episodes[2].ThumbnailURL != "" {
+		t.Errorf("unexpected episode: %+v", episodes[2])
+	}
+}
+
+func TestTMDBFetchNotFound(t *testing.T) {
+	tmdb, _ := newTMDBStub(t, map[string]string{})
+
+	for _, id := range []string{"404404", "not-a-number"} {
+		if _, err := tmdb.Fetch(context.Background(), id); err == nil || err.Error() != "title not found" {
+			t.Errorf("Fetch(%q) error = %v, want title not found", id, err)
+		}
+	}
+}
+
+func TestTMDBNon200(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"status_message":"Invalid API key"}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	tmdb := NewTMDB(srv.URL, testImageBase, "bad")
+
+	_, err := tmdb.Search(context.Background(), "moonlit", 0)
+	if err == nil || !strings.Contains(err.Error(), "status 401") {
+		t.Errorf("Search error = %v, want status 401", err)
+	}
+	if _, err := tmdb.Fetch(context.Background(), "90001"); err == nil || err.Error() == "title not found" {
+		t.Errorf("Fetch error = %v, want a provider error", err)
+	}
+}
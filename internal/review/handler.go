@@ -50,6 +50,10 @@ func (h *Handler) Create(c *gin.Context) {
 
 	review, err := h.service.Create(c.Request.Context(), userID.(string), req)
 	if err != nil {
+		if err.Error() == "drama not found" {
+			response.NotFound(c, "Drama not found")
+			return
+		}
 		if err.Error() == "review already exists" {
 			response.Error(c, http.StatusConflict, "You have already reviewed this drama", "conflict_error")
 			return
@@ -18,8 +18,14 @@ type Repository interface {
 	Delete(ctx context.Context, id string) error
 	FindByID(ctx context.Context, id string) (*Review, error)
 	UpdateDramaRating(ctx context.Context, dramaID string) error
+	// DramaExists reports whether the drama exists and is not in the trash
+	DramaExists(ctx context.Context, dramaID string) (bool, error)
 }
 
+// liveDrama holds while the drama bound to $1 is not in the trash; reviews of a
+// trashed drama are hidden until it is restored
+const liveDrama = "EXISTS (SELECT 1 FROM dramas d WHERE d.id = $1 AND d.deleted_at IS NULL)"
+
 type repository struct{}
 
 func NewRepository() Repository {
@@ -51,7 +57,7 @@ func (r *repository) GetByDramaID(ctx context.Context, dramaID string, limit, of
 
 	// Count total
 	var total int64
-	err := db.QueryRow(ctx, "SELECT COUNT(*) FROM reviews WHERE drama_id = $1", dramaID).Scan(&total)
+	err := db.QueryRow(ctx, "SELECT COUNT(*) FROM reviews WHERE drama_id = $1 AND "+liveDrama, dramaID).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -61,7 +67,7 @@ func (r *repository) GetByDramaID(ctx context.Context, dramaID string, limit, of
 		       u.id, u.name, u.avatar_url
 		FROM reviews r
 		JOIN users u ON r.user_id = u.id
-		WHERE r.drama_id = $1
+		WHERE r.drama_id = $1 AND ` + liveDrama + `
 		ORDER BY r.created_at DESC
 		LIMIT $2 OFFSET $3
 	`
@@ -92,6 +98,17 @@ func (r *repository) GetByDramaID(ctx context.Context, dramaID string, limit, of
 	return reviews, total, nil
 }
 
+func (r *repository) DramaExists(ctx context.Context, dramaID string) (bool, error) {
+	db := database.GetDB()
+	if db == nil {
+		return false, errors.New("database not connected")
+	}
+
+	var exists bool
+	err := db.QueryRow(ctx, "SELECT "+liveDrama, dramaID).Scan(&exists)
+	return exists, err
+}
+
 func (r *repository) GetByUserAndDrama(ctx context.Context, userID, dramaID string) (*Review, error) {
 	db := database.GetDB()
 	if db == nil {
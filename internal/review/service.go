@@ -21,6 +21,14 @@ func NewService(repo Repository) Service {
 }
 
 func (s *service) Create(ctx context.Context, userID string, req CreateReviewRequest) (*Review, error) {
+	exists, err := s.repo.DramaExists(ctx, req.DramaID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.New("drama not found")
+	}
+
 	// Check if already reviewed
 	existing, err := s.repo.GetByUserAndDrama(ctx, userID, req.DramaID)
 	if err != nil {
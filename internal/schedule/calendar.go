@@ -0,0 +1,121 @@
+package schedule
+
+import (
+	"time"
+)
+
+// location resolves a slot timezone, falling back to the default for unknown names
+func location(tz string) *time.Location {
+	if tz == "" {
+		tz = DefaultTimezone
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// parseDate reads a YYYY-MM-DD date as midnight in loc; ok is false for empty or invalid dates
+func parseDate(value string, loc *time.Location) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, loc)
+	return t, err == nil
+}
+
+// at returns the slot's air time on the given day
+func (s Slot) at(day time.Time) time.Time {
+	clock, err := time.Parse("15:04", s.AirTime)
+	if err != nil {
+		return day
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, day.Location())
+}
+
+// Occurrences lists the broadcasts of a slot in [from, to), in order
+func (s Slot) Occurrences(from, to time.Time) []time.Time {
+	loc := location(s.Timezone)
+	start, ok := parseDate(s.StartDate, loc)
+	if !ok {
+		return nil
+	}
+	end, hasEnd := parseDate(s.EndDate, loc)
+
+	local := from.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	if day.Before(start) {
+		day = start
+	}
+	// Jump to the first day on the slot's weekday, then step a week at a time
+	day = day.AddDate(0, 0, (s.Weekday-int(day.Weekday())+7)%7)
+
+	var airings []time.Time
+	for ; day.Before(to); day = day.AddDate(0, 0, 7) {
+		if hasEnd && day.After(end) {
+			break
+		}
+		t := s.at(day)
+		if !t.Before(from) && t.Before(to) {
+			airings = append(airings, t)
+		}
+	}
+	return airings
+}
+
+// Final returns the last broadcast of a slot; ok is false while the run is open-ended
+// or when the slot never airs between its start and end date
+func (s Slot) Final() (time.Time, bool) {
+	loc := location(s.Timezone)
+	start, ok := parseDate(s.StartDate, loc)
+	if !ok {
+		return time.Time{}, false
+	}
+	end, ok := parseDate(s.EndDate, loc)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	day := end.AddDate(0, 0, -((int(end.Weekday()) - s.Weekday + 7) % 7))
+	if day.Before(start) {
+		return time.Time{}, false
+	}
+	return s.at(day), true
+}
+
+// Next returns the first broadcast at or after t across slots, nil when none is left
+func Next(slots []Slot, t time.Time) *time.Time {
+	var next *time.Time
+	for _, s := range slots {
+		from := t
+		if start, ok := parseDate(s.StartDate, location(s.Timezone)); ok && start.After(from) {
+			from = start
+		}
+		// A weekly slot airs at least once in any eight days that fall within its run
+		airings := s.Occurrences(from, from.AddDate(0, 0, 8))
+		if len(airings) > 0 && (next == nil || airings[0].Before(*next)) {
+			first := airings[0]
+			next = &first
+		}
+	}
+	return next
+}
+
+// finalAiring returns the last broadcast across slots; ok is false if any slot is
+// still open-ended
+func finalAiring(slots []Slot) (time.Time, bool) {
+	var final time.Time
+	if len(slots) == 0 {
+		return final, false
+	}
+	for _, s := range slots {
+		if s.EndDate == "" {
+			return time.Time{}, false
+		}
+		if t, ok := s.Final(); ok && t.After(final) {
+			final = t
+		}
+	}
+	return final, !final.IsZero()
+}
@@ -0,0 +1,94 @@
+package schedule
+
+import (
+	"drakor-backend/pkg/response"
+	"drakor-backend/pkg/validator"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetSchedule lists broadcasts between ?from= and ?to= (YYYY-MM-DD, both inclusive,
+// read in ?tz=); it defaults to the coming seven days
+func (h *Handler) GetSchedule(c *gin.Context) {
+	tz := c.DefaultQuery("tz", DefaultTimezone)
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		response.BadRequest(c, "Invalid timezone", "validation_error")
+		return
+	}
+
+	now := time.Now().In(loc)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if v := c.Query("from"); v != "" {
+		if from, err = time.ParseInLocation("2006-01-02", v, loc); err != nil {
+			response.BadRequest(c, "Invalid from date, expected YYYY-MM-DD", "validation_error")
+			return
+		}
+	}
+	to := from.AddDate(0, 0, 6)
+	if v := c.Query("to"); v != "" {
+		if to, err = time.ParseInLocation("2006-01-02", v, loc); err != nil {
+			response.BadRequest(c, "Invalid to date, expected YYYY-MM-DD", "validation_error")
+			return
+		}
+	}
+
+	airings, err := h.service.GetSchedule(c.Request.Context(), from, to.AddDate(0, 0, 1))
+	if err != nil {
+		h.writeError(c, err, "Failed to fetch schedule")
+		return
+	}
+	response.Success(c, "Schedule retrieved successfully", airings)
+}
+
+// GetToday lists today's broadcasts, with "today" taken in ?tz= (Asia/Seoul by default)
+func (h *Handler) GetToday(c *gin.Context) {
+	airings, err := h.service.GetToday(c.Request.Context(), c.Query("tz"))
+	if err != nil {
+		h.writeError(c, err, "Failed to fetch schedule")
+		return
+	}
+	response.Success(c, "Today's schedule retrieved successfully", airings)
+}
+
+func (h *Handler) SetSlots(c *gin.Context) {
+	var req SetSlotsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	slots, err := h.service.SetSlots(c.Request.Context(), c.Param("id"), req.Slots)
+	if err != nil {
+		h.writeError(c, err, "Failed to update broadcast slots")
+		return
+	}
+	response.Success(c, "Broadcast slots updated successfully", slots)
+}
+
+func (h *Handler) writeError(c *gin.Context, err error, message string) {
+	switch err.Error() {
+	case "drama not found":
+		response.NotFound(c, "Drama not found")
+	case "invalid timezone", "end_date must not be before start_date",
+		"to must be after from", "schedule window is too long":
+		response.BadRequest(c, err.Error(), "validation_error")
+	default:
+		response.InternalError(c, message, err.Error())
+	}
+}
@@ -0,0 +1,52 @@
+package schedule
+
+import "time"
+
+// DefaultTimezone is used for slots without a timezone and for "today"
+const DefaultTimezone = "Asia/Seoul"
+
+// Slot is a weekly broadcast slot of a drama, e.g. every Saturday 21:00 KST
+type Slot struct {
+	ID        string    `json:"id"`
+	DramaID   string    `json:"drama_id"`
+	Weekday   int       `json:"weekday"`  // 0 = Sunday ... 6 = Saturday
+	AirTime   string    `json:"air_time"` // HH:MM, local to Timezone
+	Timezone  string    `json:"timezone"` // IANA name, e.g. Asia/Seoul
+	StartDate string    `json:"start_date"`
+	EndDate   string    `json:"end_date,omitempty"` // Empty while the run is open-ended
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Airing is one broadcast of a published drama within a schedule window
+type Airing struct {
+	DramaID   string    `json:"drama_id"`
+	Title     string    `json:"title"`
+	Slug      string    `json:"slug"`
+	PosterURL string    `json:"poster_url"`
+	Status    string    `json:"status"`
+	AirsAt    time.Time `json:"airs_at"`
+	Timezone  string    `json:"timezone"`
+}
+
+// scheduledDrama is a published drama together with its slots
+type scheduledDrama struct {
+	ID        string
+	Title     string
+	Slug      string
+	PosterURL string
+	Status    string
+	Slots     []Slot
+}
+
+type SlotReq struct {
+	Weekday   *int   `json:"weekday" validate:"required,min=0,max=6"`
+	AirTime   string `json:"air_time" validate:"required,datetime=15:04"`
+	Timezone  string `json:"timezone" validate:"omitempty,max=64"` // Defaults to Asia/Seoul
+	StartDate string `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate   string `json:"end_date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// SetSlotsRequest replaces every broadcast slot of a drama
+type SetSlotsRequest struct {
+	Slots []SlotReq `json:"slots" validate:"dive"`
+}
@@ -0,0 +1,229 @@
+package schedule
+
+import (
+	"context"
+	"drakor-backend/pkg/database"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type Repository interface {
+	DramaExists(ctx context.Context, dramaID string) (bool, error)
+	FindByDrama(ctx context.Context, dramaID string) ([]Slot, error)
+	SetSlots(ctx context.Context, dramaID string, slots []Slot) error
+	// FindAiring lists published dramas with slots running at some point between the two dates
+	FindAiring(ctx context.Context, fromDate, toDate string) ([]scheduledDrama, error)
+	// FindEnding lists ongoing dramas whose slots all have an end date
+	FindEnding(ctx context.Context) ([]scheduledDrama, error)
+	// MarkCompleted moves ongoing dramas to completed and records it in their status history
+	MarkCompleted(ctx context.Context, ids []string) (int64, error)
+}
+
+// StatusChanger moves a drama to a new status inside tx and records it in the
+// status history; drama.ChangeStatus is the one implementation
+type StatusChanger func(ctx context.Context, tx pgx.Tx, dramaID, status, changedBy string, now time.Time) error
+
+type repository struct {
+	changeStatus StatusChanger
+}
+
+// NewRepository takes the status writer as a function because the drama
+// package already imports schedule
+func NewRepository(changeStatus StatusChanger) Repository {
+	return &repository{changeStatus: changeStatus}
+}
+
+// slotColumns formats dates and times so they round-trip through Slot strings
+const slotColumns = `s.id, s.drama_id, s.weekday, to_char(s.air_time, 'HH24:MI'), s.timezone,
+	to_char(s.start_date, 'YYYY-MM-DD'), to_char(s.end_date, 'YYYY-MM-DD'), s.created_at`
+
+func (r *repository) DramaExists(ctx context.Context, dramaID string) (bool, error) {
+	db := database.GetDB()
+	if db == nil {
+		return false, errors.New("database not connected")
+	}
+
+	var exists bool
+	err := db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM dramas WHERE id = $1 AND deleted_at IS NULL)", dramaID).Scan(&exists)
+	return exists, err
+}
+
+func (r *repository) FindByDrama(ctx context.Context, dramaID string) ([]Slot, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	query := "SELECT " + slotColumns + " FROM broadcast_slots s WHERE s.drama_id = $1 ORDER BY s.start_date, s.weekday, s.air_time"
+	rows, err := db.Query(ctx, query, dramaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	slots := []Slot{}
+	for rows.Next() {
+		var s Slot
+		var endDate *string
+		if err := rows.Scan(&s.ID, &s.DramaID, &s.Weekday, &s.AirTime, &s.Timezone, &s.StartDate, &endDate, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		if endDate != nil {
+			s.EndDate = *endDate
+		}
+		slots = append(slots, s)
+	}
+	return slots, rows.Err()
+}
+
+func (r *repository) SetSlots(ctx context.Context, dramaID string, slots []Slot) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DELETE FROM broadcast_slots WHERE drama_id = $1", dramaID); err != nil {
+		return err
+	}
+	for _, s := range slots {
+		var endDate *string
+		if s.EndDate != "" {
+			endDate = &s.EndDate
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO broadcast_slots (drama_id, weekday, air_time, timezone, start_date, end_date)
+			VALUES ($1, $2, $3::time, $4, $5::date, $6::date)
+		`, dramaID, s.Weekday, s.AirTime, s.Timezone, s.StartDate, endDate)
+		if err != nil {
+			return fmt.Errorf("failed to add broadcast slot: %v", err)
+		}
+	}
+	if _, err := tx.Exec(ctx, "UPDATE dramas SET updated_at = $1 WHERE id = $2", time.Now(), dramaID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *repository) FindAiring(ctx context.Context, fromDate, toDate string) ([]scheduledDrama, error) {
+	// Dates are compared loosely here; slots keep their own timezone, so the exact
+	// window is applied by the caller
+	return r.findScheduled(ctx, `
+		d.publication_status = 'published'
+		AND s.start_date <= $2::date + 1
+		AND (s.end_date IS NULL OR s.end_date >= $1::date - 1)
+	`, fromDate, toDate)
+}
+
+func (r *repository) FindEnding(ctx context.Context) ([]scheduledDrama, error) {
+	return r.findScheduled(ctx, `
+		d.status = 'ongoing'
+		AND NOT EXISTS (SELECT 1 FROM broadcast_slots o WHERE o.drama_id = d.id AND o.end_date IS NULL)
+	`)
+}
+
+// findScheduled loads non-trashed dramas matching where, each with its matching slots
+func (r *repository) findScheduled(ctx context.Context, where string, args ...interface{}) ([]scheduledDrama, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	query := `
+		SELECT d.id, d.title, d.slug, d.poster_url, d.status, ` + slotColumns + `
+		FROM broadcast_slots s
+		JOIN dramas d ON d.id = s.drama_id
+		WHERE d.deleted_at IS NULL AND ` + where + `
+		ORDER BY d.id
+	`
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dramas := []scheduledDrama{}
+	for rows.Next() {
+		var d scheduledDrama
+		var s Slot
+		var poster, endDate *string
+		err := rows.Scan(&d.ID, &d.Title, &d.Slug, &poster, &d.Status,
+			&s.ID, &s.DramaID, &s.Weekday, &s.AirTime, &s.Timezone, &s.StartDate, &endDate, &s.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if endDate != nil {
+			s.EndDate = *endDate
+		}
+
+		// Rows are ordered by drama, so slots of the same drama are adjacent
+		if n := len(dramas); n > 0 && dramas[n-1].ID == d.ID {
+			dramas[n-1].Slots = append(dramas[n-1].Slots, s)
+			continue
+		}
+		if poster != nil {
+			d.PosterURL = *poster
+		}
+		d.Slots = []Slot{s}
+		dramas = append(dramas, d)
+	}
+	return dramas, rows.Err()
+}
+
+func (r *repository) MarkCompleted(ctx context.Context, ids []string) (int64, error) {
+	db := database.GetDB()
+	if db == nil {
+		return 0, errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the dramas still ongoing so an editor's change in between is not overwritten
+	rows, err := tx.Query(ctx, `
+		SELECT id FROM dramas
+		WHERE id = ANY($1) AND status = 'ongoing' AND deleted_at IS NULL
+		ORDER BY id
+		FOR UPDATE
+	`, ids)
+	if err != nil {
+		return 0, err
+	}
+	var ongoing []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ongoing = append(ongoing, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	// No editor is recorded, marking the change as automatic
+	now := time.Now()
+	for _, id := range ongoing {
+		if err := r.changeStatus(ctx, tx, id, "completed", "", now); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return int64(len(ongoing)), nil
+}
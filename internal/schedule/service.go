@@ -0,0 +1,162 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"time"
+)
+
+// maxWindow caps how many days GetSchedule covers
+const maxWindow = 31
+
+type Service interface {
+	// SetSlots replaces the broadcast slots of a drama and returns them
+	SetSlots(ctx context.Context, dramaID string, req []SlotReq) ([]Slot, error)
+	// GetSchedule lists broadcasts of published dramas in [from, to), in air order
+	GetSchedule(ctx context.Context, from, to time.Time) ([]Airing, error)
+	// GetToday lists broadcasts on the current day in the given timezone
+	GetToday(ctx context.Context, tz string) ([]Airing, error)
+	// CompleteFinished marks ongoing dramas completed once their final scheduled episode has aired
+	CompleteFinished(ctx context.Context, now time.Time) (int64, error)
+	// StartCompletionJob runs CompleteFinished every interval until ctx is cancelled
+	StartCompletionJob(ctx context.Context, interval time.Duration)
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) SetSlots(ctx context.Context, dramaID string, req []SlotReq) ([]Slot, error) {
+	exists, err := s.repo.DramaExists(ctx, dramaID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.New("drama not found")
+	}
+
+	slots := make([]Slot, 0, len(req))
+	for _, r := range req {
+		if r.Timezone == "" {
+			r.Timezone = DefaultTimezone
+		}
+		if _, err := time.LoadLocation(r.Timezone); err != nil {
+			return nil, errors.New("invalid timezone")
+		}
+		if r.EndDate != "" && r.EndDate < r.StartDate {
+			return nil, errors.New("end_date must not be before start_date")
+		}
+		slots = append(slots, Slot{
+			DramaID:   dramaID,
+			Weekday:   *r.Weekday,
+			AirTime:   r.AirTime,
+			Timezone:  r.Timezone,
+			StartDate: r.StartDate,
+			EndDate:   r.EndDate,
+		})
+	}
+
+	if err := s.repo.SetSlots(ctx, dramaID, slots); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByDrama(ctx, dramaID)
+}
+
+func (s *service) GetSchedule(ctx context.Context, from, to time.Time) ([]Airing, error) {
+	if !to.After(from) {
+		return nil, errors.New("to must be after from")
+	}
+	if to.Sub(from) > maxWindow*24*time.Hour {
+		return nil, errors.New("schedule window is too long")
+	}
+
+	dramas, err := s.repo.FindAiring(ctx, from.UTC().Format("2006-01-02"), to.UTC().Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	airings := []Airing{}
+	for _, d := range dramas {
+		for _, slot := range d.Slots {
+			for _, t := range slot.Occurrences(from, to) {
+				airings = append(airings, Airing{
+					DramaID:   d.ID,
+					Title:     d.Title,
+					Slug:      d.Slug,
+					PosterURL: d.PosterURL,
+					Status:    d.Status,
+					AirsAt:    t,
+					Timezone:  slot.Timezone,
+				})
+			}
+		}
+	}
+	sort.SliceStable(airings, func(i, j int) bool {
+		if !airings[i].AirsAt.Equal(airings[j].AirsAt) {
+			return airings[i].AirsAt.Before(airings[j].AirsAt)
+		}
+		return airings[i].Title < airings[j].Title
+	})
+	return airings, nil
+}
+
+func (s *service) GetToday(ctx context.Context, tz string) ([]Airing, error) {
+	if tz == "" {
+		tz = DefaultTimezone
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, errors.New("invalid timezone")
+	}
+
+	now := time.Now().In(loc)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	return s.GetSchedule(ctx, from, from.AddDate(0, 0, 1))
+}
+
+func (s *service) CompleteFinished(ctx context.Context, now time.Time) (int64, error) {
+	dramas, err := s.repo.FindEnding(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	ids := []string{}
+	for _, d := range dramas {
+		if final, ok := finalAiring(d.Slots); ok && !final.After(now) {
+			ids = append(ids, d.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return s.repo.MarkCompleted(ctx, ids)
+}
+
+func (s *service) StartCompletionJob(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				completed, err := s.CompleteFinished(ctx, time.Now())
+				if err != nil {
+					log.Printf("Broadcast completion job failed: %v", err)
+					continue
+				}
+				if completed > 0 {
+					log.Printf("🏁 Marked %d dramas completed after their final broadcast", completed)
+				}
+			}
+		}
+	}()
+}
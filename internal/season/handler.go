@@ -1,6 +1,7 @@
 package season
 
 import (
+	"drakor-backend/internal/auth"
 	"drakor-backend/pkg/response"
 	"drakor-backend/pkg/validator"
 	"net/http"
@@ -18,7 +19,7 @@ func NewHandler(service Service) *Handler {
 
 func (h *Handler) GetByDramaID(c *gin.Context) {
 	dramaID := c.Param("id")
-	seasons, err := h.service.GetByDramaID(c.Request.Context(), dramaID)
+	seasons, err := h.service.GetByDramaID(c.Request.Context(), dramaID, auth.CanPreview(c))
 	if err != nil {
 		response.InternalError(c, "Failed to fetch seasons", err.Error())
 		return
@@ -28,7 +29,7 @@ func (h *Handler) GetByDramaID(c *gin.Context) {
 
 func (h *Handler) GetByID(c *gin.Context) {
 	id := c.Param("id")
-	season, err := h.service.GetByID(c.Request.Context(), id)
+	season, err := h.service.GetByID(c.Request.Context(), id, auth.CanPreview(c))
 	if err != nil {
 		response.InternalError(c, "Failed to fetch season", err.Error())
 		return
@@ -54,6 +55,18 @@ func (h *Handler) Create(c *gin.Context) {
 
 	season, err := h.service.Create(c.Request.Context(), req)
 	if err != nil {
+		if err.Error() == "drama not found" {
+			response.NotFound(c, "Drama not found")
+			return
+		}
+		if err.Error() == "season number already exists in this drama" {
+			response.Error(c, http.StatusConflict, err.Error(), "season_number_exists")
+			return
+		}
+		if err.Error() == "season number belongs to a season in the trash" {
+			response.Error(c, http.StatusConflict, err.Error(), "season_in_trash")
+			return
+		}
 		response.InternalError(c, "Failed to create season", err.Error())
 		return
 	}
@@ -79,6 +92,47 @@ func (h *Handler) Update(c *gin.Context) {
 			response.NotFound(c, "Season not found")
 			return
 		}
+		if err.Error() == "season number already exists in this drama" {
+			response.Error(c, http.StatusConflict, err.Error(), "season_number_exists")
+			return
+		}
+		if err.Error() == "season number belongs to a season in the trash" {
+			response.Error(c, http.StatusConflict, err.Error(), "season_in_trash")
+			return
+		}
+		response.InternalError(c, "Failed to update season", err.Error())
+		return
+	}
+	response.Success(c, "Season updated successfully", season)
+}
+
+func (h *Handler) Patch(c *gin.Context) {
+	id := c.Param("id")
+	var req PatchSeasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	season, err := h.service.Patch(c.Request.Context(), id, req)
+	if err != nil {
+		if err.Error() == "season not found" {
+			response.NotFound(c, "Season not found")
+			return
+		}
+		if err.Error() == "season number already exists in this drama" {
+			response.Error(c, http.StatusConflict, err.Error(), "season_number_exists")
+			return
+		}
+		if err.Error() == "season number belongs to a season in the trash" {
+			response.Error(c, http.StatusConflict, err.Error(), "season_in_trash")
+			return
+		}
 		response.InternalError(c, "Failed to update season", err.Error())
 		return
 	}
@@ -93,3 +147,40 @@ func (h *Handler) Delete(c *gin.Context) {
 	}
 	response.Success(c, "Season deleted successfully", nil)
 }
+
+// Reorder renumbers the drama's seasons in the order given; every season must be listed
+func (h *Handler) Reorder(c *gin.Context) {
+	var req ReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	seasons, err := h.service.Reorder(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		if err.Error() == "drama not found" {
+			response.NotFound(c, "Drama not found")
+			return
+		}
+		if err.Error() == "season not found" {
+			response.NotFound(c, "Season not found")
+			return
+		}
+		if err.Error() == "duplicate season" {
+			response.BadRequest(c, "Each season may be listed once", "validation_error")
+			return
+		}
+		if err.Error() == "incomplete season list" {
+			response.BadRequest(c, "Every season of the drama must be listed", "validation_error")
+			return
+		}
+		response.InternalError(c, "Failed to reorder seasons", err.Error())
+		return
+	}
+	response.Success(c, "Seasons reordered successfully", seasons)
+}
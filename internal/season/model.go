@@ -1,6 +1,9 @@
 package season
 
-import "time"
+import (
+	"drakor-backend/pkg/patch"
+	"time"
+)
 
 type Season struct {
 	ID           string    `json:"id"`
@@ -8,6 +11,8 @@ type Season struct {
 	SeasonNumber int       `json:"season_number"`
 	Title        string    `json:"title"`
 	CreatedAt    time.Time `json:"created_at"`
+
+	dramaPublished bool // Seasons are only public while their drama is published
 }
 
 type CreateSeasonRequest struct {
@@ -20,3 +25,15 @@ type UpdateSeasonRequest struct {
 	SeasonNumber int    `json:"season_number" validate:"required,min=1"`
 	Title        string `json:"title" validate:"required"`
 }
+
+// PatchSeasonRequest follows JSON Merge Patch: absent members are left untouched
+type PatchSeasonRequest struct {
+	SeasonNumber patch.Field[int]    `json:"season_number" validate:"omitnil,min=1"`
+	Title        patch.Field[string] `json:"title" validate:"omitnil,required"`
+}
+
+// ReorderRequest lists every season of a drama in its new order; they are
+// renumbered from 1 in that order
+type ReorderRequest struct {
+	SeasonIDs []string `json:"season_ids" validate:"required,min=1,dive,uuid"`
+}
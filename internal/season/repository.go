@@ -2,7 +2,10 @@ package season
 
 import (
 	"context"
+	"drakor-backend/internal/drama"
+	"drakor-backend/internal/episode"
 	"drakor-backend/pkg/database"
+	"drakor-backend/pkg/patch"
 	"errors"
 	"time"
 
@@ -10,11 +13,18 @@ import (
 )
 
 type Repository interface {
-	FindByDramaID(ctx context.Context, dramaID string) ([]Season, error)
+	FindByDramaID(ctx context.Context, dramaID string, includeUnpublished bool) ([]Season, error)
 	FindByID(ctx context.Context, id string) (*Season, error)
 	Create(ctx context.Context, season *Season) error
 	Update(ctx context.Context, season *Season) error
+	Patch(ctx context.Context, id string, fields map[string]interface{}) error
 	Delete(ctx context.Context, id string) error
+	// SeasonNumbers maps every season number used in the drama, trashed seasons
+	// included, to whether that season is in the trash
+	SeasonNumbers(ctx context.Context, dramaID string) (map[int]bool, error)
+	// Reorder renumbers the drama's seasons from 1 in the order of ids, which must
+	// list every season not in the trash
+	Reorder(ctx context.Context, dramaID string, ids []string) error
 }
 
 type repository struct{}
@@ -23,14 +33,23 @@ func NewRepository() Repository {
 	return &repository{}
 }
 
-func (r *repository) FindByDramaID(ctx context.Context, dramaID string) ([]Season, error) {
+// FindByDramaID lists the seasons of a drama; unless includeUnpublished is set
+// nothing is returned for a drama that is not published
+func (r *repository) FindByDramaID(ctx context.Context, dramaID string, includeUnpublished bool) ([]Season, error) {
 	db := database.GetDB()
 	if db == nil {
 		return nil, errors.New("database not connected")
 	}
 
-	query := `SELECT id, drama_id, season_number, title, created_at FROM seasons WHERE drama_id = $1 ORDER BY season_number ASC`
-	rows, err := db.Query(ctx, query, dramaID)
+	query := `
+		SELECT s.id, s.drama_id, s.season_number, s.title, s.created_at
+		FROM seasons s
+		JOIN dramas d ON d.id = s.drama_id
+		WHERE s.drama_id = $1 AND s.deleted_at IS NULL AND d.deleted_at IS NULL
+		  AND ($2 OR d.publication_status = 'published')
+		ORDER BY s.season_number ASC
+	`
+	rows, err := db.Query(ctx, query, dramaID, includeUnpublished)
 	if err != nil {
 		return nil, err
 	}
@@ -54,9 +73,14 @@ func (r *repository) FindByID(ctx context.Context, id string) (*Season, error) {
 		return nil, errors.New("database not connected")
 	}
 
-	query := `SELECT id, drama_id, season_number, title, created_at FROM seasons WHERE id = $1`
+	query := `
+		SELECT s.id, s.drama_id, s.season_number, s.title, s.created_at, d.publication_status = 'published'
+		FROM seasons s
+		JOIN dramas d ON d.id = s.drama_id
+		WHERE s.id = $1 AND s.deleted_at IS NULL AND d.deleted_at IS NULL
+	`
 	var s Season
-	err := db.QueryRow(ctx, query, id).Scan(&s.ID, &s.DramaID, &s.SeasonNumber, &s.Title, &s.CreatedAt)
+	err := db.QueryRow(ctx, query, id).Scan(&s.ID, &s.DramaID, &s.SeasonNumber, &s.Title, &s.CreatedAt, &s.dramaPublished)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
@@ -72,28 +96,204 @@ func (r *repository) Create(ctx context.Context, season *Season) error {
 		return errors.New("database not connected")
 	}
 
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockDrama(ctx, tx, season.DramaID); err != nil {
+		return err
+	}
 	query := `INSERT INTO seasons (drama_id, season_number, title, created_at) VALUES ($1, $2, $3, $4) RETURNING id`
-	return db.QueryRow(ctx, query, season.DramaID, season.SeasonNumber, season.Title, time.Now()).Scan(&season.ID)
+	if err := tx.QueryRow(ctx, query, season.DramaID, season.SeasonNumber, season.Title, time.Now()).Scan(&season.ID); err != nil {
+		return err
+	}
+	if err := drama.SyncCounts(ctx, tx, season.DramaID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
 func (r *repository) Update(ctx context.Context, season *Season) error {
+	return r.Patch(ctx, season.ID, map[string]interface{}{"season_number": season.SeasonNumber, "title": season.Title})
+}
+
+func (r *repository) SeasonNumbers(ctx context.Context, dramaID string) (map[int]bool, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	rows, err := db.Query(ctx, "SELECT season_number, deleted_at IS NOT NULL FROM seasons WHERE drama_id = $1", dramaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	numbers := map[int]bool{}
+	for rows.Next() {
+		var number int
+		var trashed bool
+		if err := rows.Scan(&number, &trashed); err != nil {
+			return nil, err
+		}
+		numbers[number] = trashed
+	}
+	return numbers, rows.Err()
+}
+
+func (r *repository) Patch(ctx context.Context, id string, fields map[string]interface{}) error {
 	db := database.GetDB()
 	if db == nil {
 		return errors.New("database not connected")
 	}
+	if len(fields) == 0 {
+		return nil
+	}
 
-	query := `UPDATE seasons SET season_number = $1, title = $2 WHERE id = $3`
-	_, err := db.Exec(ctx, query, season.SeasonNumber, season.Title, season.ID)
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Generated episode slugs carry the season number, so they follow a renumbering
+	var auto map[string]string
+	if _, ok := fields["season_number"]; ok {
+		var dramaID string
+		if err := tx.QueryRow(ctx, "SELECT drama_id FROM seasons WHERE id = $1 FOR UPDATE", id).Scan(&dramaID); err != nil {
+			return err
+		}
+		if auto, err = dramaAutoSlugs(ctx, tx, dramaID); err != nil {
+			return err
+		}
+	}
+
+	fields["updated_at"] = time.Now()
+	if err := patch.Update(ctx, tx, "seasons", []string{"season_number", "title", "updated_at"}, fields, id); err != nil {
+		return err
+	}
+	if err := episode.RenumberSlugs(ctx, tx, auto); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// lockDrama locks a drama that is not in the trash, so it cannot be trashed while
+// its seasons change
+func lockDrama(ctx context.Context, tx pgx.Tx, dramaID string) error {
+	var locked int
+	err := tx.QueryRow(ctx, "SELECT 1 FROM dramas WHERE id::text = $1 AND deleted_at IS NULL FOR UPDATE", dramaID).Scan(&locked)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errors.New("drama not found")
+	}
 	return err
 }
 
+// dramaAutoSlugs finds the generated episode slugs in every season of the drama
+func dramaAutoSlugs(ctx context.Context, tx pgx.Tx, dramaID string) (map[string]string, error) {
+	var seasonIDs []string
+	if err := tx.QueryRow(ctx, "SELECT ARRAY(SELECT id::text FROM seasons WHERE drama_id = $1)", dramaID).Scan(&seasonIDs); err != nil {
+		return nil, err
+	}
+	return episode.AutoSlugs(ctx, tx, seasonIDs)
+}
+
 func (r *repository) Delete(ctx context.Context, id string) error {
 	db := database.GetDB()
 	if db == nil {
 		return errors.New("database not connected")
 	}
 
-	query := `DELETE FROM seasons WHERE id = $1`
-	_, err := db.Exec(ctx, query, id)
-	return err
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var dramaID string
+	query := `UPDATE seasons SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL RETURNING drama_id`
+	if err := tx.QueryRow(ctx, query, time.Now(), id).Scan(&dramaID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	if err := drama.SyncCounts(ctx, tx, dramaID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *repository) Reorder(ctx context.Context, dramaID string, ids []string) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockDrama(ctx, tx, dramaID); err != nil {
+		return err
+	}
+	// Numbers may collide halfway through; uniqueness is checked at commit
+	if _, err := tx.Exec(ctx, "SET CONSTRAINTS seasons_drama_id_season_number_key DEFERRED"); err != nil {
+		return err
+	}
+
+	var listed, live int
+	err = tx.QueryRow(ctx, `
+		SELECT COUNT(*) FILTER (WHERE id = ANY($2::uuid[])), COUNT(*)
+		FROM seasons
+		WHERE drama_id = $1 AND deleted_at IS NULL
+	`, dramaID, ids).Scan(&listed, &live)
+	if err != nil {
+		return err
+	}
+	if listed != len(ids) {
+		return errors.New("season not found")
+	}
+	if live != len(ids) {
+		return errors.New("incomplete season list")
+	}
+
+	// Generated episode slugs carry the season number, so they follow the new order
+	auto, err := dramaAutoSlugs(ctx, tx, dramaID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(ctx, `
+		UPDATE seasons s SET season_number = o.n, updated_at = $3
+		FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, n)
+		WHERE s.id = o.id AND s.drama_id = $1 AND s.season_number <> o.n
+	`, dramaID, ids, now)
+	if err != nil {
+		return err
+	}
+
+	// Trashed seasons still hold their numbers, so they move after the live ones
+	_, err = tx.Exec(ctx, `
+		UPDATE seasons s SET season_number = cardinality($2::uuid[]) + o.n, updated_at = $3
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY season_number) AS n
+			FROM seasons
+			WHERE drama_id = $1 AND deleted_at IS NOT NULL
+		) o
+		WHERE s.id = o.id AND s.season_number <> cardinality($2::uuid[]) + o.n
+	`, dramaID, ids, now)
+	if err != nil {
+		return err
+	}
+	if err := episode.RenumberSlugs(ctx, tx, auto); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
@@ -6,11 +6,14 @@ import (
 )
 
 type Service interface {
-	GetByDramaID(ctx context.Context, dramaID string) ([]Season, error)
-	GetByID(ctx context.Context, id string) (*Season, error)
+	GetByDramaID(ctx context.Context, dramaID string, preview bool) ([]Season, error)
+	GetByID(ctx context.Context, id string, preview bool) (*Season, error)
 	Create(ctx context.Context, req CreateSeasonRequest) (*Season, error)
 	Update(ctx context.Context, id string, req UpdateSeasonRequest) (*Season, error)
+	Patch(ctx context.Context, id string, req PatchSeasonRequest) (*Season, error)
 	Delete(ctx context.Context, id string) error
+	// Reorder renumbers every season of a drama and returns them in their new order
+	Reorder(ctx context.Context, dramaID string, req ReorderRequest) ([]Season, error)
 }
 
 type service struct {
@@ -21,15 +24,27 @@ func NewService(repo Repository) Service {
 	return &service{repo: repo}
 }
 
-func (s *service) GetByDramaID(ctx context.Context, dramaID string) ([]Season, error) {
-	return s.repo.FindByDramaID(ctx, dramaID)
+func (s *service) GetByDramaID(ctx context.Context, dramaID string, preview bool) ([]Season, error) {
+	return s.repo.FindByDramaID(ctx, dramaID, preview)
 }
 
-func (s *service) GetByID(ctx context.Context, id string) (*Season, error) {
-	return s.repo.FindByID(ctx, id)
+// GetByID hides seasons of unpublished dramas unless preview is set
+func (s *service) GetByID(ctx context.Context, id string, preview bool) (*Season, error) {
+	season, err := s.repo.FindByID(ctx, id)
+	if err != nil || season == nil {
+		return nil, err
+	}
+	if !preview && !season.dramaPublished {
+		return nil, nil
+	}
+	return season, nil
 }
 
 func (s *service) Create(ctx context.Context, req CreateSeasonRequest) (*Season, error) {
+	if err := s.checkNumber(ctx, req.DramaID, req.SeasonNumber); err != nil {
+		return nil, err
+	}
+
 	season := &Season{
 		DramaID:      req.DramaID,
 		SeasonNumber: req.SeasonNumber,
@@ -43,6 +58,22 @@ func (s *service) Create(ctx context.Context, req CreateSeasonRequest) (*Season,
 	return season, nil
 }
 
+// checkNumber rejects a season number already used in the drama. Trashed
+// seasons still hold their number, so the error points at the trash then.
+func (s *service) checkNumber(ctx context.Context, dramaID string, number int) error {
+	numbers, err := s.repo.SeasonNumbers(ctx, dramaID)
+	if err != nil {
+		return err
+	}
+	if trashed, exists := numbers[number]; exists {
+		if trashed {
+			return errors.New("season number belongs to a season in the trash")
+		}
+		return errors.New("season number already exists in this drama")
+	}
+	return nil
+}
+
 func (s *service) Update(ctx context.Context, id string, req UpdateSeasonRequest) (*Season, error) {
 	season, err := s.repo.FindByID(ctx, id)
 	if err != nil {
@@ -52,6 +83,12 @@ func (s *service) Update(ctx context.Context, id string, req UpdateSeasonRequest
 		return nil, errors.New("season not found")
 	}
 
+	if req.SeasonNumber != season.SeasonNumber {
+		if err := s.checkNumber(ctx, season.DramaID, req.SeasonNumber); err != nil {
+			return nil, err
+		}
+	}
+
 	season.SeasonNumber = req.SeasonNumber
 	season.Title = req.Title
 
@@ -62,6 +99,50 @@ func (s *service) Update(ctx context.Context, id string, req UpdateSeasonRequest
 	return season, nil
 }
 
+func (s *service) Patch(ctx context.Context, id string, req PatchSeasonRequest) (*Season, error) {
+	season, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if season == nil {
+		return nil, errors.New("season not found")
+	}
+
+	fields := map[string]interface{}{}
+	if req.SeasonNumber.Set {
+		if req.SeasonNumber.Value != season.SeasonNumber {
+			if err := s.checkNumber(ctx, season.DramaID, req.SeasonNumber.Value); err != nil {
+				return nil, err
+			}
+		}
+		fields["season_number"] = req.SeasonNumber.Value
+	}
+	if req.Title.Set {
+		fields["title"] = req.Title.Value
+	}
+
+	if err := s.repo.Patch(ctx, season.ID, fields); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(ctx, season.ID)
+}
+
 func (s *service) Delete(ctx context.Context, id string) error {
 	return s.repo.Delete(ctx, id)
 }
+
+func (s *service) Reorder(ctx context.Context, dramaID string, req ReorderRequest) ([]Season, error) {
+	seen := map[string]bool{}
+	for _, id := range req.SeasonIDs {
+		if seen[id] {
+			return nil, errors.New("duplicate season")
+		}
+		seen[id] = true
+	}
+
+	if err := s.repo.Reorder(ctx, dramaID, req.SeasonIDs); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByDramaID(ctx, dramaID, true)
+}
@@ -0,0 +1,187 @@
+package tag
+
+import (
+	"drakor-backend/pkg/response"
+	"drakor-backend/pkg/validator"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetAll lists tags with usage counts; ?category= filters, ?sort=popular orders by usage
+func (h *Handler) GetAll(c *gin.Context) {
+	tags, err := h.service.GetAll(c.Request.Context(), c.Query("category"), c.Query("sort"))
+	if err != nil {
+		response.InternalError(c, "Failed to fetch tags", err.Error())
+		return
+	}
+	response.Success(c, "Tags retrieved successfully", tags)
+}
+
+func (h *Handler) GetCategories(c *gin.Context) {
+	response.Success(c, "Tag categories retrieved successfully", Categories)
+}
+
+func (h *Handler) GetByID(c *gin.Context) {
+	tag, err := h.service.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.InternalError(c, "Failed to fetch tag", err.Error())
+		return
+	}
+	if tag == nil {
+		response.NotFound(c, "Tag not found")
+		return
+	}
+	response.Success(c, "Tag detail", tag)
+}
+
+// Autocomplete suggests tags for ?q=, matching the start of any word in the name
+func (h *Handler) Autocomplete(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	tags, err := h.service.Autocomplete(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		response.InternalError(c, "Failed to fetch tag suggestions", err.Error())
+		return
+	}
+	response.Success(c, "Tag suggestions retrieved successfully", tags)
+}
+
+func (h *Handler) Create(c *gin.Context) {
+	var req CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	tag, err := h.service.Create(c.Request.Context(), req)
+	if err != nil {
+		if err.Error() == "slug already exists" {
+			response.Error(c, http.StatusConflict, err.Error(), "slug_exists")
+			return
+		}
+		if err.Error() == "invalid slug" {
+			response.BadRequest(c, "Invalid slug", "validation_error")
+			return
+		}
+		response.InternalError(c, "Failed to create tag", err.Error())
+		return
+	}
+	response.Created(c, "Tag created successfully", tag)
+}
+
+func (h *Handler) Update(c *gin.Context) {
+	id := c.Param("id")
+	var req UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	tag, err := h.service.Update(c.Request.Context(), id, req)
+	if err != nil {
+		if err.Error() == "tag not found" {
+			response.NotFound(c, "Tag not found")
+			return
+		}
+		if err.Error() == "slug already exists" {
+			response.Error(c, http.StatusConflict, err.Error(), "slug_exists")
+			return
+		}
+		if err.Error() == "invalid slug" {
+			response.BadRequest(c, "Invalid slug", "validation_error")
+			return
+		}
+		response.InternalError(c, "Failed to update tag", err.Error())
+		return
+	}
+	response.Success(c, "Tag updated successfully", tag)
+}
+
+func (h *Handler) Patch(c *gin.Context) {
+	id := c.Param("id")
+	var req PatchTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	tag, err := h.service.Patch(c.Request.Context(), id, req)
+	if err != nil {
+		if err.Error() == "tag not found" {
+			response.NotFound(c, "Tag not found")
+			return
+		}
+		if err.Error() == "slug already exists" {
+			response.Error(c, http.StatusConflict, err.Error(), "slug_exists")
+			return
+		}
+		if err.Error() == "invalid slug" {
+			response.BadRequest(c, "Invalid slug", "validation_error")
+			return
+		}
+		response.InternalError(c, "Failed to update tag", err.Error())
+		return
+	}
+	response.Success(c, "Tag updated successfully", tag)
+}
+
+func (h *Handler) Delete(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		response.InternalError(c, "Failed to delete tag", err.Error())
+		return
+	}
+	response.Success(c, "Tag deleted successfully", nil)
+}
+
+func (h *Handler) SetDramaTags(c *gin.Context) {
+	var req SetDramaTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid input", err.Error())
+		return
+	}
+
+	if errors := validator.ValidateStruct(req); len(errors) > 0 {
+		response.Error(c, http.StatusBadRequest, "Validation failed", "validation_error")
+		return
+	}
+
+	tags, err := h.service.SetDramaTags(c.Request.Context(), c.Param("id"), req.TagIDs)
+	if err != nil {
+		if err.Error() == "drama not found" {
+			response.NotFound(c, "Drama not found")
+			return
+		}
+		if err.Error() == "tag not found" {
+			response.BadRequest(c, "Unknown tag", "tag_not_found")
+			return
+		}
+		response.InternalError(c, "Failed to update drama tags", err.Error())
+		return
+	}
+	response.Success(c, "Drama tags updated successfully", tags)
+}
@@ -0,0 +1,43 @@
+package tag
+
+import "drakor-backend/pkg/patch"
+
+// Categories group tags in filters and admin screens
+var Categories = []string{"trope", "theme", "setting", "mood", "other"}
+
+type Tag struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	Category string `json:"category"` // 'trope', 'theme', 'setting', 'mood', 'other'
+}
+
+// TagUsage is a tag with the number of published dramas carrying it
+type TagUsage struct {
+	Tag
+	UsageCount int `json:"usage_count"`
+}
+
+type CreateTagRequest struct {
+	Name     string `json:"name" validate:"required,min=2,max=100"`
+	Slug     string `json:"slug" validate:"omitempty,min=2,max=100"`                            // Auto-generated from name if empty
+	Category string `json:"category" validate:"omitempty,oneof=trope theme setting mood other"` // Defaults to trope
+}
+
+type UpdateTagRequest struct {
+	Name     string `json:"name" validate:"required,min=2,max=100"`
+	Slug     string `json:"slug" validate:"omitempty,min=2,max=100"`
+	Category string `json:"category" validate:"required,oneof=trope theme setting mood other"`
+}
+
+// PatchTagRequest follows JSON Merge Patch: absent members are left untouched
+type PatchTagRequest struct {
+	Name     patch.Field[string] `json:"name" validate:"omitnil,min=2,max=100"`
+	Slug     patch.Field[string] `json:"slug" validate:"omitnil,min=2,max=100"`
+	Category patch.Field[string] `json:"category" validate:"omitnil,oneof=trope theme setting mood other"`
+}
+
+// SetDramaTagsRequest replaces every tag on a drama
+type SetDramaTagsRequest struct {
+	TagIDs []string `json:"tag_ids" validate:"dive,uuid"`
+}
@@ -0,0 +1,258 @@
+package tag
+
+import (
+	"context"
+	"drakor-backend/pkg/database"
+	"drakor-backend/pkg/patch"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type Repository interface {
+	// FindAll lists tags with usage counts, optionally in one category, sorted by 'name' or 'popular'
+	FindAll(ctx context.Context, category, sort string) ([]TagUsage, error)
+	FindByID(ctx context.Context, id string) (*TagUsage, error)
+	FindBySlug(ctx context.Context, slug string) (*TagUsage, error)
+	// Autocomplete matches tags whose name or any word in it starts with prefix, most used first
+	Autocomplete(ctx context.Context, prefix string, limit int) ([]TagUsage, error)
+	Create(ctx context.Context, tag *Tag) error
+	Update(ctx context.Context, tag *Tag) error
+	Patch(ctx context.Context, id string, fields map[string]interface{}) error
+	Delete(ctx context.Context, id string) error
+	CountTags(ctx context.Context, ids []string) (int, error)
+	DramaExists(ctx context.Context, dramaID string) (bool, error)
+	FindByDrama(ctx context.Context, dramaID string) ([]Tag, error)
+	SetDramaTags(ctx context.Context, dramaID string, tagIDs []string) error
+}
+
+type repository struct{}
+
+func NewRepository() Repository {
+	return &repository{}
+}
+
+// usageQuery selects TagUsage columns; usage only counts dramas visitors can see
+const usageQuery = `
+	SELECT t.id, t.name, t.slug, t.category,
+	       (SELECT COUNT(*) FROM drama_tags dt JOIN dramas d ON d.id = dt.drama_id
+	        WHERE dt.tag_id = t.id AND d.deleted_at IS NULL AND d.publication_status = 'published') AS usage_count
+	FROM tags t
+`
+
+func scanUsages(rows pgx.Rows) ([]TagUsage, error) {
+	defer rows.Close()
+
+	tags := []TagUsage{}
+	for rows.Next() {
+		var t TagUsage
+		if err := rows.Scan(&t.ID, &t.Name, &t.Slug, &t.Category, &t.UsageCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+func (r *repository) FindAll(ctx context.Context, category, sort string) ([]TagUsage, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	query := usageQuery
+	args := []interface{}{}
+	if category != "" {
+		query += " WHERE t.category = $1"
+		args = append(args, category)
+	}
+	if sort == "popular" {
+		query += " ORDER BY usage_count DESC, t.name ASC"
+	} else {
+		query += " ORDER BY t.name ASC"
+	}
+
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanUsages(rows)
+}
+
+func (r *repository) FindByID(ctx context.Context, id string) (*TagUsage, error) {
+	return r.findOne(ctx, "t.id", id)
+}
+
+func (r *repository) FindBySlug(ctx context.Context, slug string) (*TagUsage, error) {
+	return r.findOne(ctx, "t.slug", slug)
+}
+
+func (r *repository) findOne(ctx context.Context, column, value string) (*TagUsage, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	var t TagUsage
+	err := db.QueryRow(ctx, usageQuery+" WHERE "+column+" = $1", value).Scan(&t.ID, &t.Name, &t.Slug, &t.Category, &t.UsageCount)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *repository) Autocomplete(ctx context.Context, prefix string, limit int) ([]TagUsage, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	// Escape LIKE wildcards typed by the user
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(strings.ToLower(prefix))
+	query := `SELECT * FROM (` + usageQuery + `
+		WHERE lower(t.name) LIKE $1 OR lower(t.name) LIKE $2
+	) matches
+	ORDER BY (lower(name) LIKE $1) DESC, usage_count DESC, name ASC
+	LIMIT $3`
+
+	rows, err := db.Query(ctx, query, escaped+"%", "% "+escaped+"%", limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanUsages(rows)
+}
+
+func (r *repository) Create(ctx context.Context, tag *Tag) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	query := `INSERT INTO tags (name, slug, category) VALUES ($1, $2, $3) RETURNING id`
+	return db.QueryRow(ctx, query, tag.Name, tag.Slug, tag.Category).Scan(&tag.ID)
+}
+
+func (r *repository) Update(ctx context.Context, tag *Tag) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	query := `UPDATE tags SET name = $1, slug = $2, category = $3 WHERE id = $4`
+	_, err := db.Exec(ctx, query, tag.Name, tag.Slug, tag.Category, tag.ID)
+	return err
+}
+
+func (r *repository) Patch(ctx context.Context, id string, fields map[string]interface{}) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+	return patch.Update(ctx, db, "tags", []string{"name", "slug", "category"}, fields, id)
+}
+
+// Delete removes the tag from every drama, bumping their updated_at
+func (r *repository) Delete(ctx context.Context, id string) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, "UPDATE dramas SET updated_at = $1 WHERE id IN (SELECT drama_id FROM drama_tags WHERE tag_id = $2)", time.Now(), id)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM tags WHERE id = $1", id); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *repository) CountTags(ctx context.Context, ids []string) (int, error) {
+	db := database.GetDB()
+	if db == nil {
+		return 0, errors.New("database not connected")
+	}
+
+	var count int
+	err := db.QueryRow(ctx, "SELECT COUNT(*) FROM tags WHERE id = ANY($1)", ids).Scan(&count)
+	return count, err
+}
+
+func (r *repository) DramaExists(ctx context.Context, dramaID string) (bool, error) {
+	db := database.GetDB()
+	if db == nil {
+		return false, errors.New("database not connected")
+	}
+
+	var exists bool
+	err := db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM dramas WHERE id = $1 AND deleted_at IS NULL)", dramaID).Scan(&exists)
+	return exists, err
+}
+
+func (r *repository) FindByDrama(ctx context.Context, dramaID string) ([]Tag, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, errors.New("database not connected")
+	}
+
+	rows, err := db.Query(ctx, `
+		SELECT t.id, t.name, t.slug, t.category
+		FROM tags t
+		JOIN drama_tags dt ON dt.tag_id = t.id
+		WHERE dt.drama_id = $1
+		ORDER BY t.category, t.name
+	`, dramaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []Tag{}
+	for rows.Next() {
+		var t Tag
+		if err := rows.Scan(&t.ID, &t.Name, &t.Slug, &t.Category); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+func (r *repository) SetDramaTags(ctx context.Context, dramaID string, tagIDs []string) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DELETE FROM drama_tags WHERE drama_id = $1", dramaID); err != nil {
+		return err
+	}
+	for _, tagID := range tagIDs {
+		if _, err := tx.Exec(ctx, "INSERT INTO drama_tags (drama_id, tag_id) VALUES ($1, $2)", dramaID, tagID); err != nil {
+			return fmt.Errorf("failed to add tag %s: %v", tagID, err)
+		}
+	}
+	if _, err := tx.Exec(ctx, "UPDATE dramas SET updated_at = $1 WHERE id = $2", time.Now(), dramaID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
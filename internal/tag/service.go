@@ -0,0 +1,163 @@
+package tag
+
+import (
+	"context"
+	"drakor-backend/pkg/validator"
+	"errors"
+)
+
+type Service interface {
+	GetAll(ctx context.Context, category, sort string) ([]TagUsage, error)
+	// GetByID accepts the tag UUID or slug
+	GetByID(ctx context.Context, id string) (*TagUsage, error)
+	Autocomplete(ctx context.Context, prefix string, limit int) ([]TagUsage, error)
+	Create(ctx context.Context, req CreateTagRequest) (*TagUsage, error)
+	Update(ctx context.Context, id string, req UpdateTagRequest) (*TagUsage, error)
+	Patch(ctx context.Context, id string, req PatchTagRequest) (*TagUsage, error)
+	Delete(ctx context.Context, id string) error
+	// SetDramaTags replaces the tags on a drama and returns them
+	SetDramaTags(ctx context.Context, dramaID string, tagIDs []string) ([]Tag, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) GetAll(ctx context.Context, category, sort string) ([]TagUsage, error) {
+	return s.repo.FindAll(ctx, category, sort)
+}
+
+func (s *service) GetByID(ctx context.Context, id string) (*TagUsage, error) {
+	if validator.IsUUID(id) {
+		return s.repo.FindByID(ctx, id)
+	}
+	return s.repo.FindBySlug(ctx, id)
+}
+
+func (s *service) Autocomplete(ctx context.Context, prefix string, limit int) ([]TagUsage, error) {
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+	if prefix == "" {
+		return []TagUsage{}, nil
+	}
+	return s.repo.Autocomplete(ctx, prefix, limit)
+}
+
+func (s *service) Create(ctx context.Context, req CreateTagRequest) (*TagUsage, error) {
+	slug, err := s.resolveSlug(ctx, req.Slug, req.Name, "")
+	if err != nil {
+		return nil, err
+	}
+	if req.Category == "" {
+		req.Category = "trope"
+	}
+
+	tag := &Tag{Name: req.Name, Slug: slug, Category: req.Category}
+	if err := s.repo.Create(ctx, tag); err != nil {
+		return nil, err
+	}
+	return &TagUsage{Tag: *tag}, nil
+}
+
+func (s *service) Update(ctx context.Context, id string, req UpdateTagRequest) (*TagUsage, error) {
+	existing, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, errors.New("tag not found")
+	}
+
+	slug, err := s.resolveSlug(ctx, req.Slug, req.Name, existing.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := &Tag{ID: existing.ID, Name: req.Name, Slug: slug, Category: req.Category}
+	if err := s.repo.Update(ctx, tag); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, tag.ID)
+}
+
+func (s *service) Patch(ctx context.Context, id string, req PatchTagRequest) (*TagUsage, error) {
+	existing, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, errors.New("tag not found")
+	}
+
+	fields := map[string]interface{}{}
+	if req.Name.Set {
+		fields["name"] = req.Name.Value
+	}
+	if req.Category.Set {
+		fields["category"] = req.Category.Value
+	}
+	if req.Slug.Set {
+		slug, err := s.resolveSlug(ctx, req.Slug.Value, "", existing.ID)
+		if err != nil {
+			return nil, err
+		}
+		if slug != existing.Slug {
+			fields["slug"] = slug
+		}
+	}
+
+	if err := s.repo.Patch(ctx, existing.ID, fields); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, existing.ID)
+}
+
+func (s *service) Delete(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *service) SetDramaTags(ctx context.Context, dramaID string, tagIDs []string) ([]Tag, error) {
+	exists, err := s.repo.DramaExists(ctx, dramaID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.New("drama not found")
+	}
+
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, id := range tagIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) > 0 {
+		count, err := s.repo.CountTags(ctx, unique)
+		if err != nil {
+			return nil, err
+		}
+		if count != len(unique) {
+			return nil, errors.New("tag not found")
+		}
+	}
+
+	if err := s.repo.SetDramaTags(ctx, dramaID, unique); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByDrama(ctx, dramaID)
+}
+
+// resolveSlug validates a requested slug or generates a unique one from the name
+func (s *service) resolveSlug(ctx context.Context, requested, name, excludeID string) (string, error) {
+	return validator.ResolveSlug(requested, name, "tag", nil, func(slug string) (bool, error) {
+		existing, err := s.repo.FindBySlug(ctx, slug)
+		return existing != nil && existing.ID != excludeID, err
+	})
+}
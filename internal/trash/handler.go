@@ -0,0 +1,81 @@
+package trash
+
+import (
+	"drakor-backend/pkg/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) GetAll(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	itemType := c.Query("type")
+
+	items, total, err := h.service.GetAll(c.Request.Context(), itemType, page, limit)
+	if err != nil {
+		if err.Error() == "invalid type" {
+			response.BadRequest(c, "Invalid type", "validation_error")
+			return
+		}
+		response.InternalError(c, "Failed to fetch trash", err.Error())
+		return
+	}
+	response.Paginated(c, items, total, page, limit)
+}
+
+func (h *Handler) Restore(c *gin.Context) {
+	itemType := c.Param("type")
+	id := c.Param("id")
+
+	if err := h.service.Restore(c.Request.Context(), itemType, id); err != nil {
+		switch err.Error() {
+		case "invalid type":
+			response.BadRequest(c, "Invalid type", "validation_error")
+		case "item not found":
+			response.NotFound(c, "Item not found in trash")
+		case "parent is in trash":
+			response.Error(c, http.StatusConflict, "Restore the parent item first", "parent_deleted")
+		default:
+			response.InternalError(c, "Failed to restore item", err.Error())
+		}
+		return
+	}
+	response.Success(c, "Item restored successfully", nil)
+}
+
+func (h *Handler) Purge(c *gin.Context) {
+	itemType := c.Param("type")
+	id := c.Param("id")
+
+	if err := h.service.Purge(c.Request.Context(), itemType, id); err != nil {
+		switch err.Error() {
+		case "invalid type":
+			response.BadRequest(c, "Invalid type", "validation_error")
+		case "item not found":
+			response.NotFound(c, "Item not found in trash")
+		default:
+			response.InternalError(c, "Failed to purge item", err.Error())
+		}
+		return
+	}
+	response.Success(c, "Item purged permanently", nil)
+}
+
+func (h *Handler) PurgeExpired(c *gin.Context) {
+	result, err := h.service.PurgeExpired(c.Request.Context())
+	if err != nil {
+		response.InternalError(c, "Failed to purge trash", err.Error())
+		return
+	}
+	response.Success(c, "Expired trash purged", result)
+}
@@ -0,0 +1,16 @@
+package trash
+
+import "time"
+
+type Item struct {
+	Type      string    `json:"type"` // 'drama', 'season', 'episode', 'actor', 'genre'
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	ParentID  string    `json:"parent_id,omitempty"` // Drama for seasons, season for episodes
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type PurgeResult struct {
+	Purged int64     `json:"purged"`
+	Before time.Time `json:"before"`
+}
@@ -0,0 +1,225 @@
+package trash
+
+import (
+	"context"
+	"drakor-backend/internal/drama"
+	"drakor-backend/pkg/database"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type Repository interface {
+	FindAll(ctx context.Context, itemType string, limit, offset int) ([]Item, int64, error)
+	Restore(ctx context.Context, itemType, id string) error
+	Purge(ctx context.Context, itemType, id string) error
+	PurgeBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// source describes how a trashable table is listed
+type source struct {
+	table     string
+	name      string // Display name expression
+	parent    string // Parent id column, empty if none
+	updatedAt bool   // Table has an updated_at column to bump on restore
+}
+
+var sources = map[string]source{
+	"drama":   {table: "dramas", name: "title", parent: "", updatedAt: true},
+	"season":  {table: "seasons", name: "COALESCE(title, 'Season ' || season_number)", parent: "drama_id", updatedAt: true},
+	"episode": {table: "episodes", name: "COALESCE(title, 'Episode ' || episode_number)", parent: "season_id", updatedAt: true},
+	"actor":   {table: "actors", name: "name", parent: ""},
+	"genre":   {table: "genres", name: "name", parent: ""},
+}
+
+// dramaOf finds the drama of items that count towards its seasons and episodes
+var dramaOf = map[string]string{
+	"season":  "SELECT drama_id FROM seasons WHERE id = $1",
+	"episode": "SELECT s.drama_id FROM episodes e JOIN seasons s ON s.id = e.season_id WHERE e.id = $1",
+}
+
+// ancestorsTrashed checks the whole parent chain of an item for trashed rows
+var ancestorsTrashed = map[string]string{
+	"season": `
+		SELECT d.deleted_at IS NOT NULL
+		FROM seasons s JOIN dramas d ON d.id = s.drama_id
+		WHERE s.id = $1`,
+	"episode": `
+		SELECT s.deleted_at IS NOT NULL OR d.deleted_at IS NOT NULL
+		FROM episodes e
+		JOIN seasons s ON s.id = e.season_id
+		JOIN dramas d ON d.id = s.drama_id
+		WHERE e.id = $1`,
+}
+
+// purgeOrder deletes children before parents so counts are not hidden by cascades
+var purgeOrder = []string{"episode", "season", "drama", "actor", "genre"}
+
+type repository struct{}
+
+func NewRepository() Repository {
+	return &repository{}
+}
+
+func (r *repository) FindAll(ctx context.Context, itemType string, limit, offset int) ([]Item, int64, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, 0, errors.New("database not connected")
+	}
+
+	// Build a UNION over the requested tables
+	union := ""
+	for _, t := range purgeOrder {
+		if itemType != "" && itemType != t {
+			continue
+		}
+		src := sources[t]
+		parent := "NULL::text"
+		if src.parent != "" {
+			parent = src.parent + "::text"
+		}
+		if union != "" {
+			union += " UNION ALL "
+		}
+		union += fmt.Sprintf(
+			"SELECT '%s' AS type, id::text AS id, %s AS name, %s AS parent_id, deleted_at FROM %s WHERE deleted_at IS NOT NULL",
+			t, src.name, parent, src.table,
+		)
+	}
+	if union == "" {
+		return nil, 0, errors.New("invalid type")
+	}
+
+	var total int64
+	if err := db.QueryRow(ctx, "SELECT COUNT(*) FROM ("+union+") t").Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT type, id, name, parent_id, deleted_at FROM (" + union + ") t ORDER BY deleted_at DESC LIMIT $1 OFFSET $2"
+	rows, err := db.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var items []Item
+	for rows.Next() {
+		var it Item
+		var parentID *string
+		if err := rows.Scan(&it.Type, &it.ID, &it.Name, &parentID, &it.DeletedAt); err != nil {
+			return nil, 0, err
+		}
+		if parentID != nil {
+			it.ParentID = *parentID
+		}
+		items = append(items, it)
+	}
+
+	return items, total, nil
+}
+
+func (r *repository) Restore(ctx context.Context, itemType, id string) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+	src, ok := sources[itemType]
+	if !ok {
+		return errors.New("invalid type")
+	}
+
+	// A child cannot come back while any of its ancestors is still in the trash
+	if query, ok := ancestorsTrashed[itemType]; ok {
+		var parentDeleted bool
+		err := db.QueryRow(ctx, query, id).Scan(&parentDeleted)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		if parentDeleted {
+			return errors.New("parent is in trash")
+		}
+	}
+
+	set := "deleted_at = NULL"
+	if src.updatedAt {
+		set += ", updated_at = CURRENT_TIMESTAMP"
+	}
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $1 AND deleted_at IS NOT NULL", src.table, set)
+	tag, err := tx.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("item not found")
+	}
+
+	// Restored seasons and episodes count towards their drama again
+	if dramaQuery, ok := dramaOf[itemType]; ok {
+		var dramaID string
+		if err := tx.QueryRow(ctx, dramaQuery, id).Scan(&dramaID); err != nil {
+			return err
+		}
+		if err := drama.SyncCounts(ctx, tx, dramaID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *repository) Purge(ctx context.Context, itemType, id string) error {
+	db := database.GetDB()
+	if db == nil {
+		return errors.New("database not connected")
+	}
+	src, ok := sources[itemType]
+	if !ok {
+		return errors.New("invalid type")
+	}
+
+	// Only trashed rows can be purged; cascades remove dependent rows
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND deleted_at IS NOT NULL", src.table)
+	tag, err := db.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("item not found")
+	}
+	return nil
+}
+
+func (r *repository) PurgeBefore(ctx context.Context, before time.Time) (int64, error) {
+	db := database.GetDB()
+	if db == nil {
+		return 0, errors.New("database not connected")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var purged int64
+	for _, t := range purgeOrder {
+		query := fmt.Sprintf("DELETE FROM %s WHERE deleted_at IS NOT NULL AND deleted_at < $1", sources[t].table)
+		tag, err := tx.Exec(ctx, query, before)
+		if err != nil {
+			return 0, err
+		}
+		purged += tag.RowsAffected()
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
@@ -0,0 +1,77 @@
+package trash
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+type Service interface {
+	GetAll(ctx context.Context, itemType string, page, limit int) ([]Item, int64, error)
+	Restore(ctx context.Context, itemType, id string) error
+	Purge(ctx context.Context, itemType, id string) error
+	PurgeExpired(ctx context.Context) (*PurgeResult, error)
+	StartPurgeJob(ctx context.Context, interval time.Duration)
+}
+
+type service struct {
+	repo      Repository
+	retention time.Duration
+}
+
+// NewService creates the trash service; items older than retention are purged permanently
+func NewService(repo Repository, retention time.Duration) Service {
+	return &service{repo: repo, retention: retention}
+}
+
+func (s *service) GetAll(ctx context.Context, itemType string, page, limit int) ([]Item, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+	return s.repo.FindAll(ctx, itemType, limit, offset)
+}
+
+func (s *service) Restore(ctx context.Context, itemType, id string) error {
+	return s.repo.Restore(ctx, itemType, id)
+}
+
+func (s *service) Purge(ctx context.Context, itemType, id string) error {
+	return s.repo.Purge(ctx, itemType, id)
+}
+
+func (s *service) PurgeExpired(ctx context.Context) (*PurgeResult, error) {
+	before := time.Now().Add(-s.retention)
+	purged, err := s.repo.PurgeBefore(ctx, before)
+	if err != nil {
+		return nil, err
+	}
+	return &PurgeResult{Purged: purged, Before: before}, nil
+}
+
+// StartPurgeJob purges expired trash every interval until ctx is cancelled
+func (s *service) StartPurgeJob(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				result, err := s.PurgeExpired(ctx)
+				if err != nil {
+					log.Printf("Trash purge failed: %v", err)
+					continue
+				}
+				if result.Purged > 0 {
+					log.Printf("🗑️  Purged %d trashed items deleted before %s", result.Purged, result.Before.Format(time.RFC3339))
+				}
+			}
+		}
+	}()
+}
@@ -51,7 +51,12 @@ func (r *repository) GetByUser(ctx context.Context, userID string, limit, offset
 
 	// Count total
 	var total int64
-	err := db.QueryRow(ctx, "SELECT COUNT(*) FROM watchlist WHERE user_id = $1", userID).Scan(&total)
+	countQuery := `
+		SELECT COUNT(*) FROM watchlist w
+		JOIN dramas d ON w.drama_id = d.id
+		WHERE w.user_id = $1 AND d.deleted_at IS NULL AND d.publication_status = 'published'
+	`
+	err := db.QueryRow(ctx, countQuery, userID).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -62,7 +67,7 @@ func (r *repository) GetByUser(ctx context.Context, userID string, limit, offset
 		       d.id, d.title, d.poster_url, d.year, d.rating, d.status
 		FROM watchlist w
 		JOIN dramas d ON w.drama_id = d.id
-		WHERE w.user_id = $1
+		WHERE w.user_id = $1 AND d.deleted_at IS NULL AND d.publication_status = 'published'
 		ORDER BY w.created_at DESC
 		LIMIT $2 OFFSET $3
 	`
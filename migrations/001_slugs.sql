@@ -0,0 +1,62 @@
+-- Human-readable slugs for dramas, actors and episodes
+-- Run after schema.sql
+
+-- 1. Slug columns
+ALTER TABLE dramas ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS slug VARCHAR(150);
+ALTER TABLE episodes ADD COLUMN IF NOT EXISTS slug VARCHAR(300);
+
+-- 2. Backfill existing rows (duplicates are disambiguated by year / creation order)
+UPDATE dramas d SET slug = s.slug
+FROM (
+    SELECT id,
+           CASE WHEN COUNT(*) OVER (PARTITION BY base) > 1
+                THEN base || '-' || COALESCE(year::text, 'x') || '-' || ROW_NUMBER() OVER (PARTITION BY base ORDER BY created_at)
+                ELSE base
+           END AS slug
+    FROM (
+        SELECT id, year, created_at,
+               COALESCE(NULLIF(trim(both '-' from regexp_replace(lower(title), '[^a-z0-9]+', '-', 'g')), ''), 'drama') AS base
+        FROM dramas WHERE slug IS NULL
+    ) b
+) s
+WHERE d.id = s.id;
+
+UPDATE actors a SET slug = s.slug
+FROM (
+    SELECT id,
+           CASE WHEN COUNT(*) OVER (PARTITION BY base) > 1
+                THEN base || '-' || ROW_NUMBER() OVER (PARTITION BY base ORDER BY created_at)
+                ELSE base
+           END AS slug
+    FROM (
+        SELECT id, created_at,
+               COALESCE(NULLIF(trim(both '-' from regexp_replace(lower(name), '[^a-z0-9]+', '-', 'g')), ''), 'actor') AS base
+        FROM actors WHERE slug IS NULL
+    ) b
+) s
+WHERE a.id = s.id;
+
+UPDATE episodes e SET slug = d.slug || '-s' || s.season_number || '-e' || e.episode_number
+FROM seasons s
+JOIN dramas d ON d.id = s.drama_id
+WHERE e.season_id = s.id AND e.slug IS NULL;
+
+ALTER TABLE dramas ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE actors ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE episodes ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dramas_slug ON dramas(slug);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_actors_slug ON actors(slug);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_episodes_slug ON episodes(slug);
+
+-- 3. Redirects from old slugs so renamed URLs keep working
+CREATE TABLE IF NOT EXISTS slug_redirects (
+    entity_type VARCHAR(20) NOT NULL CHECK (entity_type IN ('drama', 'actor', 'episode')),
+    slug VARCHAR(300) NOT NULL,
+    entity_id UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (entity_type, slug)
+);
+
+CREATE INDEX IF NOT EXISTS idx_slug_redirects_entity ON slug_redirects(entity_type, entity_id);
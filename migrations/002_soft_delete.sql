@@ -0,0 +1,15 @@
+-- Soft delete for catalog content
+-- Run after 001_slugs.sql
+
+ALTER TABLE dramas ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE seasons ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE episodes ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE genres ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Trash listing and purge job scan by deletion time
+CREATE INDEX IF NOT EXISTS idx_dramas_deleted_at ON dramas(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_seasons_deleted_at ON seasons(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_episodes_deleted_at ON episodes(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_actors_deleted_at ON actors(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_genres_deleted_at ON genres(deleted_at) WHERE deleted_at IS NOT NULL;
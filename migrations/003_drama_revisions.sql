@@ -0,0 +1,33 @@
+-- Drama revision history
+-- Run after 002_soft_delete.sql
+
+CREATE TABLE IF NOT EXISTS drama_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    drama_id UUID REFERENCES dramas(id) ON DELETE CASCADE,
+    revision_number INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('create', 'update', 'rollback')),
+    restored_from INTEGER, -- Revision number applied by a rollback
+    snapshot JSONB NOT NULL, -- Drama fields plus genre_ids and actors
+    edited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(drama_id, revision_number)
+);
+
+-- Seed a first revision for dramas that existed before history was tracked
+INSERT INTO drama_revisions (drama_id, revision_number, action, snapshot, edited_by, created_at)
+SELECT d.id, 1, 'create',
+       jsonb_build_object(
+           'title', d.title,
+           'slug', d.slug,
+           'synopsis', COALESCE(d.synopsis, ''),
+           'poster_url', COALESCE(d.poster_url, ''),
+           'year', d.year,
+           'total_seasons', d.total_seasons,
+           'status', d.status,
+           'source_url', COALESCE(d.source_url, ''),
+           'genre_ids', COALESCE((SELECT jsonb_agg(dg.genre_id ORDER BY dg.genre_id) FROM drama_genres dg WHERE dg.drama_id = d.id), '[]'::jsonb),
+           'actors', COALESCE((SELECT jsonb_agg(jsonb_build_object('actor_id', da.actor_id, 'role', da.role) ORDER BY da.actor_id) FROM drama_actors da WHERE da.drama_id = d.id), '[]'::jsonb)
+       ),
+       d.added_by, d.updated_at
+FROM dramas d
+WHERE NOT EXISTS (SELECT 1 FROM drama_revisions r WHERE r.drama_id = d.id);
@@ -0,0 +1,18 @@
+-- Draft/publish workflow with scheduled releases
+-- Run after 003_drama_revisions.sql
+
+-- Existing content stays public; new rows are created as drafts by the API
+ALTER TABLE dramas ADD COLUMN IF NOT EXISTS publication_status VARCHAR(20) NOT NULL DEFAULT 'published'
+    CHECK (publication_status IN ('draft', 'scheduled', 'published', 'unpublished'));
+ALTER TABLE dramas ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE episodes ADD COLUMN IF NOT EXISTS publication_status VARCHAR(20) NOT NULL DEFAULT 'published'
+    CHECK (publication_status IN ('draft', 'scheduled', 'published', 'unpublished'));
+ALTER TABLE episodes ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_dramas_publication ON dramas(publication_status);
+CREATE INDEX IF NOT EXISTS idx_episodes_publication ON episodes(publication_status);
+
+-- The scheduler looks up due releases by time
+CREATE INDEX IF NOT EXISTS idx_dramas_publish_at ON dramas(publish_at) WHERE publication_status = 'scheduled';
+CREATE INDEX IF NOT EXISTS idx_episodes_publish_at ON episodes(publish_at) WHERE publication_status = 'scheduled';
@@ -0,0 +1,15 @@
+-- Track edits on seasons and episodes so exports can pull incremental deltas
+-- Run after 004_publication.sql
+
+ALTER TABLE seasons ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE episodes ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE seasons SET updated_at = created_at WHERE updated_at IS NULL;
+UPDATE episodes SET updated_at = created_at WHERE updated_at IS NULL;
+
+ALTER TABLE seasons ALTER COLUMN updated_at SET DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE episodes ALTER COLUMN updated_at SET DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_dramas_updated_at ON dramas(updated_at);
+CREATE INDEX IF NOT EXISTS idx_seasons_updated_at ON seasons(updated_at);
+CREATE INDEX IF NOT EXISTS idx_episodes_updated_at ON episodes(updated_at);
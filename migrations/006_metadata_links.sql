@@ -0,0 +1,11 @@
+-- Links between dramas and external metadata providers
+-- Run after 005_catalog_updated_at.sql
+
+CREATE TABLE IF NOT EXISTS drama_metadata_links (
+    drama_id UUID PRIMARY KEY REFERENCES dramas(id) ON DELETE CASCADE,
+    provider VARCHAR(30) NOT NULL,
+    external_id VARCHAR(100) NOT NULL,
+    locked_fields TEXT[] NOT NULL DEFAULT '{}', -- Fields a refresh must not overwrite
+    synced_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(provider, external_id)
+);
@@ -0,0 +1,25 @@
+-- Curated collections for editorial rows
+-- Run after 006_metadata_links.sql
+
+CREATE TABLE IF NOT EXISTS collections (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    title VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) UNIQUE NOT NULL,
+    description TEXT,
+    type VARCHAR(20) NOT NULL DEFAULT 'manual' CHECK (type IN ('manual', 'rule')),
+    rule JSONB, -- Criteria for rule-based collections: genre_id, year_from, year_to, min_rating, sort, limit
+    visible_from TIMESTAMP WITH TIME ZONE, -- NULL means no start limit
+    visible_until TIMESTAMP WITH TIME ZONE, -- NULL means no end limit
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Ordered members of manual collections
+CREATE TABLE IF NOT EXISTS collection_dramas (
+    collection_id UUID REFERENCES collections(id) ON DELETE CASCADE,
+    drama_id UUID REFERENCES dramas(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (collection_id, drama_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_collection_dramas_position ON collection_dramas(collection_id, position);
@@ -0,0 +1,20 @@
+-- Free-form tags and tropes on dramas
+-- Run after 007_collections.sql
+
+CREATE TABLE IF NOT EXISTS tags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(100) UNIQUE NOT NULL,
+    category VARCHAR(20) NOT NULL DEFAULT 'trope' CHECK (category IN ('trope', 'theme', 'setting', 'mood', 'other')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS drama_tags (
+    drama_id UUID REFERENCES dramas(id) ON DELETE CASCADE,
+    tag_id UUID REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (drama_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_drama_tags_tag ON drama_tags(tag_id);
+-- Prefix search for autocomplete
+CREATE INDEX IF NOT EXISTS idx_tags_name_prefix ON tags(lower(name) text_pattern_ops);
@@ -0,0 +1,30 @@
+-- Crew credits, networks and production companies
+-- Run after 008_tags.sql
+
+-- Crew are people from the actors table, so someone who both acts and directs has one profile
+CREATE TABLE IF NOT EXISTS drama_crew (
+    drama_id UUID REFERENCES dramas(id) ON DELETE CASCADE,
+    actor_id UUID REFERENCES actors(id) ON DELETE CASCADE,
+    job VARCHAR(30) NOT NULL CHECK (job IN ('director', 'screenwriter', 'composer', 'producer')),
+    PRIMARY KEY (drama_id, actor_id, job)
+);
+
+CREATE INDEX IF NOT EXISTS idx_drama_crew_actor ON drama_crew(actor_id, job);
+
+-- Original broadcasting networks / streamers and production companies
+CREATE TABLE IF NOT EXISTS companies (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(150) NOT NULL,
+    slug VARCHAR(150) UNIQUE NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('network', 'production')),
+    logo_url TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS drama_companies (
+    drama_id UUID REFERENCES dramas(id) ON DELETE CASCADE,
+    company_id UUID REFERENCES companies(id) ON DELETE CASCADE,
+    PRIMARY KEY (drama_id, company_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_drama_companies_company ON drama_companies(company_id);
@@ -0,0 +1,17 @@
+-- Broadcast slots: when an ongoing drama airs new episodes
+-- Run after 009_crew_companies.sql
+
+-- weekday follows Go's time.Weekday (0 = Sunday); air_time is local to timezone
+CREATE TABLE IF NOT EXISTS broadcast_slots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    drama_id UUID NOT NULL REFERENCES dramas(id) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    air_time TIME NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Seoul',
+    start_date DATE NOT NULL,
+    end_date DATE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date IS NULL OR end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_broadcast_slots_drama ON broadcast_slots(drama_id);
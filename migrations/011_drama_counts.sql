@@ -0,0 +1,12 @@
+-- Derived season and episode counts on dramas
+-- Run after 010_broadcast_slots.sql
+
+-- total_seasons used to be typed in by hand; both counts now follow the
+-- non-trashed seasons and episodes (repair drift with cmd/reconcile)
+ALTER TABLE dramas ADD COLUMN IF NOT EXISTS episode_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE dramas ALTER COLUMN total_seasons SET DEFAULT 0;
+
+UPDATE dramas d
+SET total_seasons = (SELECT COUNT(*) FROM seasons s WHERE s.drama_id = d.id AND s.deleted_at IS NULL),
+    episode_count = (SELECT COUNT(*) FROM episodes e JOIN seasons s ON s.id = e.season_id
+                     WHERE s.drama_id = d.id AND s.deleted_at IS NULL AND e.deleted_at IS NULL);
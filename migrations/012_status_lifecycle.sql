@@ -0,0 +1,28 @@
+-- Extended drama lifecycle: upcoming, hiatus and cancelled, with a status history
+-- Run after 011_drama_counts.sql
+
+ALTER TABLE dramas DROP CONSTRAINT IF EXISTS dramas_status_check;
+ALTER TABLE dramas ADD CONSTRAINT dramas_status_check
+    CHECK (status IN ('upcoming', 'ongoing', 'hiatus', 'completed', 'cancelled'));
+
+-- Expected premiere of upcoming dramas, shown in "coming soon" listings
+ALTER TABLE dramas ADD COLUMN IF NOT EXISTS premiere_date DATE;
+CREATE INDEX IF NOT EXISTS idx_dramas_upcoming_premiere ON dramas(premiere_date) WHERE status = 'upcoming';
+
+-- from_status is NULL for the status a drama was created with
+CREATE TABLE IF NOT EXISTS drama_status_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    drama_id UUID NOT NULL REFERENCES dramas(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    changed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_drama_status_history_drama ON drama_status_history(drama_id, changed_at);
+
+-- Start every existing drama's history with its current status
+INSERT INTO drama_status_history (drama_id, from_status, to_status, changed_at)
+SELECT d.id, NULL, d.status, d.created_at
+FROM dramas d
+WHERE NOT EXISTS (SELECT 1 FROM drama_status_history h WHERE h.drama_id = d.id);
@@ -0,0 +1,30 @@
+-- Media gallery per drama: poster variants, backdrops, stills, trailers and teasers
+-- Run after 012_status_lifecycle.sql
+
+CREATE TABLE IF NOT EXISTS drama_media (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    drama_id UUID NOT NULL REFERENCES dramas(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('poster', 'backdrop', 'still', 'trailer', 'teaser')),
+    url TEXT NOT NULL,
+    title VARCHAR(255),
+    language VARCHAR(10),     -- e.g. ko, en; NULL when the image has no text
+    aspect_ratio VARCHAR(10), -- e.g. 2:3, 16:9
+    width INTEGER CHECK (width > 0),
+    height INTEGER CHECK (height > 0),
+    position INTEGER NOT NULL DEFAULT 0,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_drama_media_drama ON drama_media(drama_id, type, position);
+
+-- At most one primary item of each type per drama
+CREATE UNIQUE INDEX IF NOT EXISTS idx_drama_media_primary ON drama_media(drama_id, type) WHERE is_primary;
+
+-- Existing posters become the primary poster of their drama
+INSERT INTO drama_media (drama_id, type, url, is_primary, created_at)
+SELECT d.id, 'poster', d.poster_url, TRUE, d.created_at
+FROM dramas d
+WHERE d.poster_url IS NOT NULL AND d.poster_url <> ''
+  AND NOT EXISTS (SELECT 1 FROM drama_media m WHERE m.drama_id = d.id AND m.type = 'poster');
@@ -0,0 +1,25 @@
+-- Character names, billing order and cameo/guest credits for the cast
+-- Run after 013_drama_media.sql
+
+ALTER TABLE drama_actors ADD COLUMN IF NOT EXISTS character_name VARCHAR(150) NOT NULL DEFAULT '';
+ALTER TABLE drama_actors ADD COLUMN IF NOT EXISTS billing_order INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE drama_actors ADD COLUMN IF NOT EXISTS is_cameo BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE drama_actors ADD COLUMN IF NOT EXISTS is_guest BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- An actor may play several characters in the same drama; '' is an uncredited character
+ALTER TABLE drama_actors ALTER COLUMN drama_id SET NOT NULL;
+ALTER TABLE drama_actors ALTER COLUMN actor_id SET NOT NULL;
+ALTER TABLE drama_actors DROP CONSTRAINT IF EXISTS drama_actors_pkey;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_drama_actors_character ON drama_actors(drama_id, actor_id, character_name);
+CREATE INDEX IF NOT EXISTS idx_drama_actors_billing ON drama_actors(drama_id, billing_order);
+
+-- Bill existing cast main roles first, then by name
+UPDATE drama_actors da SET billing_order = b.n
+FROM (
+    SELECT da.drama_id, da.actor_id, da.character_name,
+           ROW_NUMBER() OVER (PARTITION BY da.drama_id ORDER BY CASE da.role WHEN 'main' THEN 0 ELSE 1 END, a.name) AS n
+    FROM drama_actors da
+    JOIN actors a ON a.id = da.actor_id
+) b
+WHERE da.drama_id = b.drama_id AND da.actor_id = b.actor_id AND da.character_name = b.character_name
+  AND da.billing_order = 0;
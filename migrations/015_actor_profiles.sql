@@ -0,0 +1,45 @@
+-- Actor profiles, photo galleries and fuzzy name search
+-- Run after 014_cast_characters.sql
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS native_name VARCHAR(100);  -- Name in its original script, e.g. 이민호
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS birth_date DATE;
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS gender VARCHAR(20);
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS nationality CHAR(2);       -- ISO 3166-1 alpha-2
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS biography TEXT;
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS agency VARCHAR(150);
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS height_cm INTEGER;
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS debut_year INTEGER;
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS social_links JSONB NOT NULL DEFAULT '{}'::jsonb; -- platform -> URL
+
+ALTER TABLE actors DROP CONSTRAINT IF EXISTS actors_gender_check;
+ALTER TABLE actors ADD CONSTRAINT actors_gender_check CHECK (gender IN ('female', 'male', 'non_binary', 'other'));
+ALTER TABLE actors DROP CONSTRAINT IF EXISTS actors_height_cm_check;
+ALTER TABLE actors ADD CONSTRAINT actors_height_cm_check CHECK (height_cm BETWEEN 50 AND 250);
+
+-- Trigram indexes back the fuzzy search, so "Lee Minho" still finds "Lee Min-ho"
+CREATE INDEX IF NOT EXISTS idx_actors_name_trgm ON actors USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_actors_native_name_trgm ON actors USING GIN (native_name gin_trgm_ops);
+
+CREATE TABLE IF NOT EXISTS actor_photos (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    actor_id UUID NOT NULL REFERENCES actors(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    caption VARCHAR(255),
+    position INTEGER NOT NULL DEFAULT 0,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_actor_photos_actor ON actor_photos(actor_id, position);
+
+-- At most one primary photo per actor; it mirrors actors.photo_url
+CREATE UNIQUE INDEX IF NOT EXISTS idx_actor_photos_primary ON actor_photos(actor_id) WHERE is_primary;
+
+-- Existing photos become the primary photo of their actor
+INSERT INTO actor_photos (actor_id, url, is_primary, created_at)
+SELECT a.id, a.photo_url, TRUE, a.created_at
+FROM actors a
+WHERE a.photo_url IS NOT NULL AND a.photo_url <> ''
+  AND NOT EXISTS (SELECT 1 FROM actor_photos p WHERE p.actor_id = a.id);
@@ -0,0 +1,17 @@
+-- Genre display order and parent genres
+-- Run after 015_actor_profiles.sql
+
+ALTER TABLE genres ADD COLUMN IF NOT EXISTS display_order INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE genres ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES genres(id) ON DELETE SET NULL;
+
+ALTER TABLE genres DROP CONSTRAINT IF EXISTS genres_parent_check;
+ALTER TABLE genres ADD CONSTRAINT genres_parent_check CHECK (parent_id IS NULL OR parent_id <> id);
+
+CREATE INDEX IF NOT EXISTS idx_genres_parent ON genres(parent_id) WHERE parent_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_drama_genres_genre ON drama_genres(genre_id);
+
+-- Keep the current alphabetical listing as the initial order
+UPDATE genres g SET display_order = o.n
+FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY name) AS n FROM genres) o
+WHERE g.id = o.id
+  AND NOT EXISTS (SELECT 1 FROM genres WHERE display_order <> 0);
@@ -0,0 +1,9 @@
+-- Country of origin and original language of dramas
+-- Run after 016_genre_hierarchy.sql
+
+-- Everything catalogued so far is Korean
+ALTER TABLE dramas ADD COLUMN IF NOT EXISTS country CHAR(2) NOT NULL DEFAULT 'KR';            -- ISO 3166-1 alpha-2
+ALTER TABLE dramas ADD COLUMN IF NOT EXISTS original_language VARCHAR(2) NOT NULL DEFAULT 'ko'; -- ISO 639-1
+
+CREATE INDEX IF NOT EXISTS idx_dramas_country ON dramas(country) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_dramas_original_language ON dramas(original_language) WHERE deleted_at IS NULL;
@@ -0,0 +1,47 @@
+-- Content advisories on dramas and episodes, and the advisories each user hides
+-- Run after 017_country_language.sql
+
+CREATE TABLE IF NOT EXISTS advisories (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(100) UNIQUE NOT NULL,
+    description TEXT,
+    display_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS drama_advisories (
+    drama_id UUID NOT NULL REFERENCES dramas(id) ON DELETE CASCADE,
+    advisory_id UUID NOT NULL REFERENCES advisories(id) ON DELETE CASCADE,
+    PRIMARY KEY (drama_id, advisory_id)
+);
+
+CREATE TABLE IF NOT EXISTS episode_advisories (
+    episode_id UUID NOT NULL REFERENCES episodes(id) ON DELETE CASCADE,
+    advisory_id UUID NOT NULL REFERENCES advisories(id) ON DELETE CASCADE,
+    PRIMARY KEY (episode_id, advisory_id)
+);
+
+-- Titles carrying any of these advisories are left out of the user's listings
+CREATE TABLE IF NOT EXISTS user_excluded_advisories (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    advisory_id UUID NOT NULL REFERENCES advisories(id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, advisory_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_drama_advisories_advisory ON drama_advisories(advisory_id);
+CREATE INDEX IF NOT EXISTS idx_episode_advisories_advisory ON episode_advisories(advisory_id);
+
+INSERT INTO advisories (name, slug, description, display_order) VALUES
+    ('Violence', 'violence', 'Physical violence, fights or injuries', 1),
+    ('Graphic violence', 'graphic-violence', 'Bloody or gory depictions of violence', 2),
+    ('Self-harm', 'self-harm', 'Depictions or discussion of self-harm', 3),
+    ('Suicide', 'suicide', 'Depictions or discussion of suicide', 4),
+    ('Sexual violence', 'sexual-violence', 'Sexual assault or abuse', 5),
+    ('Abuse', 'abuse', 'Domestic or child abuse, bullying', 6),
+    ('Substance use', 'substance-use', 'Drug or alcohol abuse', 7),
+    ('Flashing lights', 'flashing-lights', 'Strobe or flashing-light sequences that may affect photosensitive viewers', 8),
+    ('Strong language', 'strong-language', 'Frequent profanity', 9),
+    ('Nudity', 'nudity', 'Nudity or sexual content', 10),
+    ('Horror', 'horror', 'Frightening or disturbing imagery', 11)
+ON CONFLICT (slug) DO NOTHING;
@@ -0,0 +1,14 @@
+-- Deferrable season and episode numbering so reorders can swap numbers in one transaction
+-- Run after 018_content_advisories.sql
+
+-- INITIALLY IMMEDIATE keeps single-row writes checked per statement; reorders
+-- run SET CONSTRAINTS ... DEFERRED and are checked at commit instead.
+-- ON CONFLICT cannot use deferrable constraints, so upserts on these keys
+-- must update first and insert when nothing matched.
+ALTER TABLE seasons DROP CONSTRAINT IF EXISTS seasons_drama_id_season_number_key;
+ALTER TABLE seasons ADD CONSTRAINT seasons_drama_id_season_number_key
+    UNIQUE (drama_id, season_number) DEFERRABLE INITIALLY IMMEDIATE;
+
+ALTER TABLE episodes DROP CONSTRAINT IF EXISTS episodes_season_id_episode_number_key;
+ALTER TABLE episodes ADD CONSTRAINT episodes_season_id_episode_number_key
+    UNIQUE (season_id, episode_number) DEFERRABLE INITIALLY IMMEDIATE;
@@ -0,0 +1,26 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// RedirectSlug keeps oldSlug of a renamed entity reachable and drops any redirect
+// shadowing its new slug; it does nothing when the slug did not change
+func RedirectSlug(ctx context.Context, tx pgx.Tx, entityType, oldSlug, newSlug, entityID string) error {
+	if oldSlug == newSlug {
+		return nil
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO slug_redirects (entity_type, slug, entity_id, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (entity_type, slug) DO UPDATE SET entity_id = EXCLUDED.entity_id, created_at = EXCLUDED.created_at
+	`, entityType, oldSlug, entityID, time.Now())
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, "DELETE FROM slug_redirects WHERE entity_type = $1 AND slug = $2", entityType, newSlug)
+	return err
+}
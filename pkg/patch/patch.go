@@ -0,0 +1,44 @@
+package patch
+
+import (
+	"encoding/json"
+)
+
+// Field is a single member of a JSON Merge Patch (RFC 7396) document.
+// A member that is absent from the document leaves Set false, an explicit
+// null sets both Set and Null, anything else is decoded into Value.
+type Field[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+// UnmarshalJSON is only called for members present in the document
+func (f *Field[T]) UnmarshalJSON(data []byte) error {
+	f.Set = true
+	if string(data) == "null" {
+		f.Null = true
+		var zero T
+		f.Value = zero
+		return nil
+	}
+	return json.Unmarshal(data, &f.Value)
+}
+
+// ValidationValue exposes the field to the validator: a nil pointer when the
+// member is absent (so `omitnil` skips it), otherwise the value, which is the
+// zero value for null so that required fields still fail their rules
+func (f Field[T]) ValidationValue() interface{} {
+	if !f.Set {
+		return (*T)(nil)
+	}
+	return f.Value
+}
+
+// Column returns the value to store for a nullable column (nil for null)
+func (f Field[T]) Column() interface{} {
+	if f.Null {
+		return nil
+	}
+	return f.Value
+}
@@ -0,0 +1,36 @@
+package patch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Execer is a connection pool or a transaction
+type Execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// Update sets the columns present in fields on the row id of table. Only the
+// listed columns are written, in their order; table and columns come from the
+// caller's code, never from the request. Nothing runs when no column is present.
+func Update(ctx context.Context, db Execer, table string, columns []string, fields map[string]interface{}, id string) error {
+	sets := []string{}
+	args := []interface{}{}
+	for _, col := range columns {
+		if val, ok := fields[col]; ok {
+			args = append(args, val)
+			sets = append(sets, fmt.Sprintf("%s = $%d", col, len(args)))
+		}
+	}
+	if len(sets) == 0 {
+		return nil
+	}
+	args = append(args, id)
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d", table, strings.Join(sets, ", "), len(args))
+	_, err := db.Exec(ctx, query, args...)
+	return err
+}
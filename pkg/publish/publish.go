@@ -0,0 +1,50 @@
+package publish
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// ResolveAt checks the publish time required by a publication state:
+// scheduled needs a future time, published defaults to now
+func ResolveAt(publication string, publishAt *time.Time) (*time.Time, error) {
+	switch publication {
+	case "scheduled":
+		if publishAt == nil || !publishAt.After(time.Now()) {
+			return nil, errors.New("publish_at must be in the future")
+		}
+	case "published":
+		if publishAt == nil {
+			now := time.Now()
+			publishAt = &now
+		}
+	}
+	return publishAt, nil
+}
+
+// StartScheduler calls publishDue every interval until ctx is cancelled, logging
+// how many scheduled items (named by kind, e.g. "dramas") it published
+func StartScheduler(ctx context.Context, interval time.Duration, kind string, publishDue func(ctx context.Context, now time.Time) (int64, error)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				published, err := publishDue(ctx, time.Now())
+				if err != nil {
+					log.Printf("Publish scheduler for %s failed: %v", kind, err)
+					continue
+				}
+				if published > 0 {
+					log.Printf("📢 Published %d scheduled %s", published, kind)
+				}
+			}
+		}
+	}()
+}
@@ -1,10 +1,15 @@
 package validator
 
 import (
+	"drakor-backend/pkg/patch"
+	"errors"
+	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
+	"golang.org/x/text/language"
 )
 
 // Validate is the global validator instance
@@ -12,6 +17,35 @@ var Validate *validator.Validate
 
 func init() {
 	Validate = validator.New()
+	RegisterPatchTypes(
+		patch.Field[string]{},
+		patch.Field[int]{},
+		patch.Field[float64]{},
+		patch.Field[bool]{},
+		patch.Field[[]string]{},
+	)
+	Validate.RegisterValidation("iso639_1", isISO6391)
+}
+
+// isISO6391 accepts two-letter lower-case ISO 639-1 language codes such as ko or th
+func isISO6391(fl validator.FieldLevel) bool {
+	code := fl.Field().String()
+	if len(code) != 2 || strings.ToLower(code) != code {
+		return false
+	}
+	base, err := language.ParseBase(code)
+	return err == nil && base.String() == code
+}
+
+// RegisterPatchTypes lets `validate` tags apply to the value inside patch.Field types.
+// Use `omitnil` as the first tag so members missing from the patch are skipped.
+func RegisterPatchTypes(types ...interface{}) {
+	Validate.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if f, ok := field.Interface().(interface{ ValidationValue() interface{} }); ok {
+			return f.ValidationValue()
+		}
+		return nil
+	}, types...)
 }
 
 // ValidationError represents a validation error
@@ -96,3 +130,72 @@ func GenerateSlug(s string) string {
 	s = strings.Trim(s, "-")
 	return s
 }
+
+// IsUUID checks if the string is a canonical UUID
+func IsUUID(s string) bool {
+	uuidRegex := regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	return uuidRegex.MatchString(s)
+}
+
+// UniqueSlug returns the first free slug, trying the slug itself, then the slug
+// with each hint appended (e.g. a year), then numbered suffixes
+func UniqueSlug(slug string, hints []string, taken func(string) (bool, error)) (string, error) {
+	candidates := []string{slug}
+	for _, hint := range hints {
+		if hint != "" {
+			candidates = append(candidates, slug+"-"+GenerateSlug(hint))
+		}
+	}
+
+	for _, candidate := range candidates {
+		exists, err := taken(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+	}
+
+	// Fall back to numbering on top of the most specific candidate
+	base := candidates[len(candidates)-1]
+	for i := 2; ; i++ {
+		candidate := base + "-" + strconv.Itoa(i)
+		exists, err := taken(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+	}
+}
+
+// ResolveSlug validates a requested slug, failing when it is already taken, or,
+// when none was requested, generates a free one from source (or fallback when
+// source has no usable characters) via UniqueSlug
+func ResolveSlug(requested, source, fallback string, hints []string, taken func(string) (bool, error)) (string, error) {
+	if requested != "" {
+		slug := GenerateSlug(requested)
+		if slug == "" || IsUUID(slug) {
+			return "", errors.New("invalid slug")
+		}
+		exists, err := taken(slug)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			return "", errors.New("slug already exists")
+		}
+		return slug, nil
+	}
+
+	base := GenerateSlug(source)
+	if base == "" {
+		base = fallback
+	}
+	if base == "" {
+		return "", errors.New("invalid slug")
+	}
+	return UniqueSlug(base, hints, taken)
+}